@@ -0,0 +1,533 @@
+package gopengraph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/TheManticoreProject/gopengraph/edge"
+)
+
+// Structural analysis
+
+// FindEquivalentNodes groups nodes that play the same structural role in the graph.
+//
+// Two nodes are equivalent when they have the same kinds, the same in-degree and
+// out-degree, and the same multiset of neighbor kinds on their incoming and
+// outgoing edges. For example, all User nodes that are members of exactly one
+// Group and have no other relationships end up in the same class.
+//
+// Only edges whose endpoints are both nodes of the graph are considered.
+//
+// Returns:
+//
+//	[][]string: The equivalence classes. Each class lists node IDs in sorted order,
+//	            and classes are ordered by their first node ID. Every node belongs
+//	            to exactly one class.
+func (g *OpenGraph) FindEquivalentNodes() [][]string {
+	outgoing := make(map[string][]string)
+	incoming := make(map[string][]string)
+	for _, e := range g.edges {
+		start, startExists := g.nodes[e.GetStartNodeID()]
+		end, endExists := g.nodes[e.GetEndNodeID()]
+		if !startExists || !endExists {
+			continue
+		}
+		outgoing[start.GetID()] = append(outgoing[start.GetID()], kindSignature(end.GetKinds()))
+		incoming[end.GetID()] = append(incoming[end.GetID()], kindSignature(start.GetKinds()))
+	}
+
+	classes := make(map[string][]string)
+	for _, id := range g.sortedNodeIDs() {
+		out := append([]string{}, outgoing[id]...)
+		in := append([]string{}, incoming[id]...)
+		sort.Strings(out)
+		sort.Strings(in)
+
+		signature := strings.Join([]string{
+			kindSignature(g.nodes[id].GetKinds()),
+			"out:" + strings.Join(out, ";"),
+			"in:" + strings.Join(in, ";"),
+		}, "|")
+		classes[signature] = append(classes[signature], id)
+	}
+
+	result := make([][]string, 0, len(classes))
+	for _, class := range classes {
+		result = append(result, class)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i][0] < result[j][0]
+	})
+	return result
+}
+
+// FindIsolatedEdgePairs finds the node pairs that are connected only by the edges
+// running directly between them.
+//
+// For every pair of distinct nodes joined by at least one edge, in either
+// direction, the direct edges are set aside and the graph is searched for
+// another path between the two nodes, ignoring edge directions. When none
+// exists, the direct edges are the pair's only connection and are returned as a
+// group. Unlike a bridge, which is defined with respect to the connectivity of
+// the whole graph, this only considers the connectivity of the pair itself, so
+// a group may hold several parallel edges.
+//
+// Self-loops and edges whose endpoints are not both nodes of the graph are
+// ignored.
+//
+// Returns:
+//
+//	[][]*edge.Edge: The groups of edges, one per isolated node pair. Edges of a group
+//	                keep their insertion order, and groups are ordered by their
+//	                node pair. The result is empty if no pair is isolated.
+func (g *OpenGraph) FindIsolatedEdgePairs() [][]*edge.Edge {
+	neighbors := make(map[string]map[string]bool)
+	pairs := make(map[[2]string][]*edge.Edge)
+	for _, e := range g.edges {
+		start, end := e.GetStartNodeID(), e.GetEndNodeID()
+		if start == end {
+			continue
+		}
+		if _, exists := g.nodes[start]; !exists {
+			continue
+		}
+		if _, exists := g.nodes[end]; !exists {
+			continue
+		}
+
+		if neighbors[start] == nil {
+			neighbors[start] = make(map[string]bool)
+		}
+		if neighbors[end] == nil {
+			neighbors[end] = make(map[string]bool)
+		}
+		neighbors[start][end] = true
+		neighbors[end][start] = true
+
+		pair := [2]string{start, end}
+		if end < start {
+			pair = [2]string{end, start}
+		}
+		pairs[pair] = append(pairs[pair], e)
+	}
+
+	keys := make([][2]string, 0, len(pairs))
+	for pair := range pairs {
+		keys = append(keys, pair)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+
+	groups := make([][]*edge.Edge, 0)
+	for _, pair := range keys {
+		source, target := pair[0], pair[1]
+		visited := map[string]bool{source: true}
+		queue := []string{source}
+		connected := false
+		for len(queue) > 0 && !connected {
+			current := queue[0]
+			queue = queue[1:]
+			for next := range neighbors[current] {
+				// Skip the direct link between the pair.
+				if current == source && next == target {
+					continue
+				}
+				if next == target {
+					connected = true
+					break
+				}
+				if !visited[next] {
+					visited[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+		if !connected {
+			groups = append(groups, pairs[pair])
+		}
+	}
+	return groups
+}
+
+// GetAmbiguousEdges finds the node pairs connected by edges of more than one
+// kind, e.g. both MemberOf and HasSession, which may need review in data
+// quality workflows.
+//
+// Edges are considered in either direction, so a pair is keyed by its node IDs
+// in sorted order. Edges whose endpoints are not both nodes of the graph are
+// ignored.
+//
+// Returns:
+//
+//	map[[2]string][]string: The sorted, distinct edge kinds connecting each
+//	                        ambiguous pair, keyed by the pair of node IDs.
+func (g *OpenGraph) GetAmbiguousEdges() map[[2]string][]string {
+	kinds := make(map[[2]string]map[string]bool)
+	for _, e := range g.edges {
+		start, end := e.GetStartNodeID(), e.GetEndNodeID()
+		if _, exists := g.nodes[start]; !exists {
+			continue
+		}
+		if _, exists := g.nodes[end]; !exists {
+			continue
+		}
+
+		pair := [2]string{start, end}
+		if end < start {
+			pair = [2]string{end, start}
+		}
+		if kinds[pair] == nil {
+			kinds[pair] = make(map[string]bool)
+		}
+		kinds[pair][e.GetKind()] = true
+	}
+
+	ambiguous := make(map[[2]string][]string)
+	for pair, set := range kinds {
+		if len(set) < 2 {
+			continue
+		}
+		for kind := range set {
+			ambiguous[pair] = append(ambiguous[pair], kind)
+		}
+		sort.Strings(ambiguous[pair])
+	}
+	return ambiguous
+}
+
+// FindCyclesOfLength finds all directed cycles made of exactly length distinct
+// nodes.
+//
+// The search is a depth-first search from every node that never goes deeper
+// than length, which keeps it fast for short cycles such as triangles (length
+// 3). Each cycle is reported once, starting from its lexicographically smallest
+// node ID and following the edge directions; parallel edges do not produce
+// duplicate cycles. A length of 1 finds self-loops.
+//
+// Arguments:
+//
+//	length int: The number of nodes of the cycles to find.
+//
+// Returns:
+//
+//	[][]string: The cycles as node ID sequences, sorted lexicographically. The
+//	            slice is empty if there is no such cycle or length is below 1.
+func (g *OpenGraph) FindCyclesOfLength(length int) [][]string {
+	if length < 1 {
+		return make([][]string, 0)
+	}
+	return g.findCycles(g.successors(), length)
+}
+
+// FindCircularMemberships finds all the cycles formed by edges of a membership
+// kind, such as groups that are, directly or transitively, members of
+// themselves through "MemberOf" edges. Such recursive memberships are a common
+// data quality issue in Active Directory collections.
+//
+// Every elementary cycle is reported once, starting from its lexicographically
+// smallest node ID and following the edge directions. A self-loop is a cycle of
+// one node. The number of cycles can grow exponentially with the number of
+// nodes of a strongly connected group, so this is meant as a data quality check
+// rather than for densely connected graphs.
+//
+// Arguments:
+//
+//	membershipEdgeKind string: The kind of the membership edges, e.g. "MemberOf".
+//
+// Returns:
+//
+//	[][]string: The cycles as node ID sequences, sorted lexicographically, or an
+//	            empty slice if there is none.
+func (g *OpenGraph) FindCircularMemberships(membershipEdgeKind string) [][]string {
+	adjacency := g.successorsWhere(func(e *edge.Edge) bool {
+		return e.GetKind() == membershipEdgeKind
+	})
+	return g.findCycles(adjacency, 0)
+}
+
+// findCycles finds the elementary cycles of adjacency made of exactly length
+// nodes, or of any number of nodes if length is 0, with the depth-first search
+// described in FindCyclesOfLength.
+func (g *OpenGraph) findCycles(adjacency map[string][]string, length int) [][]string {
+	cycles := make([][]string, 0)
+	for _, start := range g.sortedNodeIDs() {
+		path := []string{start}
+		onPath := map[string]bool{start: true}
+
+		var visit func(current string)
+		visit = func(current string) {
+			for _, next := range adjacency[current] {
+				if next == start && (length == 0 || len(path) == length) {
+					cycles = append(cycles, append([]string{}, path...))
+					continue
+				}
+				// Only nodes greater than start are visited, so that every cycle
+				// is found from its smallest node only.
+				if next <= start || onPath[next] || len(path) == length {
+					continue
+				}
+				path = append(path, next)
+				onPath[next] = true
+				visit(next)
+				onPath[next] = false
+				path = path[:len(path)-1]
+			}
+		}
+		visit(start)
+	}
+
+	sort.Slice(cycles, func(i, j int) bool {
+		return strings.Join(cycles[i], "\x00") < strings.Join(cycles[j], "\x00")
+	})
+	return cycles
+}
+
+// GetTriangles finds all directed triangles of the graph.
+//
+// A triangle is a set of three distinct nodes forming a directed 3-cycle
+// (a -> b -> c -> a). The two orientations of a cycle over the same nodes, and
+// parallel edges, yield a single triangle. In privilege graphs, triangles point
+// at tightly coupled permissions that are hard to reduce.
+//
+// Returns:
+//
+//	[][3]string: The triangles, each as its node IDs in sorted order. Triangles are
+//	             sorted lexicographically, and the slice is empty if there is none.
+func (g *OpenGraph) GetTriangles() [][3]string {
+	triangles := make([][3]string, 0)
+	g.forEachTriangle(func(a, b, c string) {
+		triangle := [3]string{a, b, c}
+		sort.Strings(triangle[:])
+		triangles = append(triangles, triangle)
+	})
+
+	sort.Slice(triangles, func(i, j int) bool {
+		for k := 0; k < 3; k++ {
+			if triangles[i][k] != triangles[j][k] {
+				return triangles[i][k] < triangles[j][k]
+			}
+		}
+		return false
+	})
+	return triangles
+}
+
+// GetTriangleCount counts the directed triangles of the graph, as returned by
+// GetTriangles, without allocating them.
+//
+// The count is combinatorial: every edge a -> b is extended by the successors
+// of b and closed back to a, which takes O(V*E) time in the worst case and is
+// exact, unlike spectral estimates based on the adjacency matrix.
+//
+// Returns:
+//
+//	int: The number of triangles.
+func (g *OpenGraph) GetTriangleCount() int {
+	count := 0
+	g.forEachTriangle(func(a, b, c string) {
+		count++
+	})
+	return count
+}
+
+// GetCliques finds the maximal cliques of the graph, ignoring edge direction:
+// the sets of nodes joined two by two by an edge that no other node can
+// extend, e.g. groups of hosts that all trust each other.
+//
+// Cliques are enumerated with the Bron-Kerbosch algorithm with pivoting.
+// Self-loops and parallel edges are ignored, and an isolated node is a clique
+// of size 1. The number of maximal cliques can grow exponentially with the
+// number of nodes in the worst case.
+//
+// Arguments:
+//
+//	minSize int: The minimum number of nodes of the cliques to return. Values
+//	             below 1 are treated as 1.
+//
+// Returns:
+//
+//	[][]string: The cliques, each as its node IDs in sorted order. Cliques are
+//	            sorted lexicographically, and the slice is empty if there is none.
+func (g *OpenGraph) GetCliques(minSize int) [][]string {
+	adjacent := make(map[string]map[string]bool, len(g.nodes))
+	for id, neighbors := range g.neighbors() {
+		adjacent[id] = make(map[string]bool, len(neighbors))
+		for _, neighbor := range neighbors {
+			adjacent[id][neighbor] = true
+		}
+	}
+	intersect := func(ids []string, id string) []string {
+		kept := make([]string, 0, len(ids))
+		for _, other := range ids {
+			if adjacent[id][other] {
+				kept = append(kept, other)
+			}
+		}
+		return kept
+	}
+
+	cliques := make([][]string, 0)
+	var extend func(clique, candidates, excluded []string)
+	extend = func(clique, candidates, excluded []string) {
+		if len(candidates) == 0 && len(excluded) == 0 {
+			if len(clique) >= max(minSize, 1) {
+				found := append([]string{}, clique...)
+				sort.Strings(found)
+				cliques = append(cliques, found)
+			}
+			return
+		}
+
+		// Only the candidates not adjacent to the pivot need to be tried, as
+		// any clique containing a neighbor of the pivot is extended by it.
+		pivot, best := "", -1
+		for _, ids := range [][]string{candidates, excluded} {
+			for _, id := range ids {
+				if n := len(intersect(candidates, id)); n > best {
+					pivot, best = id, n
+				}
+			}
+		}
+
+		for _, id := range append([]string{}, candidates...) {
+			if adjacent[pivot][id] {
+				continue
+			}
+			extend(append(clique, id), intersect(candidates, id), intersect(excluded, id))
+			for i, candidate := range candidates {
+				if candidate == id {
+					candidates = append(candidates[:i:i], candidates[i+1:]...)
+					break
+				}
+			}
+			excluded = append(excluded, id)
+		}
+	}
+	extend(nil, g.sortedNodeIDs(), nil)
+
+	sort.Slice(cliques, func(i, j int) bool {
+		a, b := cliques[i], cliques[j]
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return cliques
+}
+
+// Graph metrics
+
+// GetAverageDegree computes the average degree of the nodes, counting both
+// incoming and outgoing edges: every edge adds one to the degree of each of its
+// two endpoints, so this is 2*E/N.
+//
+// Returns:
+//
+//	float64: The average degree, or 0 if the graph has fewer than two nodes.
+func (g *OpenGraph) GetAverageDegree() float64 {
+	if len(g.nodes) < 2 {
+		return 0
+	}
+	return 2 * float64(len(g.edges)) / float64(len(g.nodes))
+}
+
+// GetDensity computes the density of the graph as a directed graph: the ratio
+// of its edges to the N*(N-1) edges of a complete directed graph without
+// self-loops. Parallel edges and self-loops can push it above 1.
+//
+// Returns:
+//
+//	float64: The density, or 0 if the graph has fewer than two nodes.
+func (g *OpenGraph) GetDensity() float64 {
+	n := float64(len(g.nodes))
+	if n < 2 {
+		return 0
+	}
+	return float64(len(g.edges)) / (n * (n - 1))
+}
+
+// GetClusteringCoefficient computes the global clustering coefficient, or
+// transitivity, of the graph: the ratio of closed triples to connected triples,
+// where a connected triple is a node with two of its neighbors and it is closed
+// when these neighbors are also linked.
+//
+// Edge directions, parallel edges and self-loops are ignored, so a triangle
+// here does not need to be a directed cycle as in GetTriangles.
+//
+// Returns:
+//
+//	float64: The clustering coefficient, between 0 and 1, or 0 if the graph has
+//	         no connected triple.
+func (g *OpenGraph) GetClusteringCoefficient() float64 {
+	adjacency := g.neighbors()
+	linked := make(map[[2]string]bool)
+	for id, ids := range adjacency {
+		for _, other := range ids {
+			linked[[2]string{id, other}] = true
+		}
+	}
+
+	triples, closed := 0, 0
+	for _, ids := range adjacency {
+		for i := range ids {
+			for _, other := range ids[i+1:] {
+				triples++
+				if linked[[2]string{ids[i], other}] {
+					closed++
+				}
+			}
+		}
+	}
+	if triples == 0 {
+		return 0
+	}
+	return float64(closed) / float64(triples)
+}
+
+// kindSignature returns an order-independent representation of a set of kinds.
+func kindSignature(kinds []string) string {
+	sorted := append([]string{}, kinds...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// forEachTriangle calls fn once for every directed triangle of the graph, with
+// a the smallest node ID of the triangle and a -> b -> c -> a one of its
+// cycles.
+//
+// Every edge a -> b with a < b is extended by the successors c > a of b, so the
+// search runs in O(V*E) time without allocating the triangles.
+func (g *OpenGraph) forEachTriangle(fn func(a, b, c string)) {
+	adjacency := g.successors()
+	edges := make(map[[2]string]bool)
+	for start, ends := range adjacency {
+		for _, end := range ends {
+			edges[[2]string{start, end}] = true
+		}
+	}
+
+	for _, a := range g.sortedNodeIDs() {
+		for _, b := range adjacency[a] {
+			if b <= a {
+				continue
+			}
+			for _, c := range adjacency[b] {
+				if c <= a || c == b || !edges[[2]string{c, a}] {
+					continue
+				}
+				// When both orientations exist, the cycle a -> b -> c -> a is
+				// also found as a -> c -> b -> a: keep only one of them.
+				if b > c && edges[[2]string{a, c}] && edges[[2]string{c, b}] && edges[[2]string{b, a}] {
+					continue
+				}
+				fn(a, b, c)
+			}
+		}
+	}
+}
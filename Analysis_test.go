@@ -0,0 +1,358 @@
+package gopengraph_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+)
+
+func TestFindEquivalentNodes(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "bob", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "carol", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+	g.AddNode(newTestNode(t, "srv", []string{"Computer"}, nil))
+
+	g.AddEdge(newTestEdge(t, "alice", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "bob", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "carol", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "carol", "srv", "AdminTo"))
+
+	expected := [][]string{
+		{"admins"},
+		{"alice", "bob"},
+		{"carol"},
+		{"srv"},
+	}
+	if got := g.FindEquivalentNodes(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected classes %v, got %v", expected, got)
+	}
+}
+
+func TestFindEquivalentNodesDistinguishesOwnKinds(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "srv", []string{"Computer"}, nil))
+
+	classes := g.FindEquivalentNodes()
+	if len(classes) != 2 {
+		t.Errorf("Expected isolated nodes of different kinds to form 2 classes, got %v", classes)
+	}
+
+	if len(gopengraph.NewOpenGraph("").FindEquivalentNodes()) != 0 {
+		t.Error("Expected no classes for an empty graph")
+	}
+}
+
+func TestFindIsolatedEdgePairs(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+
+	// a, b and c form a triangle: every pair has an alternative path.
+	g.AddEdge(newTestEdge(t, "a", "b", "Knows"))
+	g.AddEdge(newTestEdge(t, "b", "c", "Knows"))
+	g.AddEdge(newTestEdge(t, "c", "a", "Knows"))
+	// c and d are only connected directly, through two parallel edges.
+	g.AddEdge(newTestEdge(t, "c", "d", "Knows"))
+	g.AddEdge(newTestEdge(t, "d", "c", "Trusts"))
+	// Self-loops and orphaned edges are ignored.
+	g.AddEdge(newTestEdge(t, "e", "e", "Knows"))
+	g.AddEdgeWithoutValidation(newTestEdge(t, "e", "missing", "Knows"))
+
+	groups := g.FindIsolatedEdgePairs()
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 isolated pair, got %d: %v", len(groups), groups)
+	}
+	if len(groups[0]) != 2 {
+		t.Fatalf("Expected 2 edges between c and d, got %v", groups[0])
+	}
+	if groups[0][0].GetKind() != "Knows" || groups[0][1].GetKind() != "Trusts" {
+		t.Errorf("Expected edges in insertion order, got %v", groups[0])
+	}
+}
+
+func TestFindIsolatedEdgePairsEmpty(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	if groups := g.FindIsolatedEdgePairs(); groups == nil || len(groups) != 0 {
+		t.Errorf("Expected an empty non-nil result, got %#v", groups)
+	}
+}
+
+func TestFindCyclesOfLength(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	// A triangle a -> b -> c -> a, a 4-cycle a -> b -> c -> d -> a, a 2-cycle
+	// between c and d and a self-loop on d.
+	g.AddEdge(newTestEdge(t, "a", "b", "Knows"))
+	g.AddEdge(newTestEdge(t, "a", "b", "Trusts"))
+	g.AddEdge(newTestEdge(t, "b", "c", "Knows"))
+	g.AddEdge(newTestEdge(t, "c", "a", "Knows"))
+	g.AddEdge(newTestEdge(t, "c", "d", "Knows"))
+	g.AddEdge(newTestEdge(t, "d", "a", "Knows"))
+	g.AddEdge(newTestEdge(t, "d", "c", "Knows"))
+	g.AddEdge(newTestEdge(t, "d", "d", "Knows"))
+
+	tests := []struct {
+		length   int
+		expected [][]string
+	}{
+		{1, [][]string{{"d"}}},
+		{2, [][]string{{"c", "d"}}},
+		{3, [][]string{{"a", "b", "c"}}},
+		{4, [][]string{{"a", "b", "c", "d"}}},
+		{5, [][]string{}},
+		{0, [][]string{}},
+	}
+	for _, tt := range tests {
+		if got := g.FindCyclesOfLength(tt.length); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Expected cycles of length %d to be %v, got %v", tt.length, tt.expected, got)
+		}
+	}
+}
+
+func TestFindCircularMemberships(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"alice", "g1", "g2", "g3", "g4"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	// g1 -> g2 -> g3 -> g1 and g2 -> g3 -> g2 are membership cycles, g4 is a
+	// member of itself, and g3 -> alice only closes a cycle through AdminTo.
+	g.AddEdge(newTestEdge(t, "alice", "g1", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "g1", "g2", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "g2", "g3", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "g3", "g1", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "g3", "g2", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "g4", "g4", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "g3", "alice", "AdminTo"))
+
+	expected := [][]string{{"g1", "g2", "g3"}, {"g2", "g3"}, {"g4"}}
+	if got := g.FindCircularMemberships("MemberOf"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	if got := g.FindCircularMemberships("HasSession"); got == nil || len(got) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %#v", got)
+	}
+}
+
+func TestGetTriangles(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	// a, b and c form a cycle in both orientations, with a parallel edge.
+	g.AddEdge(newTestEdge(t, "a", "b", "Knows"))
+	g.AddEdge(newTestEdge(t, "a", "b", "Trusts"))
+	g.AddEdge(newTestEdge(t, "b", "c", "Knows"))
+	g.AddEdge(newTestEdge(t, "c", "a", "Knows"))
+	g.AddEdge(newTestEdge(t, "a", "c", "Knows"))
+	g.AddEdge(newTestEdge(t, "c", "b", "Knows"))
+	g.AddEdge(newTestEdge(t, "b", "a", "Knows"))
+	// c, d and e form a cycle whose smallest node is not the edge start.
+	g.AddEdge(newTestEdge(t, "e", "c", "Knows"))
+	g.AddEdge(newTestEdge(t, "c", "d", "Knows"))
+	g.AddEdge(newTestEdge(t, "d", "e", "Knows"))
+	// a, b and f are connected, but not as a directed cycle.
+	g.AddEdge(newTestEdge(t, "f", "a", "Knows"))
+	g.AddEdge(newTestEdge(t, "f", "b", "Knows"))
+
+	expected := [][3]string{{"a", "b", "c"}, {"c", "d", "e"}}
+	if got := g.GetTriangles(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected triangles %v, got %v", expected, got)
+	}
+	if got := g.GetTriangleCount(); got != len(expected) {
+		t.Errorf("Expected GetTriangleCount to return %d, got %d", len(expected), got)
+	}
+
+	if got := gopengraph.NewOpenGraph("").GetTriangles(); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty non-nil slice, got %#v", got)
+	}
+}
+
+func TestGetTriangleCountMatchesCycles(t *testing.T) {
+	// A complete directed graph on 4 nodes has one triangle per set of 3 nodes.
+	g := gopengraph.NewOpenGraph("")
+	ids := []string{"a", "b", "c", "d"}
+	for _, id := range ids {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	for _, start := range ids {
+		for _, end := range ids {
+			if start != end {
+				g.AddEdge(newTestEdge(t, start, end, "Knows"))
+			}
+		}
+	}
+
+	if got := g.GetTriangleCount(); got != 4 {
+		t.Errorf("Expected 4 triangles, got %d", got)
+	}
+	if got := len(g.GetTriangles()); got != 4 {
+		t.Errorf("Expected GetTriangles to return 4 triangles, got %d", got)
+	}
+}
+
+func TestGetCliques(t *testing.T) {
+	t.Run("complete graph", func(t *testing.T) {
+		g := gopengraph.NewOpenGraph("")
+		ids := []string{"a", "b", "c", "d"}
+		for _, id := range ids {
+			g.AddNode(newTestNode(t, id, nil, nil))
+		}
+		for i, start := range ids {
+			for _, end := range ids[i+1:] {
+				g.AddEdge(newTestEdge(t, start, end, "Link"))
+			}
+		}
+		if got := g.GetCliques(2); !reflect.DeepEqual(got, [][]string{ids}) {
+			t.Errorf("Expected [[a b c d]], got %v", got)
+		}
+		if got := g.GetCliques(5); got == nil || len(got) != 0 {
+			t.Errorf("Expected no clique of 5 nodes, got %#v", got)
+		}
+	})
+
+	t.Run("path graph", func(t *testing.T) {
+		g := gopengraph.NewOpenGraph("")
+		for _, id := range []string{"a", "b", "c", "d"} {
+			g.AddNode(newTestNode(t, id, nil, nil))
+		}
+		g.AddEdge(newTestEdge(t, "a", "b", "Link"))
+		g.AddEdge(newTestEdge(t, "c", "b", "Link"))
+		g.AddEdge(newTestEdge(t, "c", "d", "Link"))
+		g.AddEdge(newTestEdge(t, "d", "c", "Link"))
+
+		expected := [][]string{{"a", "b"}, {"b", "c"}, {"c", "d"}}
+		if got := g.GetCliques(2); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("overlapping cliques", func(t *testing.T) {
+		// Triangles a-b-c and b-c-d share an edge, e is isolated.
+		g := gopengraph.NewOpenGraph("")
+		for _, id := range []string{"a", "b", "c", "d", "e"} {
+			g.AddNode(newTestNode(t, id, nil, nil))
+		}
+		for _, pair := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"b", "d"}, {"d", "c"}} {
+			g.AddEdge(newTestEdge(t, pair[0], pair[1], "Link"))
+		}
+
+		expected := [][]string{{"a", "b", "c"}, {"b", "c", "d"}, {"e"}}
+		if got := g.GetCliques(1); !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected %v, got %v", expected, got)
+		}
+		if got := g.GetCliques(3); !reflect.DeepEqual(got, expected[:2]) {
+			t.Errorf("Expected %v, got %v", expected[:2], got)
+		}
+	})
+
+	t.Run("empty graph", func(t *testing.T) {
+		for _, minSize := range []int{0, 1} {
+			if got := gopengraph.NewOpenGraph("").GetCliques(minSize); got == nil || len(got) != 0 {
+				t.Errorf("Expected an empty slice for minSize %d, got %#v", minSize, got)
+			}
+		}
+	})
+}
+
+func TestGraphMetrics(t *testing.T) {
+	t.Run("complete graph", func(t *testing.T) {
+		g := gopengraph.NewOpenGraph("")
+		ids := []string{"a", "b", "c", "d"}
+		for _, id := range ids {
+			g.AddNode(newTestNode(t, id, nil, nil))
+		}
+		for _, start := range ids {
+			for _, end := range ids {
+				if start != end {
+					g.AddEdge(newTestEdge(t, start, end, "Knows"))
+				}
+			}
+		}
+
+		if got := g.GetDensity(); got != 1 {
+			t.Errorf("Expected density 1, got %v", got)
+		}
+		if got := g.GetAverageDegree(); got != 6 {
+			t.Errorf("Expected average degree 6, got %v", got)
+		}
+		if got := g.GetClusteringCoefficient(); got != 1 {
+			t.Errorf("Expected clustering coefficient 1, got %v", got)
+		}
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		g := gopengraph.NewOpenGraph("")
+		for _, id := range []string{"root", "a", "b", "a1"} {
+			g.AddNode(newTestNode(t, id, nil, nil))
+		}
+		g.AddEdge(newTestEdge(t, "root", "a", "Contains"))
+		g.AddEdge(newTestEdge(t, "root", "b", "Contains"))
+		g.AddEdge(newTestEdge(t, "a", "a1", "Contains"))
+
+		if got := g.GetDensity(); got != 0.25 {
+			t.Errorf("Expected density 0.25, got %v", got)
+		}
+		if got := g.GetAverageDegree(); got != 1.5 {
+			t.Errorf("Expected average degree 1.5, got %v", got)
+		}
+		if got := g.GetClusteringCoefficient(); got != 0 {
+			t.Errorf("Expected clustering coefficient 0, got %v", got)
+		}
+	})
+
+	t.Run("triangle with a tail", func(t *testing.T) {
+		g := gopengraph.NewOpenGraph("")
+		for _, id := range []string{"a", "b", "c", "d"} {
+			g.AddNode(newTestNode(t, id, nil, nil))
+		}
+		g.AddEdge(newTestEdge(t, "a", "b", "Knows"))
+		g.AddEdge(newTestEdge(t, "b", "c", "Knows"))
+		g.AddEdge(newTestEdge(t, "a", "c", "Knows"))
+		g.AddEdge(newTestEdge(t, "c", "d", "Knows"))
+
+		// The triples centered on a and b are closed, while only one of the
+		// three centered on c is: 3 closed triples out of 5.
+		if got := g.GetClusteringCoefficient(); got != 0.6 {
+			t.Errorf("Expected clustering coefficient 0.6, got %v", got)
+		}
+	})
+
+	t.Run("empty and single-node graphs", func(t *testing.T) {
+		g := gopengraph.NewOpenGraph("")
+		if g.GetDensity() != 0 || g.GetAverageDegree() != 0 || g.GetClusteringCoefficient() != 0 {
+			t.Error("Expected zero metrics for an empty graph")
+		}
+		g.AddNode(newTestNode(t, "a", nil, nil))
+		if g.GetDensity() != 0 || g.GetAverageDegree() != 0 {
+			t.Error("Expected zero metrics for a single-node graph")
+		}
+	})
+}
+
+func TestGetAmbiguousEdges(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"alice", "bob", "admins", "dc"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "alice", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "admins", "alice", "Contains"))
+	g.AddEdge(newTestEdge(t, "alice", "dc", "HasSession"))
+	g.AddEdge(newTestEdge(t, "alice", "dc", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "alice", "dc", "CanRDP"))
+	g.AddEdge(newTestEdge(t, "bob", "admins", "MemberOf"))
+
+	want := map[[2]string][]string{
+		{"admins", "alice"}: {"Contains", "MemberOf"},
+		{"alice", "dc"}:     {"AdminTo", "CanRDP", "HasSession"},
+	}
+	if got := g.GetAmbiguousEdges(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
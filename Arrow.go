@@ -0,0 +1,257 @@
+package gopengraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/TheManticoreProject/gopengraph/node"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// Arrow exports
+
+// ExportNodesToArrow writes the nodes of the graph to w as an Apache Arrow IPC file.
+//
+// The record batch has an "id" column, a "kinds" list column, and one column per
+// property key observed across all nodes (sorted by key). Nodes that do not set a
+// property hold a null in that column. Nodes are written in ID order.
+//
+// Arguments:
+//
+//	w io.Writer: The writer to write the Arrow IPC file to.
+//
+// Returns:
+//
+//	error: An error if the schema could not be built or the file could not be written.
+func (g *OpenGraph) ExportNodesToArrow(w io.Writer) error {
+	ids := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	nodes := make([]*node.Node, 0, len(ids))
+	rows := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		n := g.nodes[id]
+		nodes = append(nodes, n)
+		rows = append(rows, n.GetProperties().GetAllProperties())
+	}
+
+	propertyFields, err := arrowPropertyFields(rows, "id", "kinds")
+	if err != nil {
+		return err
+	}
+
+	fields := []arrow.Field{
+		{Name: "id", Type: arrow.BinaryTypes.String},
+		{Name: "kinds", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+	}
+	fields = append(fields, propertyFields...)
+	schema := arrow.NewSchema(fields, nil)
+
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+
+	idBuilder := builder.Field(0).(*array.StringBuilder)
+	kindsBuilder := builder.Field(1).(*array.ListBuilder)
+	kindValues := kindsBuilder.ValueBuilder().(*array.StringBuilder)
+	for i, n := range nodes {
+		idBuilder.Append(n.GetID())
+		kindsBuilder.Append(true)
+		for _, kind := range n.GetKinds() {
+			kindValues.Append(kind)
+		}
+		if err := appendArrowProperties(builder, 2, propertyFields, rows[i]); err != nil {
+			return err
+		}
+	}
+
+	return writeArrowRecord(w, schema, builder)
+}
+
+// ExportEdgesToArrow writes the edges of the graph to w as an Apache Arrow IPC file.
+//
+// The record batch has "start", "end" and "kind" columns followed by one column per
+// property key observed across all edges (sorted by key). Edges are written in
+// insertion order.
+//
+// Arguments:
+//
+//	w io.Writer: The writer to write the Arrow IPC file to.
+//
+// Returns:
+//
+//	error: An error if the schema could not be built or the file could not be written.
+func (g *OpenGraph) ExportEdgesToArrow(w io.Writer) error {
+	rows := make([]map[string]interface{}, 0, len(g.edges))
+	for _, e := range g.edges {
+		rows = append(rows, e.GetProperties().GetAllProperties())
+	}
+
+	propertyFields, err := arrowPropertyFields(rows, "start", "end", "kind")
+	if err != nil {
+		return err
+	}
+
+	fields := []arrow.Field{
+		{Name: "start", Type: arrow.BinaryTypes.String},
+		{Name: "end", Type: arrow.BinaryTypes.String},
+		{Name: "kind", Type: arrow.BinaryTypes.String},
+	}
+	fields = append(fields, propertyFields...)
+	schema := arrow.NewSchema(fields, nil)
+
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+
+	for i, e := range g.edges {
+		builder.Field(0).(*array.StringBuilder).Append(e.GetStartNodeID())
+		builder.Field(1).(*array.StringBuilder).Append(e.GetEndNodeID())
+		builder.Field(2).(*array.StringBuilder).Append(e.GetKind())
+		if err := appendArrowProperties(builder, 3, propertyFields, rows[i]); err != nil {
+			return err
+		}
+	}
+
+	return writeArrowRecord(w, schema, builder)
+}
+
+// arrowPropertyFields infers one nullable Arrow field per property key found in
+// rows. Keys whose values are all booleans, all integers, all numbers or all
+// strings map to the matching Arrow type; anything else (slices, mixed types) is
+// stored as its JSON encoding in a string column. reserved lists the fixed column
+// names that a property key must not collide with.
+func arrowPropertyFields(rows []map[string]interface{}, reserved ...string) ([]arrow.Field, error) {
+	types := make(map[string]arrow.DataType)
+	for _, row := range rows {
+		for key, value := range row {
+			types[key] = mergeArrowType(types[key], arrowTypeOf(value))
+		}
+	}
+
+	keys := make([]string, 0, len(types))
+	for key := range types {
+		for _, name := range reserved {
+			if key == name {
+				return nil, fmt.Errorf("property key %q collides with a reserved Arrow column", key)
+			}
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]arrow.Field, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, arrow.Field{Name: key, Type: types[key], Nullable: true})
+	}
+	return fields, nil
+}
+
+// arrowTypeOf returns the Arrow type best suited to hold value.
+func arrowTypeOf(value interface{}) arrow.DataType {
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Bool:
+		return arrow.FixedWidthTypes.Boolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return arrow.PrimitiveTypes.Int64
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// Unsigned values beyond the int64 range widen the column to float64
+		// rather than wrapping around.
+		if reflect.ValueOf(value).Uint() > math.MaxInt64 {
+			return arrow.PrimitiveTypes.Float64
+		}
+		return arrow.PrimitiveTypes.Int64
+	case reflect.Float32, reflect.Float64:
+		return arrow.PrimitiveTypes.Float64
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+// mergeArrowType widens current so that it can also hold values of type next.
+func mergeArrowType(current, next arrow.DataType) arrow.DataType {
+	switch {
+	case current == nil || arrow.TypeEqual(current, next):
+		return next
+	case isArrowNumeric(current) && isArrowNumeric(next):
+		return arrow.PrimitiveTypes.Float64
+	default:
+		return arrow.BinaryTypes.String
+	}
+}
+
+func isArrowNumeric(t arrow.DataType) bool {
+	return arrow.TypeEqual(t, arrow.PrimitiveTypes.Int64) || arrow.TypeEqual(t, arrow.PrimitiveTypes.Float64)
+}
+
+// appendArrowProperties appends one row of property values to the builder fields
+// starting at offset, writing nulls for absent keys.
+func appendArrowProperties(builder *array.RecordBuilder, offset int, fields []arrow.Field, row map[string]interface{}) error {
+	for i, field := range fields {
+		fieldBuilder := builder.Field(offset + i)
+		value, exists := row[field.Name]
+		if !exists {
+			fieldBuilder.AppendNull()
+			continue
+		}
+
+		v := reflect.ValueOf(value)
+		switch b := fieldBuilder.(type) {
+		case *array.BooleanBuilder:
+			b.Append(v.Bool())
+		case *array.Int64Builder:
+			if v.CanInt() {
+				b.Append(v.Int())
+			} else {
+				b.Append(int64(v.Uint()))
+			}
+		case *array.Float64Builder:
+			switch {
+			case v.CanFloat():
+				b.Append(v.Float())
+			case v.CanInt():
+				b.Append(float64(v.Int()))
+			default:
+				b.Append(float64(v.Uint()))
+			}
+		case *array.StringBuilder:
+			if v.Kind() == reflect.String {
+				b.Append(v.String())
+				continue
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("failed to encode property %q: %w", field.Name, err)
+			}
+			b.Append(string(encoded))
+		default:
+			return fmt.Errorf("unsupported Arrow builder %T for property %q", fieldBuilder, field.Name)
+		}
+	}
+	return nil
+}
+
+// writeArrowRecord flushes the builder into a single record batch and writes it
+// to w as an Arrow IPC file.
+func writeArrowRecord(w io.Writer, schema *arrow.Schema, builder *array.RecordBuilder) error {
+	record := builder.NewRecordBatch()
+	defer record.Release()
+
+	writer, err := ipc.NewFileWriter(w, ipc.WithSchema(schema), ipc.WithAllocator(memory.DefaultAllocator))
+	if err != nil {
+		return fmt.Errorf("failed to create Arrow writer: %w", err)
+	}
+	if err := writer.Write(record); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write Arrow record: %w", err)
+	}
+	return writer.Close()
+}
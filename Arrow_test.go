@@ -0,0 +1,182 @@
+package gopengraph_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+
+	"github.com/TheManticoreProject/gopengraph"
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+	"github.com/TheManticoreProject/gopengraph/properties"
+)
+
+func readArrowRecord(t *testing.T, data []byte) arrow.RecordBatch {
+	t.Helper()
+	reader, err := ipc.NewFileReader(bytes.NewReader(data), ipc.WithAllocator(memory.DefaultAllocator))
+	if err != nil {
+		t.Fatalf("Failed to open Arrow file: %v", err)
+	}
+	defer reader.Close()
+
+	if reader.NumRecords() != 1 {
+		t.Fatalf("Expected 1 record batch, got %d", reader.NumRecords())
+	}
+	record, err := reader.RecordBatch(0)
+	if err != nil {
+		t.Fatalf("Failed to read record batch: %v", err)
+	}
+	record.Retain()
+	return record
+}
+
+func TestExportNodesToArrow(t *testing.T) {
+	g := gopengraph.NewOpenGraph("Base")
+
+	aliceProps := properties.NewProperties()
+	aliceProps.SetProperty("name", "ALICE")
+	aliceProps.SetProperty("age", 30)
+	aliceProps.SetProperty("enabled", true)
+	alice, _ := node.NewNode("1", []string{"User"}, aliceProps)
+
+	bobProps := properties.NewProperties()
+	bobProps.SetProperty("name", "BOB")
+	bobProps.SetProperty("age", 41.5)
+	bob, _ := node.NewNode("2", []string{"User"}, bobProps)
+
+	g.AddNode(bob)
+	g.AddNode(alice)
+
+	var buf bytes.Buffer
+	if err := g.ExportNodesToArrow(&buf); err != nil {
+		t.Fatalf("ExportNodesToArrow failed: %v", err)
+	}
+
+	record := readArrowRecord(t, buf.Bytes())
+	defer record.Release()
+
+	if record.NumRows() != 2 {
+		t.Fatalf("Expected 2 rows, got %d", record.NumRows())
+	}
+
+	schema := record.Schema()
+	expectedColumns := []string{"id", "kinds", "age", "enabled", "name"}
+	if len(schema.Fields()) != len(expectedColumns) {
+		t.Fatalf("Expected %d columns, got %d", len(expectedColumns), len(schema.Fields()))
+	}
+	for i, name := range expectedColumns {
+		if schema.Field(i).Name != name {
+			t.Errorf("Expected column %d to be %q, got %q", i, name, schema.Field(i).Name)
+		}
+	}
+
+	ids := record.Column(0).(*array.String)
+	if ids.Value(0) != "1" || ids.Value(1) != "2" {
+		t.Errorf("Expected nodes sorted by ID, got %q, %q", ids.Value(0), ids.Value(1))
+	}
+
+	kinds := record.Column(1).(*array.List)
+	start, end := kinds.ValueOffsets(0)
+	if end-start != 2 {
+		t.Errorf("Expected 2 kinds for node 1, got %d", end-start)
+	}
+
+	age, ok := record.Column(2).(*array.Float64)
+	if !ok {
+		t.Fatalf("Expected mixed int/float column to be float64, got %s", record.Column(2).DataType())
+	}
+	if age.Value(0) != 30 || age.Value(1) != 41.5 {
+		t.Errorf("Unexpected age values: %v, %v", age.Value(0), age.Value(1))
+	}
+
+	enabled := record.Column(3).(*array.Boolean)
+	if !enabled.Value(0) {
+		t.Error("Expected enabled to be true for node 1")
+	}
+	if !enabled.IsNull(1) {
+		t.Error("Expected enabled to be null for node 2")
+	}
+}
+
+func TestExportEdgesToArrow(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	n1, _ := node.NewNode("1", []string{"User"}, nil)
+	n2, _ := node.NewNode("2", []string{"Group"}, nil)
+	g.AddNode(n1)
+	g.AddNode(n2)
+
+	props := properties.NewProperties()
+	props.SetProperty("weight", 3)
+	props.SetProperty("tags", []string{"a", "b"})
+	e, _ := edge.NewEdge("1", "2", "MemberOf", props)
+	g.AddEdge(e)
+
+	var buf bytes.Buffer
+	if err := g.ExportEdgesToArrow(&buf); err != nil {
+		t.Fatalf("ExportEdgesToArrow failed: %v", err)
+	}
+
+	record := readArrowRecord(t, buf.Bytes())
+	defer record.Release()
+
+	if record.NumRows() != 1 {
+		t.Fatalf("Expected 1 row, got %d", record.NumRows())
+	}
+	if got := record.Column(0).(*array.String).Value(0); got != "1" {
+		t.Errorf("Expected start '1', got %q", got)
+	}
+	if got := record.Column(1).(*array.String).Value(0); got != "2" {
+		t.Errorf("Expected end '2', got %q", got)
+	}
+	if got := record.Column(2).(*array.String).Value(0); got != "MemberOf" {
+		t.Errorf("Expected kind 'MemberOf', got %q", got)
+	}
+	if got := record.Column(3).(*array.String).Value(0); got != `["a","b"]` {
+		t.Errorf("Expected slice property to be JSON encoded, got %q", got)
+	}
+	if got := record.Column(4).(*array.Int64).Value(0); got != 3 {
+		t.Errorf("Expected weight 3, got %d", got)
+	}
+}
+
+func TestExportToArrowReservedColumn(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	props := properties.NewProperties()
+	props.SetProperty("id", "shadow")
+	n, _ := node.NewNode("1", nil, props)
+	g.AddNode(n)
+
+	var buf bytes.Buffer
+	if err := g.ExportNodesToArrow(&buf); err == nil {
+		t.Error("Expected an error when a property collides with the id column")
+	}
+}
+
+func TestExportNodesToArrowLargeUint(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for id, value := range map[string]uint64{"1": 1 << 63, "2": 7} {
+		props := properties.NewProperties()
+		props.SetProperty("size", value)
+		n, _ := node.NewNode(id, nil, props)
+		g.AddNode(n)
+	}
+
+	var buf bytes.Buffer
+	if err := g.ExportNodesToArrow(&buf); err != nil {
+		t.Fatalf("ExportNodesToArrow failed: %v", err)
+	}
+	record := readArrowRecord(t, buf.Bytes())
+	defer record.Release()
+
+	size, ok := record.Column(2).(*array.Float64)
+	if !ok {
+		t.Fatalf("Expected a uint64 beyond the int64 range to widen the column to float64, got %s", record.Column(2).DataType())
+	}
+	if size.Value(0) != 1<<63 || size.Value(1) != 7 {
+		t.Errorf("Unexpected size values: %v, %v", size.Value(0), size.Value(1))
+	}
+}
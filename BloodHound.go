@@ -0,0 +1,275 @@
+package gopengraph
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+	"github.com/TheManticoreProject/gopengraph/properties"
+)
+
+// BloodHound CE imports
+
+// bloodHoundFiles maps the data files of a BloodHound CE collection that are
+// imported to the node kind of the objects they describe. sessions.json holds
+// relationships only and has no node kind.
+var bloodHoundFiles = []struct {
+	name string
+	kind string
+}{
+	{"users.json", "User"},
+	{"groups.json", "Group"},
+	{"computers.json", "Computer"},
+	{"sessions.json", ""},
+}
+
+// bloodHoundPrincipal references another object of the collection by SID.
+type bloodHoundPrincipal struct {
+	ObjectIdentifier string `json:"ObjectIdentifier"`
+	ObjectType       string `json:"ObjectType"`
+}
+
+// bloodHoundSession is a logged-on user session on a computer.
+type bloodHoundSession struct {
+	UserSID     string `json:"UserSID"`
+	ComputerSID string `json:"ComputerSID"`
+}
+
+// bloodHoundObject is an entry of the "data" array of a BloodHound CE data file.
+// Fields that do not apply to the object type are left empty.
+type bloodHoundObject struct {
+	ObjectIdentifier string                 `json:"ObjectIdentifier"`
+	Properties       map[string]interface{} `json:"Properties"`
+	PrimaryGroupSID  string                 `json:"PrimaryGroupSID"`
+	Aces             []struct {
+		PrincipalSID  string `json:"PrincipalSID"`
+		PrincipalType string `json:"PrincipalType"`
+		RightName     string `json:"RightName"`
+	} `json:"Aces"`
+	Members           []bloodHoundPrincipal `json:"Members"`
+	AllowedToDelegate []bloodHoundPrincipal `json:"AllowedToDelegate"`
+
+	Sessions           struct{ Results []bloodHoundSession } `json:"Sessions"`
+	PrivilegedSessions struct{ Results []bloodHoundSession } `json:"PrivilegedSessions"`
+	RegistrySessions   struct{ Results []bloodHoundSession } `json:"RegistrySessions"`
+
+	LocalAdmins        struct{ Results []bloodHoundPrincipal } `json:"LocalAdmins"`
+	RemoteDesktopUsers struct{ Results []bloodHoundPrincipal } `json:"RemoteDesktopUsers"`
+	DcomUsers          struct{ Results []bloodHoundPrincipal } `json:"DcomUsers"`
+	PSRemoteUsers      struct{ Results []bloodHoundPrincipal } `json:"PSRemoteUsers"`
+
+	// Entries of sessions.json are bare sessions.
+	bloodHoundSession
+}
+
+// bloodHoundRelationship is an edge extracted from a BloodHound CE object, kept
+// until all objects of the collection have been loaded as nodes.
+type bloodHoundRelationship struct {
+	start, startKind string
+	end, endKind     string
+	kind             string
+}
+
+// ImportFromBloodHoundZip reads a BloodHound Community Edition collection zip
+// and converts it into an OpenGraph.
+//
+// The files users.json, groups.json, computers.json and sessions.json are
+// imported; other files of the archive are ignored. File names may carry the
+// timestamp prefix added by the collectors (e.g. "20240101120000_users.json").
+//
+// Every object becomes a node identified by its ObjectIdentifier, with kind
+// User, Group or Computer and its Properties. Null, nested and mixed-type
+// property values are not valid OpenGraph properties and are skipped. The
+// following relationships are converted to edges, using BloodHound CE's edge
+// kinds:
+//
+//   - group members and primary groups: MemberOf
+//   - ACEs: the ACE right name (e.g. GenericAll) from the principal to the object
+//   - computer sessions: HasSession from the computer to the user
+//   - LocalAdmins, RemoteDesktopUsers, DcomUsers and PSRemoteUsers: AdminTo,
+//     CanRDP, ExecuteDCOM and CanPSRemote from the principal to the computer
+//   - constrained delegation: AllowedToDelegate
+//
+// Principals that are referenced but not part of the collection (e.g. built-in
+// groups) are added as nodes without properties, so that no edge is orphaned.
+//
+// Arguments:
+//
+//	zipPath string: The path of the BloodHound CE zip archive.
+//
+// Returns:
+//
+//	*OpenGraph: The imported graph.
+//	error: An error if the archive or one of its data files cannot be read.
+func ImportFromBloodHoundZip(zipPath string) (*OpenGraph, error) {
+	archive, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip '%s': %w", zipPath, err)
+	}
+	defer archive.Close()
+
+	g := NewOpenGraph("")
+	var relationships []bloodHoundRelationship
+
+	for _, file := range archive.File {
+		base := strings.ToLower(path.Base(file.Name))
+		for _, known := range bloodHoundFiles {
+			if base != known.name && !strings.HasSuffix(base, "_"+known.name) {
+				continue
+			}
+			objects, err := readBloodHoundFile(file)
+			if err != nil {
+				return nil, err
+			}
+			found, err := g.importBloodHoundObjects(objects, known.kind)
+			if err != nil {
+				return nil, fmt.Errorf("invalid object in '%s': %w", file.Name, err)
+			}
+			relationships = append(relationships, found...)
+		}
+	}
+
+	for _, r := range relationships {
+		if r.start == "" || r.end == "" {
+			continue
+		}
+		if err := g.ensureBloodHoundNode(r.start, r.startKind); err != nil {
+			return nil, err
+		}
+		if err := g.ensureBloodHoundNode(r.end, r.endKind); err != nil {
+			return nil, err
+		}
+		e, err := edge.NewEdge(r.start, r.end, r.kind, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid relationship (%s)-[%s]->(%s): %w", r.start, r.kind, r.end, err)
+		}
+		g.AddEdge(e)
+	}
+
+	return g, nil
+}
+
+// readBloodHoundFile decodes the "data" array of a BloodHound CE data file.
+func readBloodHoundFile(file *zip.File) ([]bloodHoundObject, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open '%s': %w", file.Name, err)
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read '%s': %w", file.Name, err)
+	}
+	// Collectors running on Windows may prefix their output with a UTF-8 BOM.
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var document struct {
+		Data []bloodHoundObject `json:"data"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse '%s': %w", file.Name, err)
+	}
+	return document.Data, nil
+}
+
+// importBloodHoundObjects adds the objects of a data file as nodes of the given
+// kind and returns the relationships they declare. When kind is empty the
+// objects are bare sessions and no node is added.
+func (g *OpenGraph) importBloodHoundObjects(objects []bloodHoundObject, kind string) ([]bloodHoundRelationship, error) {
+	var relationships []bloodHoundRelationship
+	addSessions := func(sessions []bloodHoundSession) {
+		for _, s := range sessions {
+			if s.UserSID != "" && s.ComputerSID != "" {
+				relationships = append(relationships, bloodHoundRelationship{s.ComputerSID, "Computer", s.UserSID, "User", "HasSession"})
+			}
+		}
+	}
+
+	for _, o := range objects {
+		if kind == "" {
+			addSessions([]bloodHoundSession{o.bloodHoundSession})
+			continue
+		}
+
+		props := properties.NewProperties()
+		for key, value := range o.Properties {
+			if props.IsPropertyValueValid(value) {
+				props.SetProperty(key, value)
+			}
+		}
+		if !props.HasProperty("objectid") {
+			props.SetProperty("objectid", o.ObjectIdentifier)
+		}
+
+		n, err := node.NewNode(o.ObjectIdentifier, []string{kind}, props)
+		if err != nil {
+			return nil, err
+		}
+		// Objects listed more than once keep their first definition.
+		g.AddNode(n)
+
+		id := o.ObjectIdentifier
+		if o.PrimaryGroupSID != "" {
+			relationships = append(relationships, bloodHoundRelationship{id, kind, o.PrimaryGroupSID, "Group", "MemberOf"})
+		}
+		for _, member := range o.Members {
+			relationships = append(relationships, bloodHoundRelationship{member.ObjectIdentifier, member.ObjectType, id, kind, "MemberOf"})
+		}
+		for _, ace := range o.Aces {
+			relationships = append(relationships, bloodHoundRelationship{ace.PrincipalSID, ace.PrincipalType, id, kind, ace.RightName})
+		}
+		for _, target := range o.AllowedToDelegate {
+			relationships = append(relationships, bloodHoundRelationship{id, kind, target.ObjectIdentifier, target.ObjectType, "AllowedToDelegate"})
+		}
+
+		addSessions(o.Sessions.Results)
+		addSessions(o.PrivilegedSessions.Results)
+		addSessions(o.RegistrySessions.Results)
+
+		for _, group := range []struct {
+			kind       string
+			principals []bloodHoundPrincipal
+		}{
+			{"AdminTo", o.LocalAdmins.Results},
+			{"CanRDP", o.RemoteDesktopUsers.Results},
+			{"ExecuteDCOM", o.DcomUsers.Results},
+			{"CanPSRemote", o.PSRemoteUsers.Results},
+		} {
+			for _, p := range group.principals {
+				relationships = append(relationships, bloodHoundRelationship{p.ObjectIdentifier, p.ObjectType, id, kind, group.kind})
+			}
+		}
+	}
+
+	return relationships, nil
+}
+
+// ensureBloodHoundNode adds a placeholder node for a principal referenced by a
+// relationship but not defined in the collection.
+func (g *OpenGraph) ensureBloodHoundNode(id, kind string) error {
+	if _, exists := g.nodes[id]; exists {
+		return nil
+	}
+
+	// Collectors report principals whose type could not be resolved as "Base".
+	var kinds []string
+	if kind != "" && kind != "Base" {
+		kinds = []string{kind}
+	}
+	props := properties.NewProperties()
+	props.SetProperty("objectid", id)
+
+	n, err := node.NewNode(id, kinds, props)
+	if err != nil {
+		return fmt.Errorf("invalid principal '%s': %w", id, err)
+	}
+	g.AddNode(n)
+	return nil
+}
@@ -0,0 +1,107 @@
+package gopengraph_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+)
+
+// writeBloodHoundZip writes a BloodHound CE collection archive holding files to
+// a temporary directory and returns its path, for archives the fixture under
+// testdata does not cover.
+func writeBloodHoundZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	zipPath := filepath.Join(t.TempDir(), "collection.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s to zip: %v", name, err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Failed to close zip: %v", err)
+	}
+	return zipPath
+}
+
+func TestImportFromBloodHoundZip(t *testing.T) {
+	// The fixture is a BloodHound CE collection of the CORP.LOCAL domain with
+	// two users, two groups and a computer.
+	zipPath := filepath.Join("testdata", "bloodhound_collection.zip")
+
+	g, err := gopengraph.ImportFromBloodHoundZip(zipPath)
+	if err != nil {
+		t.Fatalf("ImportFromBloodHoundZip failed: %v", err)
+	}
+
+	if got := g.GetNodeCount(); got != 6 {
+		t.Errorf("Expected 6 nodes, got %d", got)
+	}
+	if got := g.GetEdgeCount(); got != 10 {
+		t.Errorf("Expected 10 edges, got %d", got)
+	}
+
+	alice := g.GetNode("S-1-5-21-1-1104")
+	if alice == nil || !alice.HasKind("User") {
+		t.Fatalf("Expected user node S-1-5-21-1-1104, got %v", alice)
+	}
+	if got := alice.GetProperty("name"); got != "ALICE@CORP.LOCAL" {
+		t.Errorf("Expected name ALICE@CORP.LOCAL, got %v", got)
+	}
+	if alice.GetProperties().HasProperty("description") {
+		t.Error("Expected the null description property to be skipped")
+	}
+	if got := alice.GetProperty("objectid"); got != "S-1-5-21-1-1104" {
+		t.Errorf("Expected objectid to default to the object identifier, got %v", got)
+	}
+
+	placeholder := g.GetNode("S-1-5-21-1-515")
+	if placeholder == nil || !placeholder.HasKind("Group") {
+		t.Errorf("Expected placeholder group node S-1-5-21-1-515, got %v", placeholder)
+	}
+
+	for _, want := range []struct{ start, end, kind string }{
+		{"S-1-5-21-1-1104", "S-1-5-21-1-513", "MemberOf"},
+		{"S-1-5-21-1-1104", "S-1-5-21-1-512", "MemberOf"},
+		{"S-1-5-21-1-1001", "S-1-5-21-1-515", "MemberOf"},
+		{"S-1-5-21-1-512", "S-1-5-21-1-1104", "GenericAll"},
+		{"S-1-5-21-1-1105", "S-1-5-21-1-1001", "AllowedToDelegate"},
+		{"S-1-5-21-1-1001", "S-1-5-21-1-1105", "HasSession"},
+		{"S-1-5-21-1-1001", "S-1-5-21-1-1104", "HasSession"},
+		{"S-1-5-21-1-512", "S-1-5-21-1-1001", "AdminTo"},
+		{"S-1-5-21-1-1104", "S-1-5-21-1-1001", "CanRDP"},
+	} {
+		if !g.HasEdge(newTestEdge(t, want.start, want.end, want.kind)) {
+			t.Errorf("Expected edge (%s)-[%s]->(%s)", want.start, want.kind, want.end)
+		}
+	}
+
+	if errors := g.ValidateGraph(); len(errors) != 0 {
+		t.Errorf("Expected a valid graph, got %v", errors)
+	}
+}
+
+func TestImportFromBloodHoundZipErrors(t *testing.T) {
+	if _, err := gopengraph.ImportFromBloodHoundZip(filepath.Join(t.TempDir(), "missing.zip")); err == nil {
+		t.Error("Expected an error for a missing archive")
+	}
+
+	zipPath := writeBloodHoundZip(t, map[string]string{"users.json": `{"data": [`})
+	if _, err := gopengraph.ImportFromBloodHoundZip(zipPath); err == nil {
+		t.Error("Expected an error for a malformed data file")
+	}
+}
@@ -0,0 +1,96 @@
+package gopengraph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+// OpenGraphBuilder constructs an OpenGraph through method chaining.
+//
+// Nodes and edges are collected as they are added and only inserted into the
+// graph by Build, which allows edges to be declared before the nodes they
+// reference:
+//
+//	g, err := gopengraph.NewOpenGraphBuilder("Base").
+//		AddNode(bob).
+//		AddNode(alice).
+//		AddEdge(knows).
+//		Build()
+type OpenGraphBuilder struct {
+	sourceKind string
+	nodes      []*node.Node
+	edges      []*edge.Edge
+}
+
+// NewOpenGraphBuilder creates a new OpenGraphBuilder for a graph with the given source kind
+func NewOpenGraphBuilder(sourceKind string) *OpenGraphBuilder {
+	return &OpenGraphBuilder{
+		sourceKind: sourceKind,
+		nodes:      make([]*node.Node, 0),
+		edges:      make([]*edge.Edge, 0),
+	}
+}
+
+// AddNode queues a node to be added to the graph
+func (b *OpenGraphBuilder) AddNode(n *node.Node) *OpenGraphBuilder {
+	b.nodes = append(b.nodes, n)
+	return b
+}
+
+// AddEdge queues an edge to be added to the graph
+func (b *OpenGraphBuilder) AddEdge(e *edge.Edge) *OpenGraphBuilder {
+	b.edges = append(b.edges, e)
+	return b
+}
+
+// Build creates the OpenGraph from the queued nodes and edges.
+//
+// Nodes are added with AddNode, so the source kind is applied to them. Edges are
+// added after all nodes, and the resulting graph is validated for orphaned edges.
+//
+// Returns:
+//
+//	*OpenGraph: The constructed graph, nil on error.
+//	error: An error if a node or edge is nil or duplicated, or if an edge
+//	       references a node that was not added to the builder.
+func (b *OpenGraphBuilder) Build() (*OpenGraph, error) {
+	g := NewOpenGraph(b.sourceKind)
+
+	for _, n := range b.nodes {
+		if n == nil {
+			return nil, fmt.Errorf("cannot add a nil node")
+		}
+		if !g.AddNode(n) {
+			return nil, fmt.Errorf("duplicate node '%s'", n.GetID())
+		}
+	}
+
+	for _, e := range b.edges {
+		if e == nil {
+			return nil, fmt.Errorf("cannot add a nil edge")
+		}
+		if g.HasEdge(e) {
+			return nil, fmt.Errorf("duplicate edge %s", e)
+		}
+		g.AddEdgeWithoutValidation(e)
+	}
+
+	if errors := g.orphanedEdgeErrors(); len(errors) > 0 {
+		return nil, fmt.Errorf("graph has orphaned edges: %s", strings.Join(errors, "; "))
+	}
+
+	return g, nil
+}
+
+// MustBuild is like Build but panics if the graph cannot be built. It is
+// intended for tests and static graph declarations.
+func (b *OpenGraphBuilder) MustBuild() *OpenGraph {
+	g, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
@@ -0,0 +1,113 @@
+package gopengraph_test
+
+import (
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+func TestOpenGraphBuilderMinimalWorkingJSON(t *testing.T) {
+	bob, err := node.NewNodeBuilder().
+		WithID("123").
+		WithKind("Person").
+		WithKind("Base").
+		WithProperty("displayname", "bob").
+		WithProperty("property", "a").
+		WithProperty("objectid", "123").
+		WithProperty("name", "BOB").
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build node: %v", err)
+	}
+	alice, err := node.NewNodeBuilder().
+		WithID("234").
+		WithKind("Person").
+		WithKind("Base").
+		WithProperty("displayname", "alice").
+		WithProperty("property", "b").
+		WithProperty("objectid", "234").
+		WithProperty("name", "ALICE").
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build node: %v", err)
+	}
+	knows, err := edge.NewEdgeBuilder().FromNode("123").ToNode("234").WithKind("Knows").Build()
+	if err != nil {
+		t.Fatalf("Failed to build edge: %v", err)
+	}
+
+	built, err := gopengraph.NewOpenGraphBuilder("Base").
+		AddNode(bob).
+		AddNode(alice).
+		AddEdge(knows).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	expected := gopengraph.NewOpenGraph("")
+	if err := expected.FromJSONFile("example/minimal_working_json.json"); err != nil {
+		t.Fatalf("Failed to load example: %v", err)
+	}
+
+	if !built.Equal(expected) {
+		t.Errorf("Expected built graph %s to equal the example graph %s", built, expected)
+	}
+	if got := built.GetNode("123").GetProperty("name"); got != "BOB" {
+		t.Errorf("Expected node 123 to be named BOB, got %v", got)
+	}
+}
+
+func TestOpenGraphBuilderAppliesSourceKind(t *testing.T) {
+	g := gopengraph.NewOpenGraphBuilder("Base").
+		AddNode(newTestNode(t, "1", []string{"User"}, nil)).
+		MustBuild()
+	if !g.GetNode("1").HasKind("Base") {
+		t.Error("Expected the source kind to be added to built nodes")
+	}
+}
+
+func TestOpenGraphBuilderErrors(t *testing.T) {
+	t.Run("orphaned edge", func(t *testing.T) {
+		_, err := gopengraph.NewOpenGraphBuilder("").
+			AddNode(newTestNode(t, "1", nil, nil)).
+			AddEdge(newTestEdge(t, "1", "2", "Knows")).
+			Build()
+		if err == nil {
+			t.Error("Expected an error for an edge referencing a missing node")
+		}
+	})
+
+	t.Run("duplicate node", func(t *testing.T) {
+		_, err := gopengraph.NewOpenGraphBuilder("").
+			AddNode(newTestNode(t, "1", nil, nil)).
+			AddNode(newTestNode(t, "1", nil, nil)).
+			Build()
+		if err == nil {
+			t.Error("Expected an error for a duplicate node")
+		}
+	})
+
+	t.Run("duplicate edge", func(t *testing.T) {
+		_, err := gopengraph.NewOpenGraphBuilder("").
+			AddNode(newTestNode(t, "1", nil, nil)).
+			AddNode(newTestNode(t, "2", nil, nil)).
+			AddEdge(newTestEdge(t, "1", "2", "Knows")).
+			AddEdge(newTestEdge(t, "1", "2", "Knows")).
+			Build()
+		if err == nil {
+			t.Error("Expected an error for a duplicate edge")
+		}
+	})
+
+	t.Run("MustBuild panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected MustBuild to panic on an invalid graph")
+			}
+		}()
+		gopengraph.NewOpenGraphBuilder("").AddEdge(newTestEdge(t, "1", "2", "Knows")).MustBuild()
+	})
+}
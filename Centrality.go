@@ -0,0 +1,324 @@
+package gopengraph
+
+import (
+	"math"
+	"sort"
+
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+// Centrality metrics
+
+// Parameters used by GetNodeRank when computing PageRank.
+const (
+	pageRankDamping    = 0.85
+	pageRankIterations = 100
+)
+
+// NodeRankWeights controls the relative importance of each centrality metric
+// combined by GetNodeRank. A zero weight excludes the metric from the score.
+type NodeRankWeights struct {
+	DegreeWeight      float64
+	PageRankWeight    float64
+	BetweennessWeight float64
+}
+
+// degreeCentrality returns the degree centrality of every node, keyed by node
+// ID: its total degree divided by the number of other nodes in the graph. Only
+// edges whose endpoints are both nodes of the graph are counted.
+func (g *OpenGraph) degreeCentrality() map[string]float64 {
+	centrality := make(map[string]float64, len(g.nodes))
+	for id := range g.nodes {
+		centrality[id] = 0
+	}
+	if len(g.nodes) < 2 {
+		return centrality
+	}
+
+	in, out := g.nodeDegrees()
+	for id := range centrality {
+		centrality[id] = float64(in[id]+out[id]) / float64(len(g.nodes)-1)
+	}
+	return centrality
+}
+
+// pageRank computes the PageRank of every node, keyed by node ID, with the
+// given number of power iterations. The rank of dangling nodes is redistributed
+// evenly across all nodes, so the scores always sum to 1.
+func (g *OpenGraph) pageRank(damping float64, iterations int) map[string]float64 {
+	ids := g.sortedNodeIDs()
+	rank := make(map[string]float64, len(ids))
+	if len(ids) == 0 {
+		return rank
+	}
+
+	n := float64(len(ids))
+	for _, id := range ids {
+		rank[id] = 1 / n
+	}
+
+	adjacency := g.successors()
+	for i := 0; i < iterations; i++ {
+		dangling := 0.0
+		for _, id := range ids {
+			if len(adjacency[id]) == 0 {
+				dangling += rank[id]
+			}
+		}
+
+		next := make(map[string]float64, len(ids))
+		base := (1-damping)/n + damping*dangling/n
+		for _, id := range ids {
+			next[id] = base
+		}
+		for _, id := range ids {
+			targets := adjacency[id]
+			if len(targets) == 0 {
+				continue
+			}
+			share := damping * rank[id] / float64(len(targets))
+			for _, target := range targets {
+				next[target] += share
+			}
+		}
+		rank = next
+	}
+
+	return rank
+}
+
+// betweennessCentrality returns the betweenness centrality of every node, keyed
+// by node ID. It is computed with Brandes' algorithm over the directed graph and
+// normalized by (n-1)(n-2), the number of ordered pairs of other nodes.
+func (g *OpenGraph) betweennessCentrality() map[string]float64 {
+	ids := g.sortedNodeIDs()
+	betweenness := make(map[string]float64, len(ids))
+	for _, id := range ids {
+		betweenness[id] = 0
+	}
+
+	adjacency := g.successors()
+	for _, source := range ids {
+		var stack []string
+		predecessors := make(map[string][]string)
+		paths := map[string]float64{source: 1}
+		distance := map[string]int{source: 0}
+
+		queue := []string{source}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			stack = append(stack, current)
+
+			for _, next := range adjacency[current] {
+				if _, visited := distance[next]; !visited {
+					distance[next] = distance[current] + 1
+					queue = append(queue, next)
+				}
+				if distance[next] == distance[current]+1 {
+					paths[next] += paths[current]
+					predecessors[next] = append(predecessors[next], current)
+				}
+			}
+		}
+
+		dependency := make(map[string]float64)
+		for i := len(stack) - 1; i >= 0; i-- {
+			current := stack[i]
+			for _, previous := range predecessors[current] {
+				dependency[previous] += paths[previous] / paths[current] * (1 + dependency[current])
+			}
+			if current != source {
+				betweenness[current] += dependency[current]
+			}
+		}
+	}
+
+	if n := len(ids); n > 2 {
+		scale := float64((n - 1) * (n - 2))
+		for id := range betweenness {
+			betweenness[id] /= scale
+		}
+	}
+	return betweenness
+}
+
+// GetNodeRank returns a weighted composite centrality score for every node.
+//
+// Degree centrality (the total degree of a node divided by the number of other
+// nodes), PageRank (with a damping factor of 0.85 and 100 iterations) and
+// betweenness centrality over the directed graph are each rescaled to [0, 1] by
+// dividing by their maximum value, then combined as a weighted sum using
+// weights. Callers control the relative importance of each metric.
+//
+// Arguments:
+//
+//	weights NodeRankWeights: The weight applied to each centrality metric.
+//
+// Returns:
+//
+//	map[string]float64: The composite score of each node, keyed by node ID.
+func (g *OpenGraph) GetNodeRank(weights NodeRankWeights) map[string]float64 {
+	rank := make(map[string]float64, len(g.nodes))
+	for id := range g.nodes {
+		rank[id] = 0
+	}
+
+	metrics := []struct {
+		weight float64
+		scores func() map[string]float64
+	}{
+		{weights.DegreeWeight, g.degreeCentrality},
+		{weights.PageRankWeight, func() map[string]float64 {
+			return g.pageRank(pageRankDamping, pageRankIterations)
+		}},
+		{weights.BetweennessWeight, g.betweennessCentrality},
+	}
+
+	for _, metric := range metrics {
+		if metric.weight == 0 {
+			continue
+		}
+		scores := metric.scores()
+		maximum := 0.0
+		for _, score := range scores {
+			maximum = math.Max(maximum, score)
+		}
+		if maximum == 0 {
+			continue
+		}
+		for id, score := range scores {
+			rank[id] += metric.weight * score / maximum
+		}
+	}
+
+	return rank
+}
+
+// GetMostConnectedNodes returns the nodes with the highest total degree
+// (in-degree plus out-degree).
+//
+// Only edges whose endpoints are both nodes of the graph are counted. Nodes with
+// the same degree are ordered by ID, so the result is deterministic.
+//
+// Arguments:
+//
+//	n int: The maximum number of nodes to return. It is clamped to the node count.
+//
+// Returns:
+//
+//	[]*node.Node: Up to n nodes, by decreasing total degree.
+func (g *OpenGraph) GetMostConnectedNodes(n int) []*node.Node {
+	in, out := g.nodeDegrees()
+	return g.topNodesByDegree(n, func(id string) int { return in[id] + out[id] })
+}
+
+// GetMostConnectedSources returns the nodes with the highest out-degree.
+//
+// Degrees and ties are handled as in GetMostConnectedNodes.
+//
+// Arguments:
+//
+//	n int: The maximum number of nodes to return. It is clamped to the node count.
+//
+// Returns:
+//
+//	[]*node.Node: Up to n nodes, by decreasing out-degree.
+func (g *OpenGraph) GetMostConnectedSources(n int) []*node.Node {
+	_, out := g.nodeDegrees()
+	return g.topNodesByDegree(n, func(id string) int { return out[id] })
+}
+
+// GetMostConnectedTargets returns the nodes with the highest in-degree.
+//
+// Degrees and ties are handled as in GetMostConnectedNodes.
+//
+// Arguments:
+//
+//	n int: The maximum number of nodes to return. It is clamped to the node count.
+//
+// Returns:
+//
+//	[]*node.Node: Up to n nodes, by decreasing in-degree.
+func (g *OpenGraph) GetMostConnectedTargets(n int) []*node.Node {
+	in, _ := g.nodeDegrees()
+	return g.topNodesByDegree(n, func(id string) int { return in[id] })
+}
+
+// FindHubs returns the nodes whose total degree is well above the average.
+//
+// A node is a hub when its total degree (in-degree plus out-degree) exceeds
+// threshold times the average total degree of the graph. For example, a
+// threshold of 2.0 selects the nodes with more than twice the average number of
+// connections. Degrees are counted as in GetMostConnectedNodes.
+//
+// Arguments:
+//
+//	threshold float64: The multiple of the average degree a node must exceed.
+//
+// Returns:
+//
+//	[]*node.Node: The hubs, by decreasing total degree and then by ID. The slice is
+//	              empty if the graph has no edges.
+func (g *OpenGraph) FindHubs(threshold float64) []*node.Node {
+	in, out := g.nodeDegrees()
+	total := 0
+	for _, degree := range out {
+		total += degree
+	}
+	hubs := make([]*node.Node, 0)
+	if total == 0 {
+		return hubs
+	}
+
+	// Every counted edge adds one to the degree of each of its endpoints.
+	cutoff := threshold * float64(2*total) / float64(len(g.nodes))
+	degree := func(id string) int { return in[id] + out[id] }
+	for _, n := range g.topNodesByDegree(len(g.nodes), degree) {
+		if float64(degree(n.GetID())) <= cutoff {
+			break
+		}
+		hubs = append(hubs, n)
+	}
+	return hubs
+}
+
+// nodeDegrees counts the incoming and outgoing edges of every node. Only edges
+// whose endpoints are both nodes of the graph are counted.
+func (g *OpenGraph) nodeDegrees() (in, out map[string]int) {
+	in = make(map[string]int, len(g.nodes))
+	out = make(map[string]int, len(g.nodes))
+	for _, e := range g.edges {
+		start, end := e.GetStartNodeID(), e.GetEndNodeID()
+		if _, exists := g.nodes[start]; !exists {
+			continue
+		}
+		if _, exists := g.nodes[end]; !exists {
+			continue
+		}
+		out[start]++
+		in[end]++
+	}
+	return in, out
+}
+
+// topNodesByDegree returns up to n nodes by decreasing degree, breaking ties by
+// node ID.
+func (g *OpenGraph) topNodesByDegree(n int, degree func(id string) int) []*node.Node {
+	ids := g.sortedNodeIDs()
+	sort.SliceStable(ids, func(i, j int) bool {
+		return degree(ids[i]) > degree(ids[j])
+	})
+
+	if n > len(ids) {
+		n = len(ids)
+	}
+	if n < 0 {
+		n = 0
+	}
+	nodes := make([]*node.Node, 0, n)
+	for _, id := range ids[:n] {
+		nodes = append(nodes, g.nodes[id])
+	}
+	return nodes
+}
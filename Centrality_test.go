@@ -0,0 +1,147 @@
+package gopengraph_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestStarGraphCentrality(t *testing.T) {
+	// "a" reaches "b" and "c" only through "hub".
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"hub", "a", "b", "c"} {
+		g.AddNode(newTestNode(t, id, []string{"User"}, nil))
+	}
+	g.AddEdge(newTestEdge(t, "a", "hub", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "hub", "b", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "hub", "c", "AdminTo"))
+
+	t.Run("node rank", func(t *testing.T) {
+		t.Run("hub ranks first", func(t *testing.T) {
+			rank := g.GetNodeRank(gopengraph.NodeRankWeights{DegreeWeight: 1, PageRankWeight: 1, BetweennessWeight: 1})
+			for _, id := range []string{"a", "b", "c"} {
+				if rank["hub"] <= rank[id] {
+					t.Errorf("Expected hub to outrank %s, got hub=%v %s=%v", id, rank["hub"], id, rank[id])
+				}
+			}
+		})
+
+		t.Run("degree centrality", func(t *testing.T) {
+			// hub has a degree of 3 and the leaves of 1, out of 3 other nodes.
+			rank := g.GetNodeRank(gopengraph.NodeRankWeights{DegreeWeight: 1})
+			if !almostEqual(rank["hub"], 1) {
+				t.Errorf("Expected hub rank 1, got %v", rank["hub"])
+			}
+			if !almostEqual(rank["a"], 1.0/3) {
+				t.Errorf("Expected leaf rank 1/3, got %v", rank["a"])
+			}
+		})
+
+		t.Run("pagerank", func(t *testing.T) {
+			rank := g.GetNodeRank(gopengraph.NodeRankWeights{PageRankWeight: 1})
+			if rank["hub"] <= rank["a"] {
+				t.Errorf("Expected hub to outrank a, got hub=%v a=%v", rank["hub"], rank["a"])
+			}
+		})
+
+		t.Run("betweenness centrality", func(t *testing.T) {
+			// Only hub lies on shortest paths between other nodes, a->b and a->c.
+			rank := g.GetNodeRank(gopengraph.NodeRankWeights{BetweennessWeight: 1})
+			if !almostEqual(rank["hub"], 1) {
+				t.Errorf("Expected hub rank 1, got %v", rank["hub"])
+			}
+			for _, id := range []string{"a", "b", "c"} {
+				if rank[id] != 0 {
+					t.Errorf("Expected leaf %s rank 0, got %v", id, rank[id])
+				}
+			}
+		})
+
+		t.Run("single metric is rescaled to its maximum", func(t *testing.T) {
+			rank := g.GetNodeRank(gopengraph.NodeRankWeights{DegreeWeight: 2})
+			if !almostEqual(rank["hub"], 2) {
+				t.Errorf("Expected hub rank 2, got %v", rank["hub"])
+			}
+			if !almostEqual(rank["a"], 2.0/3) {
+				t.Errorf("Expected leaf rank 2/3, got %v", rank["a"])
+			}
+		})
+
+		t.Run("empty graph", func(t *testing.T) {
+			weights := gopengraph.NodeRankWeights{DegreeWeight: 1, PageRankWeight: 1, BetweennessWeight: 1}
+			if rank := gopengraph.NewOpenGraph("").GetNodeRank(weights); len(rank) != 0 {
+				t.Errorf("Expected no score for an empty graph, got %v", rank)
+			}
+		})
+
+		t.Run("zero weights give zero scores", func(t *testing.T) {
+			rank := g.GetNodeRank(gopengraph.NodeRankWeights{})
+			if len(rank) != 4 {
+				t.Fatalf("Expected a score for each of the 4 nodes, got %d", len(rank))
+			}
+			for id, score := range rank {
+				if score != 0 {
+					t.Errorf("Expected score 0 for %s, got %v", id, score)
+				}
+			}
+		})
+	})
+
+	t.Run("most connected nodes", func(t *testing.T) {
+		if got := nodeIDs(g.GetMostConnectedNodes(2)); !reflect.DeepEqual(got, []string{"hub", "a"}) {
+			t.Errorf("Expected [hub a], got %v", got)
+		}
+		if got := nodeIDs(g.GetMostConnectedSources(10)); !reflect.DeepEqual(got, []string{"hub", "a", "b", "c"}) {
+			t.Errorf("Expected n to be clamped to the node count, got %v", got)
+		}
+		// hub, b and c all have an in-degree of 1 and are ordered by ID.
+		if got := nodeIDs(g.GetMostConnectedTargets(4)); !reflect.DeepEqual(got, []string{"b", "c", "hub", "a"}) {
+			t.Errorf("Expected [b c hub a], got %v", got)
+		}
+		if got := g.GetMostConnectedNodes(0); len(got) != 0 {
+			t.Errorf("Expected no nodes for n = 0, got %v", got)
+		}
+	})
+
+	t.Run("hubs", func(t *testing.T) {
+		// The average degree is 6/4 = 1.5: only the hub, with a degree of 3, is above
+		// 1.5 times the average.
+		if got := nodeIDs(g.FindHubs(1.5)); !reflect.DeepEqual(got, []string{"hub"}) {
+			t.Errorf("Expected [hub], got %v", got)
+		}
+		if got := nodeIDs(g.FindHubs(2.0)); len(got) != 0 {
+			t.Errorf("Expected no hub with a degree above 3, got %v", got)
+		}
+		if got := nodeIDs(g.FindHubs(0.5)); !reflect.DeepEqual(got, []string{"hub", "a", "b", "c"}) {
+			t.Errorf("Expected every node above half the average, got %v", got)
+		}
+
+		if got := gopengraph.NewOpenGraph("").FindHubs(1); got == nil || len(got) != 0 {
+			t.Errorf("Expected an empty non-nil slice for an empty graph, got %#v", got)
+		}
+	})
+}
+
+func TestGetMostConnectedNodesBalanced(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"d", "c", "b", "a"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	// A directed cycle: every node has an in-degree and out-degree of 1.
+	g.AddEdge(newTestEdge(t, "a", "b", "Knows"))
+	g.AddEdge(newTestEdge(t, "b", "c", "Knows"))
+	g.AddEdge(newTestEdge(t, "c", "d", "Knows"))
+	g.AddEdge(newTestEdge(t, "d", "a", "Knows"))
+
+	for i := 0; i < 5; i++ {
+		if got := nodeIDs(g.GetMostConnectedNodes(3)); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+			t.Fatalf("Expected ties to be broken by ID, got %v", got)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package gopengraph
+
+import "context"
+
+// contextKey is the type of the key under which StoreInContext stores a graph.
+// Being unexported, it cannot collide with keys defined by other packages.
+type contextKey struct{}
+
+// Context integration
+
+// StoreInContext returns a copy of ctx carrying the graph, so that functions
+// further down a call chain (e.g. HTTP handlers behind a middleware) can
+// retrieve it with LoadFromContext instead of receiving it as a parameter.
+//
+// Arguments:
+//
+//	ctx context.Context: The parent context.
+//
+// Returns:
+//
+//	context.Context: A context derived from ctx that carries the graph.
+func (g *OpenGraph) StoreInContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, g)
+}
+
+// LoadFromContext retrieves the graph stored in ctx by StoreInContext.
+//
+// Arguments:
+//
+//	ctx context.Context: The context to read the graph from.
+//
+// Returns:
+//
+//	*OpenGraph: The stored graph, nil if ctx carries none.
+//	bool: True if a graph was found.
+func LoadFromContext(ctx context.Context) (*OpenGraph, bool) {
+	g, ok := ctx.Value(contextKey{}).(*OpenGraph)
+	return g, ok && g != nil
+}
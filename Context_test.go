@@ -0,0 +1,38 @@
+package gopengraph_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+)
+
+func TestStoreAndLoadFromContext(t *testing.T) {
+	g := gopengraph.NewOpenGraph("Base")
+	ctx := g.StoreInContext(context.Background())
+
+	loaded, ok := gopengraph.LoadFromContext(ctx)
+	if !ok || loaded != g {
+		t.Errorf("Expected to load the stored graph, got %v (ok=%v)", loaded, ok)
+	}
+
+	// The graph is visible from derived contexts.
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if loaded, ok := gopengraph.LoadFromContext(child); !ok || loaded != g {
+		t.Errorf("Expected to load the graph from a derived context, got %v (ok=%v)", loaded, ok)
+	}
+}
+
+func TestLoadFromContextMissing(t *testing.T) {
+	if g, ok := gopengraph.LoadFromContext(context.Background()); ok || g != nil {
+		t.Errorf("Expected no graph in an empty context, got %v (ok=%v)", g, ok)
+	}
+
+	// A nil graph is reported as missing.
+	var nilGraph *gopengraph.OpenGraph
+	ctx := nilGraph.StoreInContext(context.Background())
+	if _, ok := gopengraph.LoadFromContext(ctx); ok {
+		t.Error("Expected a stored nil graph to be reported as missing")
+	}
+}
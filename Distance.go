@@ -0,0 +1,222 @@
+package gopengraph
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+// Distances
+//
+// Distances are shortest path lengths, in number of edges, with edges followed
+// in either direction, so that they are defined within every connected
+// component as returned by GetConnectedComponents.
+
+// GetGraphDiameter computes the diameter of the graph: the greatest distance
+// between two of its nodes.
+//
+// A breadth-first search is run from every node, so this takes O(N*(N+E)) time;
+// see GetApproximateDiameter for large graphs. A disconnected graph has an
+// infinite diameter, which is reported as an error.
+//
+// Returns:
+//
+//	diameter int: The diameter of the graph.
+//	fromID string: The ID of a node at one end of a longest shortest path.
+//	toID string: The ID of the node at the other end of that path.
+//	err error: An error if the graph is empty or disconnected.
+func (g *OpenGraph) GetGraphDiameter() (diameter int, fromID string, toID string, err error) {
+	if len(g.nodes) == 0 {
+		return 0, "", "", fmt.Errorf("graph is empty")
+	}
+
+	adjacency := g.neighbors()
+	diameter = -1
+	for _, id := range g.sortedNodeIDs() {
+		eccentricity, farthest, connected := g.eccentricity(id, adjacency)
+		if !connected {
+			return 0, "", "", fmt.Errorf("graph is disconnected: diameter is infinite")
+		}
+		if eccentricity > diameter {
+			diameter, fromID, toID = eccentricity, id, farthest
+		}
+	}
+	return diameter, fromID, toID, nil
+}
+
+// GetApproximateDiameter estimates the diameter of the graph from the
+// breadth-first searches of up to samples randomly chosen nodes.
+//
+// The estimate is the greatest eccentricity among the sampled nodes, so it
+// never exceeds the actual diameter, and it is exact when samples is at least
+// the number of nodes.
+//
+// Arguments:
+//
+//	samples int: The number of nodes to start a search from.
+//
+// Returns:
+//
+//	int: The estimated diameter of the graph.
+//	error: An error if samples is not positive, or if the graph is empty or
+//	       disconnected.
+func (g *OpenGraph) GetApproximateDiameter(samples int) (int, error) {
+	if samples < 1 {
+		return 0, fmt.Errorf("samples must be positive, got %d", samples)
+	}
+	if len(g.nodes) == 0 {
+		return 0, fmt.Errorf("graph is empty")
+	}
+
+	ids := g.sortedNodeIDs()
+	if samples < len(ids) {
+		rand.Shuffle(len(ids), func(i, j int) {
+			ids[i], ids[j] = ids[j], ids[i]
+		})
+		ids = ids[:samples]
+	}
+
+	adjacency := g.neighbors()
+	diameter := 0
+	for _, id := range ids {
+		eccentricity, _, connected := g.eccentricity(id, adjacency)
+		if !connected {
+			return 0, fmt.Errorf("graph is disconnected: diameter is infinite")
+		}
+		diameter = max(diameter, eccentricity)
+	}
+	return diameter, nil
+}
+
+// GetEccentricity computes the eccentricity of a node: its greatest distance to
+// any other node of the graph.
+//
+// Arguments:
+//
+//	id string: The ID of the node.
+//
+// Returns:
+//
+//	int: The eccentricity of the node, or -1 if some nodes of the graph cannot
+//	     be reached from it, i.e. the graph is disconnected.
+//	error: An error if the node does not exist.
+func (g *OpenGraph) GetEccentricity(id string) (int, error) {
+	if _, exists := g.nodes[id]; !exists {
+		return 0, fmt.Errorf("node '%s' not found", id)
+	}
+	eccentricity, _, connected := g.eccentricity(id, g.neighbors())
+	if !connected {
+		return -1, nil
+	}
+	return eccentricity, nil
+}
+
+// GetRadius computes the radius of the graph: the smallest eccentricity of its
+// nodes.
+//
+// Returns:
+//
+//	int: The radius of the graph.
+//	error: An error if the graph is empty or disconnected.
+func (g *OpenGraph) GetRadius() (int, error) {
+	radius, _, err := g.center()
+	return radius, err
+}
+
+// GetCenter returns the center of the graph: the nodes whose eccentricity is
+// the radius of the graph. In a chain, this is its middle node or nodes.
+//
+// Returns:
+//
+//	[]*node.Node: The center nodes, in ID order.
+//	error: An error if the graph is empty or disconnected.
+func (g *OpenGraph) GetCenter() ([]*node.Node, error) {
+	_, ids, err := g.center()
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*node.Node, 0, len(ids))
+	for _, id := range ids {
+		nodes = append(nodes, g.nodes[id])
+	}
+	return nodes, nil
+}
+
+// center returns the radius of the graph and the IDs of its center nodes, in
+// sorted order.
+func (g *OpenGraph) center() (int, []string, error) {
+	if len(g.nodes) == 0 {
+		return 0, nil, fmt.Errorf("graph is empty")
+	}
+
+	adjacency := g.neighbors()
+	radius := -1
+	var ids []string
+	for _, id := range g.sortedNodeIDs() {
+		eccentricity, _, connected := g.eccentricity(id, adjacency)
+		if !connected {
+			return 0, nil, fmt.Errorf("graph is disconnected: eccentricities are infinite")
+		}
+		switch {
+		case radius == -1 || eccentricity < radius:
+			radius, ids = eccentricity, []string{id}
+		case eccentricity == radius:
+			ids = append(ids, id)
+		}
+	}
+	return radius, ids, nil
+}
+
+// neighbors returns, for every node, the IDs of the other nodes it shares an
+// edge with in either direction, in sorted order and without duplicates.
+// Self-loops and edges whose endpoints are not both nodes of the graph are
+// ignored.
+func (g *OpenGraph) neighbors() map[string][]string {
+	adjacency := make(map[string][]string, len(g.nodes))
+	seen := make(map[[2]string]bool)
+	link := func(a, b string) {
+		if !seen[[2]string{a, b}] {
+			seen[[2]string{a, b}] = true
+			adjacency[a] = append(adjacency[a], b)
+		}
+	}
+	successors := g.successors()
+	for _, id := range g.sortedNodeIDs() {
+		for _, next := range successors[id] {
+			if next != id {
+				link(id, next)
+				link(next, id)
+			}
+		}
+	}
+	for id := range adjacency {
+		sort.Strings(adjacency[id])
+	}
+	return adjacency
+}
+
+// eccentricity runs a breadth-first search from id over adjacency and returns
+// the greatest distance reached, the smallest ID of a node at that distance and
+// whether every node of the graph was reached.
+func (g *OpenGraph) eccentricity(id string, adjacency map[string][]string) (int, string, bool) {
+	distances := map[string]int{id: 0}
+	queue := []string{id}
+	eccentricity, farthest := 0, id
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[current] {
+			if _, visited := distances[next]; visited {
+				continue
+			}
+			distances[next] = distances[current] + 1
+			if distances[next] > eccentricity || (distances[next] == eccentricity && next < farthest) {
+				eccentricity, farthest = distances[next], next
+			}
+			queue = append(queue, next)
+		}
+	}
+	return eccentricity, farthest, len(distances) == len(g.nodes)
+}
@@ -0,0 +1,127 @@
+package gopengraph_test
+
+import (
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+)
+
+func TestGraphDistances(t *testing.T) {
+	// Each subtest gets its own chain a -> b -> c -> d -> e, as some extend it.
+	newChain := func(t *testing.T) *gopengraph.OpenGraph {
+		t.Helper()
+		g := gopengraph.NewOpenGraph("")
+		ids := []string{"a", "b", "c", "d", "e"}
+		for _, id := range ids {
+			g.AddNode(newTestNode(t, id, nil, nil))
+		}
+		for i := 1; i < len(ids); i++ {
+			g.AddEdge(newTestEdge(t, ids[i-1], ids[i], "Knows"))
+		}
+		return g
+	}
+
+	t.Run("diameter", func(t *testing.T) {
+		g := newChain(t)
+
+		diameter, from, to, err := g.GetGraphDiameter()
+		if err != nil {
+			t.Fatalf("GetGraphDiameter failed: %v", err)
+		}
+		if diameter != 4 || from != "a" || to != "e" {
+			t.Errorf("Expected diameter 4 from a to e, got %d from %s to %s", diameter, from, to)
+		}
+
+		// Closing the chain into a 5-cycle shrinks the diameter.
+		g.AddEdge(newTestEdge(t, "e", "a", "Knows"))
+		if diameter, _, _, _ := g.GetGraphDiameter(); diameter != 2 {
+			t.Errorf("Expected diameter 2 for a 5-cycle, got %d", diameter)
+		}
+
+		g.AddNode(newTestNode(t, "isolated", nil, nil))
+		if _, _, _, err := g.GetGraphDiameter(); err == nil {
+			t.Error("Expected an error for a disconnected graph")
+		}
+		if _, _, _, err := gopengraph.NewOpenGraph("").GetGraphDiameter(); err == nil {
+			t.Error("Expected an error for an empty graph")
+		}
+	})
+
+	t.Run("approximate diameter", func(t *testing.T) {
+		g := newChain(t)
+
+		diameter, err := g.GetApproximateDiameter(2)
+		if err != nil {
+			t.Fatalf("GetApproximateDiameter failed: %v", err)
+		}
+		if diameter < 2 || diameter > 4 {
+			t.Errorf("Expected an estimate between 2 and 4, got %d", diameter)
+		}
+
+		if diameter, _ := g.GetApproximateDiameter(10); diameter != 4 {
+			t.Errorf("Expected the exact diameter when sampling every node, got %d", diameter)
+		}
+		if _, err := g.GetApproximateDiameter(0); err == nil {
+			t.Error("Expected an error for zero samples")
+		}
+	})
+
+	t.Run("eccentricity", func(t *testing.T) {
+		g := newChain(t)
+
+		expected := map[string]int{"a": 4, "b": 3, "c": 2, "d": 3, "e": 4}
+		for id, want := range expected {
+			got, err := g.GetEccentricity(id)
+			if err != nil {
+				t.Fatalf("GetEccentricity(%s) failed: %v", id, err)
+			}
+			if got != want {
+				t.Errorf("Expected eccentricity %d for %s, got %d", want, id, got)
+			}
+		}
+
+		if _, err := g.GetEccentricity("missing"); err == nil {
+			t.Error("Expected an error for a missing node")
+		}
+
+		g.AddNode(newTestNode(t, "isolated", nil, nil))
+		if got, err := g.GetEccentricity("c"); err != nil || got != -1 {
+			t.Errorf("Expected -1 in a disconnected graph, got %d (err=%v)", got, err)
+		}
+	})
+
+	t.Run("radius and center", func(t *testing.T) {
+		g := newChain(t)
+
+		radius, err := g.GetRadius()
+		if err != nil {
+			t.Fatalf("GetRadius failed: %v", err)
+		}
+		if radius != 2 {
+			t.Errorf("Expected radius 2, got %d", radius)
+		}
+
+		center, err := g.GetCenter()
+		if err != nil {
+			t.Fatalf("GetCenter failed: %v", err)
+		}
+		if ids := nodeIDs(center); len(ids) != 1 || ids[0] != "c" {
+			t.Errorf("Expected center [c], got %v", ids)
+		}
+
+		g.AddNode(newTestNode(t, "f", nil, nil))
+		g.AddEdge(newTestEdge(t, "e", "f", "Knows"))
+		center, _ = g.GetCenter()
+		if ids := nodeIDs(center); len(ids) != 2 || ids[0] != "c" || ids[1] != "d" {
+			t.Errorf("Expected center [c d] for a 6-node chain, got %v", ids)
+		}
+
+		g.AddNode(newTestNode(t, "isolated", nil, nil))
+		if _, err := g.GetRadius(); err == nil {
+			t.Error("Expected an error for a disconnected graph")
+		}
+		if _, err := gopengraph.NewOpenGraph("").GetCenter(); err == nil {
+			t.Error("Expected an error for an empty graph")
+		}
+	})
+}
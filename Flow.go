@@ -0,0 +1,189 @@
+package gopengraph
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// flowEpsilon is the amount of flow below which an edge is considered
+// saturated or empty, absorbing floating-point rounding errors.
+const flowEpsilon = 1e-9
+
+// FlowPath is a path from the source to the sink of a flow network together
+// with the amount of flow it carries, as returned by GetFlowDecomposition.
+type FlowPath struct {
+	Path []string
+	Flow float64
+}
+
+// Flows
+
+// GetFlowDecomposition computes a maximum flow from one node to another and
+// decomposes it into paths, e.g. to enumerate the independent channels an
+// attacker can use to reach a target.
+//
+// Edges are followed in their direction, with the numeric value of the
+// capacityProperty property as capacity; the capacities of parallel edges add
+// up. Edges without the property are ignored, and an empty capacityProperty
+// gives every edge a capacity of 1. The maximum flow is found with the
+// Edmonds-Karp algorithm, then shortest paths carrying flow are repeatedly
+// extracted from it, so the flows of the paths sum to the maximum flow.
+//
+// Arguments:
+//
+//	sourceID string: The ID of the node the flow leaves from.
+//	sinkID string: The ID of the node the flow arrives at.
+//	capacityProperty string: The edge property holding the capacities, or ""
+//	                         for unit capacities.
+//
+// Returns:
+//
+//	[]FlowPath: The paths of the decomposition, in the order they were
+//	            extracted, or an empty slice if no flow can reach sinkID.
+//	error: An error if either node does not exist, if they are the same node,
+//	       if an edge has a non-numeric, negative, infinite or NaN capacity, or
+//	       if the capacities of parallel edges add up to infinity.
+func (g *OpenGraph) GetFlowDecomposition(sourceID, sinkID string, capacityProperty string) ([]FlowPath, error) {
+	if _, exists := g.nodes[sourceID]; !exists {
+		return nil, fmt.Errorf("source node '%s' not found", sourceID)
+	}
+	if _, exists := g.nodes[sinkID]; !exists {
+		return nil, fmt.Errorf("sink node '%s' not found", sinkID)
+	}
+	if sourceID == sinkID {
+		return nil, fmt.Errorf("source and sink must be different nodes")
+	}
+
+	capacity := make(map[[2]string]float64)
+	for _, e := range g.edges {
+		start, end := e.GetStartNodeID(), e.GetEndNodeID()
+		if _, exists := g.nodes[start]; !exists || start == end {
+			continue
+		}
+		if _, exists := g.nodes[end]; !exists {
+			continue
+		}
+
+		c := 1.0
+		if capacityProperty != "" {
+			if !e.GetProperties().HasProperty(capacityProperty) {
+				continue
+			}
+			value, ok := toFloat64(e.GetProperty(capacityProperty))
+			if !ok || value < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+				return nil, fmt.Errorf("edge %s-%s->%s has an invalid capacity: %v", start, e.GetKind(), end, e.GetProperty(capacityProperty))
+			}
+			c = value
+		}
+
+		capacity[[2]string{start, end}] += c
+		if math.IsInf(capacity[[2]string{start, end}], 0) {
+			return nil, fmt.Errorf("capacities of the edges from %s to %s overflow", start, end)
+		}
+	}
+
+	// Flow may be pushed back along an edge, so the residual network links
+	// the endpoints of every edge both ways.
+	residualNeighbors := make(map[string][]string)
+	linked := make(map[[2]string]bool)
+	for pair := range capacity {
+		u, v := pair[0], pair[1]
+		if linked[[2]string{u, v}] {
+			continue
+		}
+		linked[[2]string{u, v}], linked[[2]string{v, u}] = true, true
+		residualNeighbors[u] = append(residualNeighbors[u], v)
+		residualNeighbors[v] = append(residualNeighbors[v], u)
+	}
+	for _, neighbors := range residualNeighbors {
+		sort.Strings(neighbors)
+	}
+
+	// flow is antisymmetric: pushing flow from u to v cancels flow from v to u.
+	flow := make(map[[2]string]float64)
+	for {
+		path := shortestFlowPath(sourceID, sinkID, residualNeighbors, func(u, v string) float64 {
+			return capacity[[2]string{u, v}] - flow[[2]string{u, v}]
+		})
+		if path == nil {
+			break
+		}
+		bottleneck := pathBottleneck(path, func(u, v string) float64 {
+			return capacity[[2]string{u, v}] - flow[[2]string{u, v}]
+		})
+		for i := 1; i < len(path); i++ {
+			flow[[2]string{path[i-1], path[i]}] += bottleneck
+			flow[[2]string{path[i], path[i-1]}] -= bottleneck
+		}
+	}
+
+	// Peel paths off the positive part of the flow until none is left.
+	positive := make(map[[2]string]float64)
+	for pair, f := range flow {
+		if f > flowEpsilon {
+			positive[pair] = f
+		}
+	}
+	paths := make([]FlowPath, 0)
+	for {
+		path := shortestFlowPath(sourceID, sinkID, residualNeighbors, func(u, v string) float64 {
+			return positive[[2]string{u, v}]
+		})
+		if path == nil {
+			break
+		}
+		bottleneck := pathBottleneck(path, func(u, v string) float64 {
+			return positive[[2]string{u, v}]
+		})
+		for i := 1; i < len(path); i++ {
+			positive[[2]string{path[i-1], path[i]}] -= bottleneck
+		}
+		paths = append(paths, FlowPath{Path: path, Flow: bottleneck})
+	}
+	return paths, nil
+}
+
+// shortestFlowPath finds a path from sourceID to sinkID with the fewest steps
+// among those whose every step has more than flowEpsilon of available amount.
+// It returns nil if there is none.
+func shortestFlowPath(sourceID, sinkID string, neighbors map[string][]string, available func(u, v string) float64) []string {
+	parent := map[string]string{sourceID: sourceID}
+	queue := []string{sourceID}
+	for len(queue) > 0 {
+		current := queue[0]
+		if current == sinkID {
+			break
+		}
+		queue = queue[1:]
+		for _, next := range neighbors[current] {
+			if _, visited := parent[next]; visited || available(current, next) <= flowEpsilon {
+				continue
+			}
+			parent[next] = current
+			queue = append(queue, next)
+		}
+	}
+	if _, reached := parent[sinkID]; !reached {
+		return nil
+	}
+
+	path := []string{sinkID}
+	for id := sinkID; id != sourceID; {
+		id = parent[id]
+		path = append(path, id)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// pathBottleneck returns the smallest available amount among the steps of path.
+func pathBottleneck(path []string, available func(u, v string) float64) float64 {
+	bottleneck := available(path[0], path[1])
+	for i := 2; i < len(path); i++ {
+		bottleneck = min(bottleneck, available(path[i-1], path[i]))
+	}
+	return bottleneck
+}
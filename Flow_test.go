@@ -0,0 +1,96 @@
+package gopengraph_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+)
+
+func TestGetFlowDecomposition(t *testing.T) {
+	// A flow network from s to t whose maximum flow is 5.
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"s", "a", "b", "t", "x"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	for _, link := range []struct {
+		start, end string
+		capacity   interface{}
+	}{
+		{"s", "a", 3}, {"s", "b", 2}, {"a", "b", 1.5}, {"a", "t", 2}, {"b", "t", 3},
+	} {
+		e := newTestEdge(t, link.start, link.end, "CanReach")
+		e.SetProperty("capacity", link.capacity)
+		g.AddEdge(e)
+	}
+
+	t.Run("decomposes the maximum flow", func(t *testing.T) {
+		paths, err := g.GetFlowDecomposition("s", "t", "capacity")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		total := 0.0
+		for _, p := range paths {
+			if p.Flow <= 0 {
+				t.Errorf("Expected a positive flow, got %v on %v", p.Flow, p.Path)
+			}
+			if p.Path[0] != "s" || p.Path[len(p.Path)-1] != "t" {
+				t.Errorf("Expected a path from s to t, got %v", p.Path)
+			}
+			if _, err := g.GetPathEdges(p.Path); err != nil {
+				t.Errorf("Expected a path of the graph, got %v: %v", p.Path, err)
+			}
+			total += p.Flow
+		}
+		if math.Abs(total-5) > 1e-9 {
+			t.Errorf("Expected the flows to sum to 5, got %v (%v)", total, paths)
+		}
+
+		// With unit capacities, the flow follows the two edge-disjoint paths.
+		paths, err = g.GetFlowDecomposition("s", "t", "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		want := []gopengraph.FlowPath{{Path: []string{"s", "a", "t"}, Flow: 1}, {Path: []string{"s", "b", "t"}, Flow: 1}}
+		if !reflect.DeepEqual(paths, want) {
+			t.Errorf("Expected %v, got %v", want, paths)
+		}
+
+		paths, err = g.GetFlowDecomposition("s", "x", "capacity")
+		if err != nil || paths == nil || len(paths) != 0 {
+			t.Errorf("Expected no path to an unreachable node, got %v (%v)", paths, err)
+		}
+	})
+
+	// Last, as it adds an edge with an invalid capacity to the network.
+	t.Run("errors", func(t *testing.T) {
+		for _, pair := range [][2]string{{"missing", "t"}, {"s", "missing"}, {"s", "s"}} {
+			if _, err := g.GetFlowDecomposition(pair[0], pair[1], "capacity"); err == nil {
+				t.Errorf("Expected error from %s to %s, got nil", pair[0], pair[1])
+			}
+		}
+
+		e := newTestEdge(t, "s", "x", "CanReach")
+		g.AddEdge(e)
+		for _, capacity := range []interface{}{-1, math.Inf(1), math.NaN()} {
+			e.SetProperty("capacity", capacity)
+			if _, err := g.GetFlowDecomposition("s", "t", "capacity"); err == nil {
+				t.Errorf("Expected error for a capacity of %v, got nil", capacity)
+			}
+		}
+
+		// Finite parallel capacities whose sum overflows are rejected too.
+		multi := gopengraph.NewOpenGraphMulti("")
+		multi.AddNode(newTestNode(t, "s", nil, nil))
+		multi.AddNode(newTestNode(t, "t", nil, nil))
+		for i := 0; i < 2; i++ {
+			parallel := newTestEdge(t, "s", "t", "CanReach")
+			parallel.SetProperty("capacity", math.MaxFloat64)
+			multi.AddEdge(parallel)
+		}
+		if _, err := multi.GetFlowDecomposition("s", "t", "capacity"); err == nil {
+			t.Error("Expected error for overflowing parallel capacities, got nil")
+		}
+	})
+}
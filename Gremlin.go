@@ -0,0 +1,140 @@
+package gopengraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TheManticoreProject/gopengraph/properties"
+)
+
+// gremlinIDProperty is the vertex property holding the OpenGraph node ID in the
+// scripts written by ExportToGremlin. Not every TinkerPop provider accepts
+// user-supplied vertex IDs, so the node ID is stored as a regular property.
+const gremlinIDProperty = "opengraph_id"
+
+// Gremlin exports
+
+// ExportToGremlin writes a Gremlin Groovy script recreating the graph in a
+// TinkerPop-compatible database.
+//
+// Every node becomes a g.addV() statement whose label is the kinds of the node
+// joined by "::" (the multi-label syntax of Amazon Neptune; a single kind is
+// used as is, and a node without kinds gets the default "vertex" label). The
+// node ID is stored in the "opengraph_id" property. Every edge then becomes a
+// g.addE() statement labeled with its kind, between the vertices of its
+// endpoints; edges whose endpoints are not both nodes of the graph are skipped.
+// Array properties are written as Groovy lists. Nodes and edges are ordered as
+// by ExportJSON.
+//
+// Arguments:
+//
+//	w io.Writer: The writer to write the script to.
+//
+// Returns:
+//
+//	error: An error if the script could not be written.
+func (g *OpenGraph) ExportToGremlin(w io.Writer) error {
+	var b strings.Builder
+	variables := make(map[string]string, len(g.nodes))
+
+	for i, n := range g.sortedNodes() {
+		variable := fmt.Sprintf("v%d", i)
+		variables[n.GetID()] = variable
+
+		label := strings.Join(n.GetKinds(), "::")
+		if label == "" {
+			label = "vertex"
+		}
+		fmt.Fprintf(&b, "%s = g.addV(%s).property(%s, %s)", variable,
+			gremlinLiteral(label), gremlinLiteral(gremlinIDProperty), gremlinLiteral(n.GetID()))
+		writeGremlinProperties(&b, n.GetProperties())
+		b.WriteString(".next()\n")
+	}
+
+	for _, e := range g.sortedEdges() {
+		start, startExists := variables[e.GetStartNodeID()]
+		end, endExists := variables[e.GetEndNodeID()]
+		if !startExists || !endExists {
+			continue
+		}
+		fmt.Fprintf(&b, "g.addE(%s).from(%s).to(%s)", gremlinLiteral(e.GetKind()), start, end)
+		writeGremlinProperties(&b, e.GetProperties())
+		b.WriteString(".iterate()\n")
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write Gremlin script: %w", err)
+	}
+	return nil
+}
+
+// writeGremlinProperties appends a .property() step for every property of p,
+// in key order.
+func writeGremlinProperties(b *strings.Builder, p *properties.Properties) {
+	values := p.GetAllProperties()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(b, ".property(%s, %s)", gremlinLiteral(key), gremlinLiteral(values[key]))
+	}
+}
+
+// gremlinLiteral formats a property value as a Groovy literal: strings are
+// single-quoted, floats are doubles and slices are lists.
+func gremlinLiteral(value interface{}) string {
+	if n, ok := value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return gremlinLiteral(i)
+		}
+		f, _ := n.Float64()
+		return gremlinLiteral(f)
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "$", `\$`)
+		return "'" + replacer.Replace(v.String()) + "'"
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() > math.MaxInt32 || v.Int() < math.MinInt32 {
+			return strconv.FormatInt(v.Int(), 10) + "L"
+		}
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() > math.MaxInt32 {
+			return strconv.FormatUint(v.Uint(), 10) + "L"
+		}
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		switch f := v.Float(); {
+		case math.IsNaN(f):
+			return "Double.NaN"
+		case math.IsInf(f, 1):
+			return "Double.POSITIVE_INFINITY"
+		case math.IsInf(f, -1):
+			return "Double.NEGATIVE_INFINITY"
+		default:
+			return strconv.FormatFloat(f, 'g', -1, 64) + "d"
+		}
+	case reflect.Slice, reflect.Array:
+		elements := make([]string, v.Len())
+		for i := range elements {
+			elements[i] = gremlinLiteral(v.Index(i).Interface())
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	default:
+		return gremlinLiteral(fmt.Sprint(value))
+	}
+}
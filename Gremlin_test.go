@@ -0,0 +1,37 @@
+package gopengraph_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+)
+
+func TestExportToGremlin(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "2", []string{"Group"}, map[string]interface{}{"name": "IT's admins"}))
+	g.AddNode(newTestNode(t, "1", []string{"User", "Base"}, map[string]interface{}{
+		"name": "ALICE", "enabled": true, "logons": 3, "ratio": 0.5, "tags": []string{"a", "b"},
+	}))
+	g.AddNode(newTestNode(t, "3", nil, nil))
+	memberOf := newTestEdge(t, "1", "2", "MemberOf")
+	memberOf.SetProperty("since", 2020)
+	g.AddEdge(memberOf)
+	g.AddEdgeWithoutValidation(newTestEdge(t, "3", "missing", "Knows"))
+
+	var buf bytes.Buffer
+	if err := g.ExportToGremlin(&buf); err != nil {
+		t.Fatalf("ExportToGremlin failed: %v", err)
+	}
+
+	expected := strings.Join([]string{
+		`v0 = g.addV('User::Base').property('opengraph_id', '1').property('enabled', true).property('logons', 3).property('name', 'ALICE').property('ratio', 0.5d).property('tags', ['a', 'b']).next()`,
+		`v1 = g.addV('Group').property('opengraph_id', '2').property('name', 'IT\'s admins').next()`,
+		`v2 = g.addV('vertex').property('opengraph_id', '3').next()`,
+		`g.addE('MemberOf').from(v0).to(v1).property('since', 2020).iterate()`,
+	}, "\n") + "\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("Unexpected script:\n%s\nExpected:\n%s", got, expected)
+	}
+}
@@ -0,0 +1,133 @@
+package gopengraph
+
+import (
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+// HookHandle identifies a callback registered on an OpenGraph, so that it can be
+// deregistered with RemoveHook.
+type HookHandle uint64
+
+type hookEvent int
+
+const (
+	hookNodeAdded hookEvent = iota
+	hookNodeRemoved
+	hookEdgeAdded
+	hookEdgeRemoved
+)
+
+// hook is a registered callback. Exactly one of onNode and onEdge is set,
+// depending on event.
+type hook struct {
+	handle HookHandle
+	event  hookEvent
+	onNode func(*node.Node)
+	onEdge func(*edge.Edge)
+}
+
+// Event hooks
+
+// OnNodeAdded registers fn to be called after a node is added to the graph.
+//
+// Callbacks registered with this and the other On* functions run
+// synchronously, in registration order, and only when the mutation succeeds
+// (e.g. not when AddNode rejects a duplicate node).
+//
+// Arguments:
+//
+//	fn func(*node.Node): The callback receiving the added node.
+//
+// Returns:
+//
+//	HookHandle: The handle to pass to RemoveHook to deregister the callback.
+func (g *OpenGraph) OnNodeAdded(fn func(*node.Node)) HookHandle {
+	return g.registerHook(hook{event: hookNodeAdded, onNode: fn})
+}
+
+// OnNodeRemoved registers fn to be called after a node is removed from the graph.
+// Callbacks are run as described in OnNodeAdded.
+//
+// Arguments:
+//
+//	fn func(*node.Node): The callback receiving the removed node.
+//
+// Returns:
+//
+//	HookHandle: The handle to pass to RemoveHook to deregister the callback.
+func (g *OpenGraph) OnNodeRemoved(fn func(*node.Node)) HookHandle {
+	return g.registerHook(hook{event: hookNodeRemoved, onNode: fn})
+}
+
+// OnEdgeAdded registers fn to be called after an edge is added to the graph,
+// and not when AddEdge rejects it. Callbacks are run as described in
+// OnNodeAdded.
+//
+// Arguments:
+//
+//	fn func(*edge.Edge): The callback receiving the added edge.
+//
+// Returns:
+//
+//	HookHandle: The handle to pass to RemoveHook to deregister the callback.
+func (g *OpenGraph) OnEdgeAdded(fn func(*edge.Edge)) HookHandle {
+	return g.registerHook(hook{event: hookEdgeAdded, onEdge: fn})
+}
+
+// OnEdgeRemoved registers fn to be called after an edge is removed from the
+// graph, including edges removed together with one of their nodes. Callbacks
+// are run as described in OnNodeAdded.
+//
+// Arguments:
+//
+//	fn func(*edge.Edge): The callback receiving the removed edge.
+//
+// Returns:
+//
+//	HookHandle: The handle to pass to RemoveHook to deregister the callback.
+func (g *OpenGraph) OnEdgeRemoved(fn func(*edge.Edge)) HookHandle {
+	return g.registerHook(hook{event: hookEdgeRemoved, onEdge: fn})
+}
+
+// RemoveHook deregisters the callback identified by handle.
+//
+// Arguments:
+//
+//	handle HookHandle: The handle returned when the callback was registered.
+//
+// Returns:
+//
+//	bool: True if a callback was removed, false if the handle is unknown.
+func (g *OpenGraph) RemoveHook(handle HookHandle) bool {
+	for i, h := range g.hooks {
+		if h.handle == handle {
+			g.hooks = append(g.hooks[:i:i], g.hooks[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (g *OpenGraph) registerHook(h hook) HookHandle {
+	g.nextHookHandle++
+	h.handle = g.nextHookHandle
+	g.hooks = append(g.hooks, h)
+	return h.handle
+}
+
+func (g *OpenGraph) notifyNode(event hookEvent, n *node.Node) {
+	for _, h := range g.hooks {
+		if h.event == event {
+			h.onNode(n)
+		}
+	}
+}
+
+func (g *OpenGraph) notifyEdge(event hookEvent, e *edge.Edge) {
+	for _, h := range g.hooks {
+		if h.event == event {
+			h.onEdge(e)
+		}
+	}
+}
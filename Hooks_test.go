@@ -0,0 +1,100 @@
+package gopengraph_test
+
+import (
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+func TestNodeHooks(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+
+	var added, removed []string
+	g.OnNodeAdded(func(n *node.Node) { added = append(added, n.GetID()) })
+	g.OnNodeRemoved(func(n *node.Node) { removed = append(removed, n.GetID()) })
+
+	n := newTestNode(t, "1", nil, nil)
+	g.AddNode(n)
+	if len(added) != 1 || added[0] != "1" {
+		t.Fatalf("Expected OnNodeAdded to be called once with node 1, got %v", added)
+	}
+
+	// A rejected duplicate must not trigger the callback.
+	g.AddNode(n)
+	if len(added) != 1 {
+		t.Errorf("Expected OnNodeAdded not to be called for a duplicate node, got %v", added)
+	}
+
+	g.RemoveNodeByID("1")
+	g.RemoveNodeByID("1")
+	if len(removed) != 1 || removed[0] != "1" {
+		t.Errorf("Expected OnNodeRemoved to be called once with node 1, got %v", removed)
+	}
+}
+
+func TestEdgeHooks(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "1", nil, nil))
+	g.AddNode(newTestNode(t, "2", nil, nil))
+
+	var added, removed int
+	g.OnEdgeAdded(func(e *edge.Edge) { added++ })
+	g.OnEdgeRemoved(func(e *edge.Edge) { removed++ })
+
+	g.AddEdge(newTestEdge(t, "1", "2", "Knows"))
+	g.AddEdge(newTestEdge(t, "1", "2", "Knows"))
+	g.AddEdge(newTestEdge(t, "1", "3", "Knows"))
+	if added != 1 {
+		t.Errorf("Expected OnEdgeAdded to be called once, got %d", added)
+	}
+
+	// Removing a node removes its incident edges too.
+	g.RemoveNodeByID("2")
+	if removed != 1 {
+		t.Errorf("Expected OnEdgeRemoved to be called once, got %d", removed)
+	}
+}
+
+func TestMultipleHooksAndRemoveHook(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+
+	var calls []string
+	first := g.OnNodeAdded(func(n *node.Node) { calls = append(calls, "first") })
+	g.OnNodeAdded(func(n *node.Node) { calls = append(calls, "second") })
+
+	g.AddNode(newTestNode(t, "1", nil, nil))
+	if len(calls) != 2 || calls[0] != "first" || calls[1] != "second" {
+		t.Fatalf("Expected both callbacks in registration order, got %v", calls)
+	}
+
+	if !g.RemoveHook(first) {
+		t.Fatal("Expected RemoveHook to return true for a registered handle")
+	}
+	if g.RemoveHook(first) {
+		t.Error("Expected RemoveHook to return false for an already removed handle")
+	}
+
+	calls = nil
+	g.AddNode(newTestNode(t, "2", nil, nil))
+	if len(calls) != 1 || calls[0] != "second" {
+		t.Errorf("Expected only the remaining callback to run, got %v", calls)
+	}
+}
+
+func TestClearTriggersRemovalHooks(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "1", nil, nil))
+	g.AddNode(newTestNode(t, "2", nil, nil))
+	g.AddEdge(newTestEdge(t, "1", "2", "Knows"))
+
+	var nodes, edges int
+	g.OnNodeRemoved(func(n *node.Node) { nodes++ })
+	g.OnEdgeRemoved(func(e *edge.Edge) { edges++ })
+
+	g.Clear()
+	if nodes != 2 || edges != 1 {
+		t.Errorf("Expected 2 node and 1 edge removal callbacks, got %d and %d", nodes, edges)
+	}
+}
@@ -0,0 +1,130 @@
+package gopengraph
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+// Iterators
+
+// Nodes returns an iterator over the nodes of the graph, for use in
+// "for n := range g.Nodes()".
+//
+// Nodes are yielded in no particular order and no slice is allocated. The graph
+// must not be modified while iterating.
+//
+// Returns:
+//
+//	iter.Seq[*node.Node]: The iterator over the nodes.
+func (g *OpenGraph) Nodes() iter.Seq[*node.Node] {
+	return func(yield func(*node.Node) bool) {
+		for _, n := range g.nodes {
+			if !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+// Edges returns an iterator over the edges of the graph, in insertion order.
+//
+// No slice is allocated. The graph must not be modified while iterating.
+//
+// Returns:
+//
+//	iter.Seq[*edge.Edge]: The iterator over the edges.
+func (g *OpenGraph) Edges() iter.Seq[*edge.Edge] {
+	return func(yield func(*edge.Edge) bool) {
+		for _, e := range g.edges {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// NodesOfKind returns an iterator over the nodes of the graph having the given
+// kind, in no particular order.
+//
+// Unlike GetNodesByKind, it checks the kinds of every node instead of using
+// the kind index, so no slice is allocated and kinds changed directly on a node
+// (e.g. with node.AddKind) are seen at once. Both return the same nodes as
+// long as kinds are changed through the graph, with AddKindToNode,
+// RemoveKindFromNode or ReplaceNodeKinds.
+//
+// Arguments:
+//
+//	kind string: The kind of the nodes to yield.
+//
+// Returns:
+//
+//	iter.Seq[*node.Node]: The iterator over the matching nodes.
+func (g *OpenGraph) NodesOfKind(kind string) iter.Seq[*node.Node] {
+	return func(yield func(*node.Node) bool) {
+		for n := range g.Nodes() {
+			if n.HasKind(kind) && !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+// EdgesOfKind returns an iterator over the edges of the graph having the given
+// kind, in insertion order. It is the allocation-free counterpart of
+// GetEdgesByKind.
+//
+// Arguments:
+//
+//	kind string: The kind of the edges to yield.
+//
+// Returns:
+//
+//	iter.Seq[*edge.Edge]: The iterator over the matching edges.
+func (g *OpenGraph) EdgesOfKind(kind string) iter.Seq[*edge.Edge] {
+	return func(yield func(*edge.Edge) bool) {
+		for e := range g.Edges() {
+			if e.GetKind() == kind && !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+// ForEachNodeParallel calls fn on every node of the graph, spreading the nodes
+// over workers goroutines.
+//
+// Nodes are visited in no particular order and ForEachNodeParallel returns once
+// fn has returned for every node. fn runs concurrently with itself, so it must
+// be safe for concurrent use: it may modify the node it receives, but must
+// synchronize any access to shared state, including other nodes. The graph must
+// not be modified until ForEachNodeParallel returns.
+//
+// Arguments:
+//
+//	workers int: The number of goroutines to use. Values below 1 use a single
+//	             goroutine.
+//	fn func(*node.Node): The function to call on every node.
+func (g *OpenGraph) ForEachNodeParallel(workers int, fn func(*node.Node)) {
+	workers = max(workers, 1)
+
+	nodes := make(chan *node.Node)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range nodes {
+				fn(n)
+			}
+		}()
+	}
+
+	for _, n := range g.nodes {
+		nodes <- n
+	}
+	close(nodes)
+	wg.Wait()
+}
@@ -0,0 +1,163 @@
+package gopengraph_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+func TestIterators(t *testing.T) {
+	// 1 000 nodes alternating between the User and Computer kinds, where every
+	// node has an edge to the next one.
+	g := gopengraph.NewOpenGraph("")
+	for i := 0; i < 1000; i++ {
+		kind := "User"
+		if i%2 == 1 {
+			kind = "Computer"
+		}
+		g.AddNode(newTestNode(t, fmt.Sprintf("n%d", i), []string{kind}, nil))
+	}
+	for i := 0; i+1 < 1000; i++ {
+		kind := "Knows"
+		if i%2 == 1 {
+			kind = "AdminTo"
+		}
+		g.AddEdgeWithoutValidation(newTestEdge(t, fmt.Sprintf("n%d", i), fmt.Sprintf("n%d", i+1), kind))
+	}
+
+	t.Run("nodes", func(t *testing.T) {
+		visits := make(map[string]int)
+		for n := range g.Nodes() {
+			visits[n.GetID()]++
+		}
+		if len(visits) != 1000 {
+			t.Fatalf("Expected 1000 distinct nodes, got %d", len(visits))
+		}
+		for id, count := range visits {
+			if count != 1 {
+				t.Errorf("Expected node %s to be visited once, got %d", id, count)
+			}
+		}
+
+		users := 0
+		for n := range g.NodesOfKind("User") {
+			if !n.HasKind("User") {
+				t.Errorf("Expected only User nodes, got %v", n)
+			}
+			users++
+		}
+		if users != 500 {
+			t.Errorf("Expected 500 User nodes, got %d", users)
+		}
+	})
+
+	t.Run("edges", func(t *testing.T) {
+		var ends []string
+		for e := range g.Edges() {
+			ends = append(ends, e.GetEndNodeID())
+		}
+		if len(ends) != 999 || ends[0] != "n1" || ends[998] != "n999" {
+			t.Errorf("Expected the 999 edges in insertion order, got %d edges", len(ends))
+		}
+
+		admin := 0
+		for e := range g.EdgesOfKind("AdminTo") {
+			if e.GetKind() != "AdminTo" {
+				t.Errorf("Expected only AdminTo edges, got %v", e)
+			}
+			admin++
+		}
+		if admin != 499 {
+			t.Errorf("Expected 499 AdminTo edges, got %d", admin)
+		}
+	})
+
+	t.Run("stop early", func(t *testing.T) {
+		count := 0
+		for range g.NodesOfKind("Computer") {
+			count++
+			if count == 3 {
+				break
+			}
+		}
+		if count != 3 {
+			t.Errorf("Expected the loop to stop after 3 nodes, got %d", count)
+		}
+
+		count = 0
+		for range g.Edges() {
+			count++
+			break
+		}
+		if count != 1 {
+			t.Errorf("Expected the loop to stop after 1 edge, got %d", count)
+		}
+	})
+
+	t.Run("parallel", func(t *testing.T) {
+		for _, workers := range []int{0, 1, 8} {
+			var mu sync.Mutex
+			visits := make(map[string]int)
+			g.ForEachNodeParallel(workers, func(n *node.Node) {
+				mu.Lock()
+				visits[n.GetID()]++
+				mu.Unlock()
+			})
+
+			if len(visits) != 1000 {
+				t.Fatalf("Expected 1000 distinct nodes with %d workers, got %d", workers, len(visits))
+			}
+			for id, count := range visits {
+				if count != 1 {
+					t.Errorf("Expected node %s to be visited once with %d workers, got %d", id, workers, count)
+				}
+			}
+		}
+
+		// Modifying the visited node itself needs no synchronization.
+		g.ForEachNodeParallel(4, func(n *node.Node) {
+			n.SetProperty("visited", true)
+		})
+		for n := range g.Nodes() {
+			if n.GetProperty("visited") != true {
+				t.Fatalf("Expected node %s to be updated", n.GetID())
+			}
+		}
+	})
+}
+
+func BenchmarkNodesOfKind(b *testing.B) {
+	g := newKindIndexBenchmarkGraph(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		for range g.NodesOfKind("Kind7") {
+			count++
+		}
+	}
+}
+
+func BenchmarkGetNodesByKind(b *testing.B) {
+	g := newKindIndexBenchmarkGraph(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		for range g.GetNodesByKind("Kind7") {
+			count++
+		}
+	}
+}
+
+func BenchmarkFilterNodesByPredicate(b *testing.B) {
+	g := newKindIndexBenchmarkGraph(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		count := 0
+		for range g.FilterNodesByPredicate(func(n *node.Node) bool { return n.HasKind("Kind7") }) {
+			count++
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package gopengraph
+
+import (
+	"fmt"
+
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+// Kind index
+
+// AddKindToNode adds a kind to a node of the graph, as node.AddKind does.
+//
+// Kinds must be changed through the graph (AddKindToNode, RemoveKindFromNode,
+// ReplaceNodeKinds) rather than directly on a node for GetNodesByKind to see
+// the change: the kind index it relies on is only updated by the graph.
+// NodesOfKind does not use the index and sees either change.
+//
+// Arguments:
+//
+//	nodeID string: The ID of the node to update.
+//	kind string: The kind to add.
+//
+// Returns:
+//
+//	error: An error if the node does not exist or already has node.MaxKinds
+//	       other kinds.
+func (g *OpenGraph) AddKindToNode(nodeID string, kind string) error {
+	n, exists := g.nodes[nodeID]
+	if !exists {
+		return fmt.Errorf("node '%s' not found", nodeID)
+	}
+	g.removeFromKindIndex(n)
+	added := n.AddKind(kind)
+	g.addToKindIndex(n)
+	if !added {
+		return fmt.Errorf("node '%s' cannot have more than %d kinds", nodeID, node.MaxKinds)
+	}
+	return nil
+}
+
+// RemoveKindFromNode removes a kind from a node of the graph, as
+// node.RemoveKind does, keeping the kind index valid.
+//
+// Arguments:
+//
+//	nodeID string: The ID of the node to update.
+//	kind string: The kind to remove.
+//
+// Returns:
+//
+//	error: An error if the node does not exist.
+func (g *OpenGraph) RemoveKindFromNode(nodeID string, kind string) error {
+	n, exists := g.nodes[nodeID]
+	if !exists {
+		return fmt.Errorf("node '%s' not found", nodeID)
+	}
+	g.removeFromKindIndex(n)
+	n.RemoveKind(kind)
+	g.addToKindIndex(n)
+	return nil
+}
+
+// buildKindIndex indexes the IDs of the nodes of the graph by kind, unless the
+// index is already built.
+func (g *OpenGraph) buildKindIndex() {
+	if g.kindIndex != nil {
+		return
+	}
+	g.kindIndex = make(map[string]map[string]bool)
+	for id, n := range g.nodes {
+		for _, kind := range n.GetKinds() {
+			if g.kindIndex[kind] == nil {
+				g.kindIndex[kind] = make(map[string]bool)
+			}
+			g.kindIndex[kind][id] = true
+		}
+	}
+}
+
+// addToKindIndex records the kinds of n in the kind index. Unless the index is
+// kept up to date (see WithKindIndex), it is discarded instead, to be rebuilt
+// on the next lookup.
+func (g *OpenGraph) addToKindIndex(n *node.Node) {
+	if !g.eagerKindIndex {
+		g.kindIndex = nil
+		return
+	}
+	for _, kind := range n.GetKinds() {
+		if g.kindIndex[kind] == nil {
+			g.kindIndex[kind] = make(map[string]bool)
+		}
+		g.kindIndex[kind][n.GetID()] = true
+	}
+}
+
+// removeFromKindIndex removes the kinds of n from the kind index, or discards
+// the index as addToKindIndex does.
+func (g *OpenGraph) removeFromKindIndex(n *node.Node) {
+	if !g.eagerKindIndex {
+		g.kindIndex = nil
+		return
+	}
+	for _, kind := range n.GetKinds() {
+		delete(g.kindIndex[kind], n.GetID())
+		if len(g.kindIndex[kind]) == 0 {
+			delete(g.kindIndex, kind)
+		}
+	}
+}
+
+// resetKindIndex empties the kind index, as when the graph is cleared.
+func (g *OpenGraph) resetKindIndex() {
+	g.kindIndex = nil
+	if g.eagerKindIndex {
+		g.kindIndex = make(map[string]map[string]bool)
+	}
+}
@@ -0,0 +1,143 @@
+package gopengraph_test
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+)
+
+// sortedNodeIDsOfKind returns the IDs of the nodes of a kind, sorted.
+func sortedNodeIDsOfKind(g *gopengraph.OpenGraph, kind string) []string {
+	ids := nodeIDs(g.GetNodesByKind(kind))
+	sort.Strings(ids)
+	return ids
+}
+
+func TestGetNodesByKindIndexInvalidation(t *testing.T) {
+	g := newTestGraph(t)
+	if got := sortedNodeIDsOfKind(g, "User"); !reflect.DeepEqual(got, []string{"alice", "bob"}) {
+		t.Fatalf("Expected [alice bob], got %v", got)
+	}
+
+	g.AddNode(newTestNode(t, "carol", []string{"User"}, nil))
+	if got := sortedNodeIDsOfKind(g, "User"); !reflect.DeepEqual(got, []string{"alice", "bob", "carol"}) {
+		t.Errorf("Expected [alice bob carol] after AddNode, got %v", got)
+	}
+
+	g.RemoveNodeByID("bob")
+	if got := sortedNodeIDsOfKind(g, "User"); !reflect.DeepEqual(got, []string{"alice", "carol"}) {
+		t.Errorf("Expected [alice carol] after RemoveNodeByID, got %v", got)
+	}
+
+	if err := g.AddKindToNode("admins", "User"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := sortedNodeIDsOfKind(g, "User"); !reflect.DeepEqual(got, []string{"admins", "alice", "carol"}) {
+		t.Errorf("Expected [admins alice carol] after AddKindToNode, got %v", got)
+	}
+
+	if err := g.RemoveKindFromNode("alice", "User"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := sortedNodeIDsOfKind(g, "User"); !reflect.DeepEqual(got, []string{"admins", "carol"}) {
+		t.Errorf("Expected [admins carol] after RemoveKindFromNode, got %v", got)
+	}
+
+	if err := g.ReplaceNodeKinds("carol", []string{"Computer"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := sortedNodeIDsOfKind(g, "Computer"); !reflect.DeepEqual(got, []string{"carol"}) {
+		t.Errorf("Expected [carol] after ReplaceNodeKinds, got %v", got)
+	}
+
+	g.Clear()
+	if got := g.GetNodesByKind("Computer"); len(got) != 0 {
+		t.Errorf("Expected no node after Clear, got %v", nodeIDs(got))
+	}
+}
+
+func TestNodesOfKindAndKindIndex(t *testing.T) {
+	g := newTestGraph(t)
+	iterated := func(kind string) []string {
+		ids := make([]string, 0)
+		for n := range g.NodesOfKind(kind) {
+			ids = append(ids, n.GetID())
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	// Kinds changed through the graph are seen by both.
+	if err := g.AddKindToNode("admins", "User"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, want := iterated("User"), sortedNodeIDsOfKind(g, "User"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected NodesOfKind to match GetNodesByKind %v, got %v", want, got)
+	}
+
+	// Kinds changed directly on a node are only seen by NodesOfKind until the
+	// kind index is rebuilt.
+	g.GetNode("bob").AddKind("Tier0")
+	if got := iterated("Tier0"); !reflect.DeepEqual(got, []string{"bob"}) {
+		t.Errorf("Expected NodesOfKind to see the new kind, got %v", got)
+	}
+	if got := sortedNodeIDsOfKind(g, "Tier0"); len(got) != 0 {
+		t.Errorf("Expected the kind index not to see the new kind, got %v", got)
+	}
+	g.AddNode(newTestNode(t, "carol", []string{"User"}, nil))
+	if got := sortedNodeIDsOfKind(g, "Tier0"); !reflect.DeepEqual(got, []string{"bob"}) {
+		t.Errorf("Expected the rebuilt kind index to see the new kind, got %v", got)
+	}
+}
+
+func TestAddKindToNodeErrors(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "a", []string{"A", "B", "C"}, nil))
+
+	if err := g.AddKindToNode("missing", "User"); err == nil {
+		t.Error("Expected error for a missing node, got nil")
+	}
+	if err := g.AddKindToNode("a", "D"); err == nil {
+		t.Error("Expected error when the node already has the maximum number of kinds, got nil")
+	}
+	if err := g.AddKindToNode("a", "A"); err != nil {
+		t.Errorf("Expected no error for a kind the node already has, got %v", err)
+	}
+	if err := g.RemoveKindFromNode("missing", "A"); err == nil {
+		t.Error("Expected error for a missing node, got nil")
+	}
+}
+
+// newKindIndexBenchmarkGraph builds a graph of 100 000 nodes spread over 20
+// kinds.
+func newKindIndexBenchmarkGraph(b *testing.B) *gopengraph.OpenGraph {
+	b.Helper()
+	g := gopengraph.NewOpenGraph("")
+	for i := 0; i < 100000; i++ {
+		g.AddNode(newTestNode(b, fmt.Sprintf("n%d", i), []string{fmt.Sprintf("Kind%d", i%20)}, nil))
+	}
+	return g
+}
+
+func BenchmarkGetNodesByKindScan(b *testing.B) {
+	g := newKindIndexBenchmarkGraph(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var nodes []string
+		for n := range g.NodesOfKind("Kind7") {
+			nodes = append(nodes, n.GetID())
+		}
+	}
+}
+
+func BenchmarkGetNodesByKindIndexed(b *testing.B) {
+	g := newKindIndexBenchmarkGraph(b)
+	g.GetNodesByKind("Kind7")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.GetNodesByKind("Kind7")
+	}
+}
@@ -1,9 +1,16 @@
 package gopengraph
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math/rand/v2"
 	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/TheManticoreProject/gopengraph/edge"
 	"github.com/TheManticoreProject/gopengraph/node"
@@ -21,15 +28,70 @@ type OpenGraph struct {
 	nodes      map[string]*node.Node
 	edges      []*edge.Edge
 	sourceKind string
+
+	// outEdges and inEdges index the edges by start and end node ID, in the
+	// order of edges.
+	outEdges map[string][]*edge.Edge
+	inEdges  map[string][]*edge.Edge
+
+	hooks          []hook
+	nextHookHandle HookHandle
+
+	propertyIndexes map[string]propertyIndex
+
+	// multiEdges disables the duplicate edge check of AddEdge.
+	multiEdges bool
+
+	// kindIndex maps each kind to the IDs of the nodes having it. It is built
+	// by GetNodesByKind on demand and reset to nil whenever the nodes or their
+	// kinds change through the graph, unless eagerKindIndex is set, in which
+	// case it is kept up to date instead.
+	kindIndex      map[string]map[string]bool
+	eagerKindIndex bool
+
+	// autoSourceKind adds the source kind to the nodes of the graph.
+	autoSourceKind bool
+
+	// concurrent guards the methods listed by WithConcurrency with mu.
+	concurrent bool
+	mu         sync.RWMutex
 }
 
-// NewOpenGraph creates a new OpenGraph instance
-func NewOpenGraph(sourceKind string) *OpenGraph {
-	return &OpenGraph{
-		nodes:      make(map[string]*node.Node),
-		edges:      make([]*edge.Edge, 0),
-		sourceKind: sourceKind,
+// NewOpenGraph creates a new OpenGraph instance.
+//
+// Without options, the graph rejects duplicate edges and adds its source kind
+// to its nodes; see Option for the alternatives.
+func NewOpenGraph(sourceKind string, opts ...Option) *OpenGraph {
+	g := &OpenGraph{
+		nodes:          make(map[string]*node.Node),
+		edges:          make([]*edge.Edge, 0),
+		sourceKind:     sourceKind,
+		outEdges:       make(map[string][]*edge.Edge),
+		inEdges:        make(map[string][]*edge.Edge),
+		autoSourceKind: true,
+	}
+	for _, opt := range opts {
+		opt(g)
 	}
+	return g
+}
+
+// NewOpenGraphMulti creates a new OpenGraph instance in multigraph mode, where
+// AddEdge accepts several edges with the same endpoints and kind. Such
+// parallel edges are told apart with edge.IsSame and can be listed with
+// GetEdgesByNodePair.
+func NewOpenGraphMulti(sourceKind string) *OpenGraph {
+	return NewOpenGraph(sourceKind, WithMultiEdges())
+}
+
+// IsMultiGraph reports whether the graph accepts parallel edges, as created by
+// NewOpenGraphMulti.
+//
+// Returns:
+//
+//	bool: True if AddEdge does not reject duplicate edges.
+func (g *OpenGraph) IsMultiGraph() bool {
+	return g.multiEdges
 }
 
 // Edges operations
@@ -38,7 +100,8 @@ func NewOpenGraph(sourceKind string) *OpenGraph {
 //
 // It verifies that both the start and end nodes referenced by the edge exist in the graph,
 // and that the edge is not a duplicate of an existing edge. If any validation fails,
-// the edge is not added.
+// the edge is not added. In multigraph mode (see NewOpenGraphMulti), duplicate
+// edges are accepted.
 //
 // Arguments:
 //
@@ -49,6 +112,20 @@ func NewOpenGraph(sourceKind string) *OpenGraph {
 //	bool: True if the edge was successfully added, false if validation failed
 //	      (e.g., nodes do not exist or the edge is a duplicate).
 func (g *OpenGraph) AddEdge(e *edge.Edge) bool {
+	unlock := g.lock()
+	if !g.canAddEdge(e) {
+		unlock()
+		return false
+	}
+	g.putEdge(e)
+	unlock()
+
+	g.notifyEdge(hookEdgeAdded, e)
+	return true
+}
+
+// canAddEdge reports whether e passes the validation of AddEdge.
+func (g *OpenGraph) canAddEdge(e *edge.Edge) bool {
 	// Verify both endpoints exist, but only for endpoints resolved by node id.
 	// name- and property-matched endpoints are resolved by BloodHound at
 	// ingestion time and cannot be validated against the local node set.
@@ -66,13 +143,15 @@ func (g *OpenGraph) AddEdge(e *edge.Edge) bool {
 	}
 
 	// Check for duplicate edge
-	for _, existing := range g.edges {
-		if existing.Equal(e) {
-			return false
+	if !g.multiEdges {
+		for _, existing := range g.edges {
+			if existing.Equal(e) {
+				return false
+			}
 		}
 	}
 
-	return g.AddEdgeWithoutValidation(e)
+	return true
 }
 
 // AddEdgeWithoutValidation adds an edge to the graph without validating the nodes.
@@ -89,10 +168,281 @@ func (g *OpenGraph) AddEdge(e *edge.Edge) bool {
 //
 //	bool: True if the edge was successfully added.
 func (g *OpenGraph) AddEdgeWithoutValidation(edge *edge.Edge) bool {
-	g.edges = append(g.edges, edge)
+	unlock := g.lock()
+	g.putEdge(edge)
+	unlock()
+
+	g.notifyEdge(hookEdgeAdded, edge)
 	return true
 }
 
+// putEdge appends e to the edges of the graph and indexes it.
+func (g *OpenGraph) putEdge(e *edge.Edge) {
+	g.edges = append(g.edges, e)
+	g.indexEdge(e)
+}
+
+// UpdateEdgeProperties sets several properties on an edge of the graph at once.
+//
+// The edge is looked up by its start node ID, end node ID and kind. All values are
+// validated before any of them is applied, so an invalid value leaves the edge
+// unchanged. Properties whose keys are not in updates are left untouched.
+//
+// Arguments:
+//
+//	startID string: The ID of the start node of the edge.
+//	endID string: The ID of the end node of the edge.
+//	kind string: The kind of the edge.
+//	updates map[string]interface{}: The properties to set on the edge.
+//
+// Returns:
+//
+//	error: An error if the edge does not exist or a property value is invalid.
+func (g *OpenGraph) UpdateEdgeProperties(startID, endID, kind string, updates map[string]interface{}) error {
+	e := g.findEdge(startID, endID, kind)
+	if e == nil {
+		return fmt.Errorf("edge (%s)-[%s]->(%s) not found", startID, kind, endID)
+	}
+	if err := validatePropertyUpdates(e.GetProperties(), updates); err != nil {
+		return err
+	}
+	for key, value := range updates {
+		e.SetProperty(key, value)
+	}
+	return nil
+}
+
+// BulkSetPropertyOnEdgesByKind sets a property on every edge of a kind.
+//
+// Arguments:
+//
+//	kind string: The kind of the edges to update.
+//	key string: The property key to set.
+//	value interface{}: The property value to set.
+//
+// Returns:
+//
+//	int: The number of edges updated, or -1 if value is not a valid property
+//	     value, in which case no edge is updated.
+func (g *OpenGraph) BulkSetPropertyOnEdgesByKind(kind string, key string, value interface{}) int {
+	if !properties.NewProperties().IsPropertyValueValid(value) {
+		return -1
+	}
+
+	count := 0
+	for _, e := range g.edges {
+		if e.GetKind() == kind {
+			e.SetProperty(key, value)
+			count++
+		}
+	}
+	return count
+}
+
+// SetEdgeKind changes the kind of an edge of the graph.
+//
+// The edge is looked up by its start node ID, end node ID and kind. The kind is
+// not changed if the graph already has an edge with the same endpoints and the
+// new kind, so that the graph does not end up with duplicate edges.
+//
+// Arguments:
+//
+//	startID string: The ID of the start node of the edge.
+//	endID string: The ID of the end node of the edge.
+//	oldKind string: The current kind of the edge.
+//	newKind string: The new kind of the edge.
+//
+// Returns:
+//
+//	error: An error if the edge does not exist, the new kind is invalid or the
+//	       change would duplicate an existing edge.
+func (g *OpenGraph) SetEdgeKind(startID, endID, oldKind, newKind string) error {
+	e := g.findEdge(startID, endID, oldKind)
+	if e == nil {
+		return fmt.Errorf("edge (%s)-[%s]->(%s) not found", startID, oldKind, endID)
+	}
+	if oldKind == newKind {
+		return nil
+	}
+	if g.findEdge(startID, endID, newKind) != nil {
+		return fmt.Errorf("edge (%s)-[%s]->(%s) already exists", startID, newKind, endID)
+	}
+	return e.SetKind(newKind)
+}
+
+// UpsertEdge adds an edge to the graph, or merges it into the edge with the
+// same endpoints and kind if there is one.
+//
+// A new edge is added with AddEdge, so it must pass the same validation.
+// Otherwise the properties of e are copied onto the existing edge, overwriting
+// existing values of the same keys.
+//
+// Arguments:
+//
+//	e *edge.Edge: The edge to add or merge.
+//
+// Returns:
+//
+//	bool: True if the edge was added, false if it was merged into an existing
+//	      edge or rejected by AddEdge.
+func (g *OpenGraph) UpsertEdge(e *edge.Edge) bool {
+	for _, existing := range g.edges {
+		if existing.Equal(e) {
+			for key, value := range e.GetProperties().GetAllProperties() {
+				existing.SetProperty(key, value)
+			}
+			return false
+		}
+	}
+	return g.AddEdge(e)
+}
+
+// findEdge returns the first edge matching the given endpoints and kind, or nil.
+func (g *OpenGraph) findEdge(startID, endID, kind string) *edge.Edge {
+	for _, e := range g.edges {
+		if e.GetStartNodeID() == startID && e.GetEndNodeID() == endID && e.GetKind() == kind {
+			return e
+		}
+	}
+	return nil
+}
+
+// SortEdges sorts the edges of the graph in place using the given comparator.
+//
+// Edges are kept in insertion order otherwise, which makes serialization depend
+// on the order in which edges were added. The sort is stable, so edges that
+// compare equal keep their relative order.
+//
+// Arguments:
+//
+//	less func(a, b *edge.Edge) bool: Reports whether a must be placed before b.
+func (g *OpenGraph) SortEdges(less func(a, b *edge.Edge) bool) {
+	sort.SliceStable(g.edges, func(i, j int) bool {
+		return less(g.edges[i], g.edges[j])
+	})
+	g.rebuildEdgeIndex()
+}
+
+// SortEdgesByKind sorts the edges of the graph in place by kind, then by start
+// node ID and end node ID.
+func (g *OpenGraph) SortEdgesByKind() {
+	g.SortEdgesBy("kind")
+}
+
+// SortEdgesBy sorts the edges of the graph in place by a field.
+//
+// The field is "start", "end" or "kind" to sort by start node ID, end node ID or
+// kind; any other value is a property key. Property values are compared
+// numerically when both are numbers and by their string representation
+// otherwise, and edges without the property come last. Ties are broken by start
+// node ID, end node ID and kind, so SortEdgesBy("start") produces the order
+// checked by IsSorted.
+//
+// Arguments:
+//
+//	field string: The field or property key to sort by.
+func (g *OpenGraph) SortEdgesBy(field string) {
+	var key func(e *edge.Edge) string
+	switch field {
+	case "start":
+		key = (*edge.Edge).GetStartNodeID
+	case "end":
+		key = (*edge.Edge).GetEndNodeID
+	case "kind":
+		key = (*edge.Edge).GetKind
+	}
+
+	g.SortEdges(func(a, b *edge.Edge) bool {
+		if key != nil {
+			if ka, kb := key(a), key(b); ka != kb {
+				return ka < kb
+			}
+			return edgeLess(a, b)
+		}
+
+		va, hasA := a.GetProperties().Properties[field]
+		vb, hasB := b.GetProperties().Properties[field]
+		if hasA != hasB {
+			return hasA
+		}
+		if hasA && !reflect.DeepEqual(va, vb) {
+			return propertyValueLess(va, vb)
+		}
+		return edgeLess(a, b)
+	})
+}
+
+// SortNodes returns the nodes of the graph sorted with the given comparator.
+//
+// Nodes are stored in a map, so the graph itself has no node order. The sort is
+// stable and starts from the nodes in ID order, so nodes that compare equal are
+// returned in ID order and the result is deterministic.
+//
+// Arguments:
+//
+//	less func(a, b *node.Node) bool: Reports whether a must be placed before b.
+//
+// Returns:
+//
+//	[]*node.Node: The nodes of the graph, sorted.
+func (g *OpenGraph) SortNodes(less func(a, b *node.Node) bool) []*node.Node {
+	nodes := g.sortedNodes()
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return less(nodes[i], nodes[j])
+	})
+	return nodes
+}
+
+// IsSorted reports whether the edges of the graph are sorted by start node ID,
+// end node ID and kind, as done by SortEdgesBy("start").
+func (g *OpenGraph) IsSorted() bool {
+	return sort.SliceIsSorted(g.edges, func(i, j int) bool {
+		return edgeLess(g.edges[i], g.edges[j])
+	})
+}
+
+// edgeLess orders edges by start node ID, end node ID and kind.
+func edgeLess(a, b *edge.Edge) bool {
+	if a.GetStartNodeID() != b.GetStartNodeID() {
+		return a.GetStartNodeID() < b.GetStartNodeID()
+	}
+	if a.GetEndNodeID() != b.GetEndNodeID() {
+		return a.GetEndNodeID() < b.GetEndNodeID()
+	}
+	return a.GetKind() < b.GetKind()
+}
+
+// propertyValueLess orders two property values, numerically when both are
+// numbers and by their string representation otherwise.
+func propertyValueLess(a, b interface{}) bool {
+	fa, aIsNumber := toFloat64(a)
+	fb, bIsNumber := toFloat64(b)
+	if aIsNumber && bIsNumber {
+		return fa < fb
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+// toFloat64 converts a numeric property value, including a json.Number, to
+// float64.
+func toFloat64(value interface{}) (float64, bool) {
+	if n, ok := value.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
 // Nodes operations
 
 // AddNode adds a node to the graph after performing validation checks.
@@ -110,16 +460,22 @@ func (g *OpenGraph) AddEdgeWithoutValidation(edge *edge.Edge) bool {
 //	bool: True if the node was successfully added, false if validation failed
 //	      (e.g., node already exists or has an invalid ID).
 func (g *OpenGraph) AddNode(node *node.Node) bool {
+	unlock := g.lock()
 	if _, exists := g.nodes[node.GetID()]; exists {
+		unlock()
 		return false
 	}
 
 	// Add source kind if specified and not already present
-	if g.sourceKind != "" && !node.HasKind(g.sourceKind) {
+	if g.autoSourceKind && g.sourceKind != "" && !node.HasKind(g.sourceKind) {
 		node.AddKind(g.sourceKind)
 	}
 
-	return g.AddNodeWithoutValidation(node)
+	g.putNode(node)
+	unlock()
+
+	g.notifyNode(hookNodeAdded, node)
+	return true
 }
 
 // AddNodeWithoutValidation adds a node to the graph without validating the node.
@@ -136,10 +492,26 @@ func (g *OpenGraph) AddNode(node *node.Node) bool {
 //
 //	bool: True if the node was successfully added.
 func (g *OpenGraph) AddNodeWithoutValidation(node *node.Node) bool {
-	g.nodes[node.GetID()] = node
+	unlock := g.lock()
+	g.putNode(node)
+	unlock()
+
+	g.notifyNode(hookNodeAdded, node)
 	return true
 }
 
+// putNode stores n in the graph, replacing the node with the same ID if there
+// is one, and indexes it.
+func (g *OpenGraph) putNode(n *node.Node) {
+	if replaced, exists := g.nodes[n.GetID()]; exists {
+		g.unindexNode(replaced)
+		g.removeFromKindIndex(replaced)
+	}
+	g.nodes[n.GetID()] = n
+	g.indexNode(n)
+	g.addToKindIndex(n)
+}
+
 // RemoveNodeByID removes a node and its associated edges after performing validation checks.
 //
 // It verifies that the node exists in the graph,
@@ -155,24 +527,329 @@ func (g *OpenGraph) AddNodeWithoutValidation(node *node.Node) bool {
 //	bool: True if the node was successfully removed, false if validation failed
 //	      (e.g., node does not exist or has an invalid ID).
 func (g *OpenGraph) RemoveNodeByID(id string) bool {
-	if _, exists := g.nodes[id]; !exists {
+	unlock := g.lock()
+	removed, exists := g.nodes[id]
+	if !exists {
+		unlock()
 		return false
 	}
 
 	delete(g.nodes, id)
+	g.unindexNode(removed)
+	g.removeFromKindIndex(removed)
 
 	// Remove associated edges
 	newEdges := make([]*edge.Edge, 0)
+	var removedEdges []*edge.Edge
 	for _, e := range g.edges {
 		if e.GetStartNodeID() != id && e.GetEndNodeID() != id {
 			newEdges = append(newEdges, e)
+		} else {
+			removedEdges = append(removedEdges, e)
 		}
 	}
 	g.edges = newEdges
+	g.rebuildEdgeIndex()
+	unlock()
+
+	g.notifyNode(hookNodeRemoved, removed)
+	for _, e := range removedEdges {
+		g.notifyEdge(hookEdgeRemoved, e)
+	}
 
 	return true
 }
 
+// ContractNode replaces a node with direct edges between its neighbors, e.g. to
+// remove relay nodes from attack paths.
+//
+// For every edge P -> node and every edge node -> S, an edge P -> S of kind
+// edgeKind is added. P and S may be the same node, which then gets a self-loop.
+// With keepEdges, an edge is added for every such pair of edges, even if the
+// graph already has an edge P -> S of kind edgeKind. Otherwise it is added only
+// when there is none yet. The node and all its edges are then removed, firing
+// the same hooks as RemoveNodeByID.
+//
+// Arguments:
+//
+//	id string: The ID of the node to contract.
+//	edgeKind string: The kind of the edges linking its predecessors to its
+//	                 successors.
+//	keepEdges bool: Whether to add the new edges even when they duplicate
+//	                existing ones.
+//
+// Returns:
+//
+//	error: An error if the node does not exist or edgeKind is not a valid edge
+//	       kind, in which case the graph is unchanged.
+func (g *OpenGraph) ContractNode(id string, edgeKind string, keepEdges bool) error {
+	if _, exists := g.nodes[id]; !exists {
+		return fmt.Errorf("node '%s' not found", id)
+	}
+
+	var shortcuts []*edge.Edge
+	for _, in := range g.GetEdgesToNode(id) {
+		for _, out := range g.GetEdgesFromNode(id) {
+			start, end := in.GetStartNodeID(), out.GetEndNodeID()
+			if start == id || end == id {
+				continue
+			}
+			shortcut, err := edge.NewEdge(start, end, edgeKind, nil)
+			if err != nil {
+				return fmt.Errorf("failed to create edge (%s)-[%s]->(%s): %w", start, edgeKind, end, err)
+			}
+			shortcuts = append(shortcuts, shortcut)
+		}
+	}
+
+	g.RemoveNodeByID(id)
+	for _, shortcut := range shortcuts {
+		start, end := shortcut.GetStartNodeID(), shortcut.GetEndNodeID()
+		switch {
+		case g.nodes[start] == nil || g.nodes[end] == nil:
+			// The neighbor is referenced by an orphaned edge.
+		case keepEdges:
+			// AddEdge would reject parallel edges outside multigraph mode.
+			g.AddEdgeWithoutValidation(shortcut)
+		case g.findEdge(start, end, edgeKind) == nil:
+			g.AddEdge(shortcut)
+		}
+	}
+	return nil
+}
+
+// UpdateNodeProperties sets several properties on a node of the graph at once.
+//
+// All values are validated before any of them is applied, so an invalid value
+// leaves the node unchanged. Properties whose keys are not in updates are left
+// untouched.
+//
+// Arguments:
+//
+//	id string: The ID of the node to update.
+//	updates map[string]interface{}: The properties to set on the node.
+//
+// Returns:
+//
+//	error: An error if the node does not exist or a property value is invalid.
+func (g *OpenGraph) UpdateNodeProperties(id string, updates map[string]interface{}) error {
+	n, exists := g.nodes[id]
+	if !exists {
+		return fmt.Errorf("node '%s' not found", id)
+	}
+	if err := validatePropertyUpdates(n.GetProperties(), updates); err != nil {
+		return err
+	}
+	g.unindexNode(n)
+	for key, value := range updates {
+		n.SetProperty(key, value)
+	}
+	g.indexNode(n)
+	return nil
+}
+
+// UpsertNode adds a node to the graph, or merges it into the node with the same
+// ID if there is one.
+//
+// A new node is added with AddNode. Otherwise the kinds of n are added to the
+// existing node, skipping kinds it already has (kinds beyond node.MaxKinds are
+// dropped), and the properties of n are copied onto it, overwriting existing
+// values of the same keys. The existing node is kept in the graph, so n itself
+// is not stored in that case.
+//
+// Arguments:
+//
+//	n *node.Node: The node to add or merge.
+//
+// Returns:
+//
+//	bool: True if the node was added, false if it was merged into an existing node.
+func (g *OpenGraph) UpsertNode(n *node.Node) bool {
+	existing, exists := g.nodes[n.GetID()]
+	if !exists {
+		return g.AddNode(n)
+	}
+
+	g.unindexNode(existing)
+	g.removeFromKindIndex(existing)
+	for _, kind := range n.GetKinds() {
+		existing.AddKind(kind)
+	}
+	g.addToKindIndex(existing)
+	for key, value := range n.GetProperties().GetAllProperties() {
+		existing.SetProperty(key, value)
+	}
+	g.indexNode(existing)
+	return false
+}
+
+// ReplaceNodeKinds replaces all the kinds of a node of the graph, as
+// node.ReplaceKinds does. The source kind of the graph, if set, is added back
+// when kinds does not hold it, as AddNode does.
+//
+// Arguments:
+//
+//	id string: The ID of the node to update.
+//	kinds []string: The new kinds of the node.
+//
+// Returns:
+//
+//	error: An error if the node does not exist or would have more than
+//	       node.MaxKinds kinds.
+func (g *OpenGraph) ReplaceNodeKinds(id string, kinds []string) error {
+	n, exists := g.nodes[id]
+	if !exists {
+		return fmt.Errorf("node '%s' not found", id)
+	}
+	if g.autoSourceKind && g.sourceKind != "" {
+		kinds = append(append([]string{}, kinds...), g.sourceKind)
+	}
+	g.removeFromKindIndex(n)
+	err := n.ReplaceKinds(kinds)
+	g.addToKindIndex(n)
+	return err
+}
+
+// ReplaceNodeID changes the ID of a node of the graph, e.g. to replace a
+// provisional ID, keeping the graph consistent.
+//
+// The id-matched endpoints of the edges referencing the node are updated to
+// the new ID, as are the edge, kind and property indexes. Use it instead of
+// node.SetID for a node already in the graph.
+//
+// Arguments:
+//
+//	oldID string: The current ID of the node.
+//	newID string: The new ID of the node.
+//
+// Returns:
+//
+//	error: An error if the node does not exist, if newID is empty or if another
+//	       node already has newID.
+func (g *OpenGraph) ReplaceNodeID(oldID, newID string) error {
+	n, exists := g.nodes[oldID]
+	if !exists {
+		return fmt.Errorf("node '%s' not found", oldID)
+	}
+	if newID == "" {
+		return fmt.Errorf("node ID cannot be empty")
+	}
+	if newID == oldID {
+		return nil
+	}
+	if _, taken := g.nodes[newID]; taken {
+		return fmt.Errorf("node '%s' already exists", newID)
+	}
+
+	g.unindexNode(n)
+	g.removeFromKindIndex(n)
+	n.SetID(newID)
+	delete(g.nodes, oldID)
+	g.nodes[newID] = n
+	g.addToKindIndex(n)
+	g.indexNode(n)
+
+	for _, e := range g.edges {
+		if start := e.GetStart(); start.GetMatchBy() == edge.MatchByID && start.GetValue() == oldID {
+			e.SetStart(edge.NewEndpointByID(newID))
+		}
+		if end := e.GetEnd(); end.GetMatchBy() == edge.MatchByID && end.GetValue() == oldID {
+			e.SetEnd(edge.NewEndpointByID(newID))
+		}
+	}
+	g.rebuildEdgeIndex()
+	return nil
+}
+
+// SetEdgeEndpoints changes the start and end endpoints of an edge of the graph,
+// keeping the edge index consistent.
+//
+// The endpoints are validated as in AddEdge. Use it instead of edge.SetStart and
+// edge.SetEnd for an edge already in the graph.
+//
+// Arguments:
+//
+//	e *edge.Edge: The edge to update.
+//	start edge.Endpoint: The new start endpoint.
+//	end edge.Endpoint: The new end endpoint.
+//
+// Returns:
+//
+//	error: An error if the edge is not in the graph, if an endpoint is invalid
+//	       or references a missing node, or if the change would duplicate an
+//	       existing edge. The edge is left unchanged on error.
+func (g *OpenGraph) SetEdgeEndpoints(e *edge.Edge, start, end edge.Endpoint) error {
+	if !containsEdgePointer(g.edges, e) {
+		return fmt.Errorf("edge not found in graph")
+	}
+	candidate, err := edge.NewEdgeWithEndpoints(start, end, e.GetKind(), e.GetProperties())
+	if err != nil {
+		return err
+	}
+	for _, endpoint := range []edge.Endpoint{start, end} {
+		if endpoint.GetMatchBy() != edge.MatchByID {
+			continue
+		}
+		if _, exists := g.nodes[endpoint.GetValue()]; !exists {
+			return fmt.Errorf("node '%s' not found", endpoint.GetValue())
+		}
+	}
+	if !g.multiEdges {
+		for _, existing := range g.edges {
+			if existing != e && existing.Equal(candidate) {
+				return fmt.Errorf("edge (%s)-[%s]->(%s) already exists",
+					candidate.GetStartNodeID(), candidate.GetKind(), candidate.GetEndNodeID())
+			}
+		}
+	}
+
+	e.SetStart(start)
+	e.SetEnd(end)
+	g.rebuildEdgeIndex()
+	return nil
+}
+
+// BulkSetPropertyOnNodesByKind sets a property on every node of a kind, e.g.
+// "enabled" on all User nodes after an import.
+//
+// Arguments:
+//
+//	kind string: The kind of the nodes to update.
+//	key string: The property key to set.
+//	value interface{}: The property value to set.
+//
+// Returns:
+//
+//	int: The number of nodes updated, or -1 if value is not a valid property
+//	     value, in which case no node is updated.
+func (g *OpenGraph) BulkSetPropertyOnNodesByKind(kind string, key string, value interface{}) int {
+	if !properties.NewProperties().IsPropertyValueValid(value) {
+		return -1
+	}
+
+	count := 0
+	for _, n := range g.nodes {
+		if !n.HasKind(kind) {
+			continue
+		}
+		g.unindexNode(n)
+		n.SetProperty(key, value)
+		g.indexNode(n)
+		count++
+	}
+	return count
+}
+
+// validatePropertyUpdates reports the first value in updates that p would reject.
+func validatePropertyUpdates(p *properties.Properties, updates map[string]interface{}) error {
+	for key, value := range updates {
+		if !p.IsPropertyValueValid(value) {
+			return fmt.Errorf("invalid value for property '%s': %T is not a primitive type or homogeneous array of primitives", key, value)
+		}
+	}
+	return nil
+}
+
 // HasNode checks if a node exists in the graph after performing validation checks.
 //
 // It verifies that the node exists in the graph,
@@ -211,6 +888,7 @@ func (g *OpenGraph) HasNode(n *node.Node) bool {
 //	*node.Node: The node if it exists, nil if validation failed
 //	             (e.g., node does not exist or has an invalid ID).
 func (g *OpenGraph) GetNode(id string) *node.Node {
+	defer g.rlock()()
 	return g.nodes[id]
 }
 
@@ -220,6 +898,12 @@ func (g *OpenGraph) GetNode(id string) *node.Node {
 // and that the nodes exist in the graph. If any validation fails,
 // the nodes are not returned.
 //
+// The nodes are looked up in a kind index built on the first call and kept
+// until nodes are added or removed or their kinds change through the graph, or
+// kept up to date with WithKindIndex. Kinds changed directly on a node (e.g.
+// with node.AddKind) are not seen until then; use AddKindToNode and
+// RemoveKindFromNode instead.
+//
 // Arguments:
 //
 //	kind string: The kind of nodes to be returned from the graph.
@@ -229,9 +913,95 @@ func (g *OpenGraph) GetNode(id string) *node.Node {
 //	[]*node.Node: The nodes if they exist, nil if validation failed
 //	              (e.g., kind is not valid or nodes do not exist).
 func (g *OpenGraph) GetNodesByKind(kind string) []*node.Node {
+	unlock := g.lock()
+	defer unlock()
+	g.buildKindIndex()
+
 	var nodes []*node.Node
-	for _, n := range g.nodes {
-		if n.HasKind(kind) {
+	for id := range g.kindIndex[kind] {
+		nodes = append(nodes, g.nodes[id])
+	}
+	return nodes
+}
+
+// GetNodesByAllKinds returns all nodes having every one of the given kinds.
+//
+// Arguments:
+//
+//	kinds []string: The kinds the nodes must all have. An empty slice matches
+//	                every node.
+//
+// Returns:
+//
+//	[]*node.Node: The matching nodes, in ID order, or an empty slice if none match.
+func (g *OpenGraph) GetNodesByAllKinds(kinds []string) []*node.Node {
+	nodes := make([]*node.Node, 0)
+	for _, n := range g.sortedNodes() {
+		if n.HasAllKinds(kinds) {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// GetNodesByAnyKind returns all nodes having at least one of the given kinds.
+//
+// Arguments:
+//
+//	kinds []string: The kinds the nodes must have one of. An empty slice matches
+//	                no node.
+//
+// Returns:
+//
+//	[]*node.Node: The matching nodes, in ID order, or an empty slice if none match.
+func (g *OpenGraph) GetNodesByAnyKind(kinds []string) []*node.Node {
+	nodes := make([]*node.Node, 0)
+	for _, n := range g.sortedNodes() {
+		if n.HasAnyKind(kinds) {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// GetNodesExcludingKinds returns all nodes having none of the given kinds,
+// e.g. to leave out nodes carrying a marker kind such as "Base".
+//
+// Arguments:
+//
+//	excludeKinds []string: The kinds the nodes must not have. An empty slice
+//	                       leaves out no node.
+//
+// Returns:
+//
+//	[]*node.Node: The remaining nodes, in ID order, or an empty slice if there
+//	              are none.
+func (g *OpenGraph) GetNodesExcludingKinds(excludeKinds []string) []*node.Node {
+	nodes := make([]*node.Node, 0)
+	for _, n := range g.sortedNodes() {
+		if !n.HasAnyKind(excludeKinds) {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// GetNodesBySourceKind returns all nodes having the source kind of the graph.
+//
+// AddNode adds the source kind to every node, so the nodes missing from the
+// result were added with AddNodeWithoutValidation or lost the kind afterwards.
+//
+// Returns:
+//
+//	[]*node.Node: The matching nodes, in ID order. The slice is empty if the graph
+//	              has no source kind.
+func (g *OpenGraph) GetNodesBySourceKind() []*node.Node {
+	nodes := make([]*node.Node, 0)
+	if g.sourceKind == "" {
+		return nodes
+	}
+	for _, n := range g.sortedNodes() {
+		if n.HasKind(g.sourceKind) {
 			nodes = append(nodes, n)
 		}
 	}
@@ -285,6 +1055,33 @@ func (g *OpenGraph) GetEdgesByKind(kind string) []*edge.Edge {
 	return edges
 }
 
+// GetEdgesExcludingKinds returns all edges whose kind is not one of the given
+// kinds, e.g. to leave known noisy relationships out of an analysis.
+//
+// Arguments:
+//
+//	excludeKinds []string: The kinds of the edges to leave out. An empty slice
+//	                       leaves out no edge.
+//
+// Returns:
+//
+//	[]*edge.Edge: The remaining edges, in insertion order, or an empty slice if
+//	              there are none.
+func (g *OpenGraph) GetEdgesExcludingKinds(excludeKinds []string) []*edge.Edge {
+	excluded := make(map[string]bool, len(excludeKinds))
+	for _, kind := range excludeKinds {
+		excluded[kind] = true
+	}
+
+	edges := make([]*edge.Edge, 0)
+	for _, e := range g.edges {
+		if !excluded[e.GetKind()] {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
 // GetEdgesFromNode returns all edges starting from a node after performing validation checks.
 //
 // It verifies that the node exists in the graph,
@@ -300,13 +1097,8 @@ func (g *OpenGraph) GetEdgesByKind(kind string) []*edge.Edge {
 //	[]*edge.Edge: The edges if they exist, nil if validation failed
 //	              (e.g., node does not exist or has an invalid ID).
 func (g *OpenGraph) GetEdgesFromNode(id string) []*edge.Edge {
-	var edges []*edge.Edge
-	for _, e := range g.edges {
-		if e.GetStartNodeID() == id {
-			edges = append(edges, e)
-		}
-	}
-	return edges
+	defer g.rlock()()
+	return append([]*edge.Edge(nil), g.outEdges[id]...)
 }
 
 // GetEdgesToNode returns all edges ending at a node after performing validation checks.
@@ -324,15 +1116,163 @@ func (g *OpenGraph) GetEdgesFromNode(id string) []*edge.Edge {
 //	[]*edge.Edge: The edges if they exist, nil if validation failed
 //	              (e.g., node does not exist or has an invalid ID).
 func (g *OpenGraph) GetEdgesToNode(id string) []*edge.Edge {
-	var edges []*edge.Edge
+	defer g.rlock()()
+	return append([]*edge.Edge(nil), g.inEdges[id]...)
+}
+
+// GetEdgesByStartAndKind returns the edges of a kind starting from a node.
+//
+// The edges are looked up in the index of the edges by start node, so this
+// takes time proportional to the out-degree of the node rather than to the
+// number of edges of the graph.
+//
+// Arguments:
+//
+//	startID string: The ID of the start node.
+//	kind string: The kind of the edges.
+//
+// Returns:
+//
+//	[]*edge.Edge: The matching edges, in insertion order, or an empty slice if
+//	              none match.
+func (g *OpenGraph) GetEdgesByStartAndKind(startID, kind string) []*edge.Edge {
+	return edgesOfKind(g.outEdges[startID], kind)
+}
+
+// GetEdgesByEndAndKind returns the edges of a kind ending at a node, in time
+// proportional to the in-degree of the node, like GetEdgesByStartAndKind.
+//
+// Arguments:
+//
+//	endID string: The ID of the end node.
+//	kind string: The kind of the edges.
+//
+// Returns:
+//
+//	[]*edge.Edge: The matching edges, in insertion order, or an empty slice if
+//	              none match.
+func (g *OpenGraph) GetEdgesByEndAndKind(endID, kind string) []*edge.Edge {
+	return edgesOfKind(g.inEdges[endID], kind)
+}
+
+// GetEdgesFromNodeByKind returns the edges of a kind starting from a node. It
+// is the kind-filtered counterpart of GetEdgesFromNode and is equivalent to
+// GetEdgesByStartAndKind.
+//
+// Arguments:
+//
+//	id string: The ID of the node to get edges from.
+//	kind string: The kind of the edges.
+//
+// Returns:
+//
+//	[]*edge.Edge: The matching edges, in insertion order, or an empty slice if
+//	              none match.
+func (g *OpenGraph) GetEdgesFromNodeByKind(id, kind string) []*edge.Edge {
+	return edgesOfKind(g.outEdges[id], kind)
+}
+
+// GetEdgesToNodeByKind returns the edges of a kind ending at a node. It is the
+// kind-filtered counterpart of GetEdgesToNode and is equivalent to
+// GetEdgesByEndAndKind.
+//
+// Arguments:
+//
+//	id string: The ID of the node to get edges to.
+//	kind string: The kind of the edges.
+//
+// Returns:
+//
+//	[]*edge.Edge: The matching edges, in insertion order, or an empty slice if
+//	              none match.
+func (g *OpenGraph) GetEdgesToNodeByKind(id, kind string) []*edge.Edge {
+	return edgesOfKind(g.inEdges[id], kind)
+}
+
+// GetEdgesByNodePair returns all edges directed from one node to another,
+// regardless of their kind.
+//
+// Arguments:
+//
+//	startID string: The ID of the start node.
+//	endID string: The ID of the end node.
+//
+// Returns:
+//
+//	[]*edge.Edge: The edges from startID to endID, in insertion order. The slice is
+//	              empty if either node does not exist or no such edge exists.
+func (g *OpenGraph) GetEdgesByNodePair(startID, endID string) []*edge.Edge {
+	edges := make([]*edge.Edge, 0)
+	if g.GetNode(startID) == nil || g.GetNode(endID) == nil {
+		return edges
+	}
 	for _, e := range g.edges {
-		if e.GetEndNodeID() == id {
+		if e.GetStartNodeID() == startID && e.GetEndNodeID() == endID {
 			edges = append(edges, e)
 		}
 	}
 	return edges
 }
 
+// GetEdgesBetweenNodes returns all edges between two nodes, in either direction,
+// regardless of their kind.
+//
+// Arguments:
+//
+//	id1 string: The ID of the first node.
+//	id2 string: The ID of the second node.
+//
+// Returns:
+//
+//	[]*edge.Edge: The edges from id1 to id2 and from id2 to id1, in insertion order.
+//	              The slice is empty if either node does not exist or no such edge
+//	              exists.
+func (g *OpenGraph) GetEdgesBetweenNodes(id1, id2 string) []*edge.Edge {
+	edges := make([]*edge.Edge, 0)
+	if g.GetNode(id1) == nil || g.GetNode(id2) == nil {
+		return edges
+	}
+	for _, e := range g.edges {
+		start, end := e.GetStartNodeID(), e.GetEndNodeID()
+		if (start == id1 && end == id2) || (start == id2 && end == id1) {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// GetEdgesBetweenSets returns all edges crossing between two sets of nodes, in
+// either direction, e.g. to check whether connected components are linked.
+//
+// An edge crosses when it starts in setA and ends in setB, or starts in setB
+// and ends in setA. Edges within a single set or with an endpoint in neither
+// set are excluded; a node present in both sets belongs to each.
+//
+// Arguments:
+//
+//	setA []string: The IDs of the nodes of the first set.
+//	setB []string: The IDs of the nodes of the second set.
+//
+// Returns:
+//
+//	[]*edge.Edge: The crossing edges, in insertion order, or an empty slice if
+//	              there are none.
+func (g *OpenGraph) GetEdgesBetweenSets(setA, setB []string) []*edge.Edge {
+	inA := make(map[string]bool, len(setA))
+	for _, id := range setA {
+		inA[id] = true
+	}
+	inB := make(map[string]bool, len(setB))
+	for _, id := range setB {
+		inB[id] = true
+	}
+
+	return g.FilterEdgesByPredicate(func(e *edge.Edge) bool {
+		start, end := e.GetStartNodeID(), e.GetEndNodeID()
+		return (inA[start] && inB[end]) || (inB[start] && inA[end])
+	})
+}
+
 // Metadata operations
 
 // GetSourceKind returns the source kind of the graph after performing validation checks.
@@ -349,22 +1289,35 @@ func (g *OpenGraph) GetSourceKind() string {
 	return g.sourceKind
 }
 
-// SetSourceKind sets the source kind of the graph after performing validation checks.
+// SetSourceKind sets the source kind of the graph.
 //
-// It verifies that the source kind exists in the graph,
-// and that the source kind has a valid ID. If any validation fails,
-// the source kind is not set.
+// The source kind is added to every node already in the graph that lacks it,
+// as AddNode does for new nodes; nodes that already have node.MaxKinds kinds
+// are left unchanged. Setting an empty source kind clears it without changing
+// any node.
 //
 // Arguments:
 //
 //	sourceKind string: The source kind to set for the graph.
+func (g *OpenGraph) SetSourceKind(sourceKind string) {
+	g.sourceKind = sourceKind
+	if sourceKind == "" || !g.autoSourceKind {
+		return
+	}
+	for _, n := range g.nodes {
+		g.removeFromKindIndex(n)
+		n.AddKind(sourceKind)
+		g.addToKindIndex(n)
+	}
+}
+
+// HasSourceKind reports whether the graph has a source kind.
 //
 // Returns:
 //
-//	string: The source kind if it exists, nil if validation failed
-//	           (e.g., source kind does not exist or has an invalid ID).
-func (g *OpenGraph) SetSourceKind(sourceKind string) {
-	g.sourceKind = sourceKind
+//	bool: True if the source kind is not empty.
+func (g *OpenGraph) HasSourceKind() bool {
+	return g.sourceKind != ""
 }
 
 // Graph operations
@@ -386,6 +1339,30 @@ func (g *OpenGraph) SetSourceKind(sourceKind string) {
 //	[][]string: The paths if they exist, nil if validation failed
 //	             (e.g., start or end node does not exist or has an invalid ID).
 func (g *OpenGraph) FindPaths(startID, endID string, maxDepth int) [][]string {
+	return g.FindPathsWithFilter(startID, endID, maxDepth, nil, nil)
+}
+
+// FindPathsWithFilter finds paths between two nodes using BFS, like FindPaths,
+// traversing only the edges and nodes accepted by the filters.
+//
+// edgeFilter restricts the edges that can be followed, e.g. only MemberOf edges,
+// and nodeFilter restricts the intermediate nodes a path can go through, e.g.
+// only Computer nodes. The start and end nodes are not subject to nodeFilter.
+// A nil filter accepts everything.
+//
+// Arguments:
+//
+//	startID string: The ID of the start node.
+//	endID string: The ID of the end node.
+//	maxDepth int: The maximum depth of the paths to find.
+//	edgeFilter func(*edge.Edge) bool: Reports whether an edge can be traversed.
+//	nodeFilter func(*node.Node) bool: Reports whether a node can be visited.
+//
+// Returns:
+//
+//	[][]string: The paths if they exist, nil if validation failed
+//	             (e.g., start or end node does not exist or has an invalid ID).
+func (g *OpenGraph) FindPathsWithFilter(startID, endID string, maxDepth int, edgeFilter func(*edge.Edge) bool, nodeFilter func(*node.Node) bool) [][]string {
 	if _, exists := g.nodes[startID]; !exists {
 		return nil
 	}
@@ -405,30 +1382,168 @@ func (g *OpenGraph) FindPaths(startID, endID string, maxDepth int) [][]string {
 	}{{startID, []string{startID}}}
 	visited[startID] = true
 
-	for len(queue) > 0 && len(queue[0].path) <= maxDepth {
-		current := queue[0]
-		queue = queue[1:]
+	for len(queue) > 0 && len(queue[0].path) <= maxDepth {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, edge := range g.GetEdgesFromNode(current.id) {
+			if edgeFilter != nil && !edgeFilter(edge) {
+				continue
+			}
+			nextID := edge.GetEndNodeID()
+			if !visited[nextID] {
+				newPath := append([]string{}, current.path...)
+				newPath = append(newPath, nextID)
+
+				if nextID == endID {
+					paths = append(paths, newPath)
+				} else {
+					next, exists := g.nodes[nextID]
+					if nodeFilter != nil && (!exists || !nodeFilter(next)) {
+						continue
+					}
+					visited[nextID] = true
+					queue = append(queue, struct {
+						id   string
+						path []string
+					}{nextID, newPath})
+				}
+			}
+		}
+	}
+
+	return paths
+}
+
+// IsReachable reports whether a node can be reached from another by following
+// edges in their direction. A node is always reachable from itself.
+//
+// Arguments:
+//
+//	startID string: The ID of the node to start from.
+//	endID string: The ID of the node to reach.
+//
+// Returns:
+//
+//	bool: True if there is a path from startID to endID, false otherwise or if
+//	      either node does not exist.
+func (g *OpenGraph) IsReachable(startID, endID string) bool {
+	if _, exists := g.nodes[startID]; !exists {
+		return false
+	}
+	if _, exists := g.nodes[endID]; !exists {
+		return false
+	}
+
+	adjacency := g.successors()
+	visited := map[string]bool{startID: true}
+	queue := []string{startID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == endID {
+			return true
+		}
+		for _, next := range adjacency[current] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// GetDisconnectedNodePairs returns every ordered pair of distinct nodes (A, B)
+// such that B is not reachable from A, as reported by IsReachable.
+//
+// A breadth-first search is run from every node, so this takes O(N*(N+E))
+// time and the result can hold up to N*(N-1) pairs; see
+// GetSampledDisconnectedPairs for large graphs.
+//
+// Returns:
+//
+//	[][2]string: The pairs, ordered by start node ID then end node ID, or an
+//	             empty slice if every node can reach every other one.
+func (g *OpenGraph) GetDisconnectedNodePairs() [][2]string {
+	adjacency := g.successors()
+	ids := g.sortedNodeIDs()
+	pairs := make([][2]string, 0)
+	for _, from := range ids {
+		reachable := reachableFrom(from, adjacency)
+		for _, to := range ids {
+			if !reachable[to] {
+				pairs = append(pairs, [2]string{from, to})
+			}
+		}
+	}
+	return pairs
+}
 
-		for _, edge := range g.GetEdgesFromNode(current.id) {
-			nextID := edge.GetEndNodeID()
-			if !visited[nextID] {
-				newPath := append([]string{}, current.path...)
-				newPath = append(newPath, nextID)
+// GetSampledDisconnectedPairs checks up to samples randomly chosen ordered
+// pairs of distinct nodes and returns those where the end node is not
+// reachable from the start node, as GetDisconnectedNodePairs does for every
+// pair.
+//
+// Pairs are drawn without replacement, and every pair is checked when samples
+// is at least N*(N-1). When samples is more than half of N*(N-1), the pairs are
+// drawn from an enumeration of all of them rather than by rejecting repeated
+// draws. Each distinct start node costs one breadth-first search.
+//
+// Arguments:
+//
+//	samples int: The number of pairs to check.
+//
+// Returns:
+//
+//	[][2]string: The disconnected pairs among the sampled ones, ordered by start
+//	             node ID then end node ID, or an empty slice if there are none
+//	             or samples is not positive.
+func (g *OpenGraph) GetSampledDisconnectedPairs(samples int) [][2]string {
+	ids := g.sortedNodeIDs()
+	// Computed in int64, as N*(N-1) overflows a 32-bit int from about 46k nodes.
+	total := int64(len(ids)) * int64(len(ids)-1)
+	if int64(samples) >= total {
+		return g.GetDisconnectedNodePairs()
+	}
 
-				if nextID == endID {
-					paths = append(paths, newPath)
-				} else {
-					visited[nextID] = true
-					queue = append(queue, struct {
-						id   string
-						path []string
-					}{nextID, newPath})
-				}
+	sampled := make(map[[2]string]bool, max(samples, 0))
+	if int64(samples) > total/2 {
+		// total < 2*samples fits in an int here. Pair k has start node
+		// k/(N-1) and the k%(N-1)-th other node as end node.
+		for _, k := range rand.Perm(int(total))[:samples] {
+			from, to := k/(len(ids)-1), k%(len(ids)-1)
+			if to >= from {
+				to++
 			}
+			sampled[[2]string{ids[from], ids[to]}] = true
+		}
+	}
+	for len(sampled) < samples {
+		from, to := ids[rand.IntN(len(ids))], ids[rand.IntN(len(ids))]
+		if from != to {
+			sampled[[2]string{from, to}] = true
 		}
 	}
 
-	return paths
+	adjacency := g.successors()
+	reachable := make(map[string]map[string]bool)
+	pairs := make([][2]string, 0)
+	for pair := range sampled {
+		if reachable[pair[0]] == nil {
+			reachable[pair[0]] = reachableFrom(pair[0], adjacency)
+		}
+		if !reachable[pair[0]][pair[1]] {
+			pairs = append(pairs, pair)
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+	return pairs
 }
 
 // GetConnectedComponents finds all connected components after performing validation checks.
@@ -480,6 +1595,58 @@ func (g *OpenGraph) GetConnectedComponents() []map[string]bool {
 	return components
 }
 
+// GetTopologicalGenerations groups the nodes of a directed acyclic graph into
+// layers: the first layer holds the nodes without incoming edges, and every
+// following layer the nodes whose predecessors are all in earlier layers.
+//
+// Disconnected parts of the graph are layered together, and edges whose
+// endpoints are not both nodes of the graph are ignored.
+//
+// Returns:
+//
+//	[][]string: The layers, each holding node IDs in sorted order.
+//	error: An error if the graph has a cycle, including a self-loop.
+func (g *OpenGraph) GetTopologicalGenerations() ([][]string, error) {
+	adjacency := g.successors()
+	inDegree := make(map[string]int, len(g.nodes))
+	for _, ends := range adjacency {
+		for _, end := range ends {
+			inDegree[end]++
+		}
+	}
+
+	var layer []string
+	for _, id := range g.sortedNodeIDs() {
+		if inDegree[id] == 0 {
+			layer = append(layer, id)
+		}
+	}
+
+	generations := make([][]string, 0)
+	layered := 0
+	for len(layer) > 0 {
+		generations = append(generations, layer)
+		layered += len(layer)
+
+		var next []string
+		for _, id := range layer {
+			for _, end := range adjacency[id] {
+				inDegree[end]--
+				if inDegree[end] == 0 {
+					next = append(next, end)
+				}
+			}
+		}
+		sort.Strings(next)
+		layer = next
+	}
+
+	if layered != len(g.nodes) {
+		return nil, fmt.Errorf("graph has a cycle: %d nodes cannot be layered", len(g.nodes)-layered)
+	}
+	return generations, nil
+}
+
 // ValidateGraph checks for common graph issues after performing validation checks.
 //
 // It verifies that the edges and nodes exist in the graph,
@@ -493,10 +1660,91 @@ func (g *OpenGraph) GetConnectedComponents() []map[string]bool {
 //	[]string: The errors if they exist, nil if validation failed
 //	           (e.g., edges or nodes do not exist or have an invalid ID).
 func (g *OpenGraph) ValidateGraph() []string {
+	errors := g.orphanedEdgeErrors()
+
+	// Check for nodes whose ID was changed with node.SetID instead of
+	// ReplaceNodeID
+	for _, id := range g.sortedNodeIDs() {
+		if actual := g.nodes[id].GetID(); actual != id {
+			errors = append(errors, fmt.Sprintf("Node stored under ID %s has ID %s", id, actual))
+		}
+	}
+
+	// Check for edges whose endpoints were changed with edge.SetStart or
+	// edge.SetEnd instead of SetEdgeEndpoints
+	errors = append(errors, g.edgeIndexErrors()...)
+
+	// Check for isolated nodes
+	var isolatedNodes []string
+	for id := range g.nodes {
+		if len(g.GetEdgesFromNode(id)) == 0 && len(g.GetEdgesToNode(id)) == 0 {
+			isolatedNodes = append(isolatedNodes, id)
+		}
+	}
+
+	if len(isolatedNodes) > 0 {
+		errors = append(errors, fmt.Sprintf("Found %d isolated nodes: %v",
+			len(isolatedNodes), isolatedNodes))
+	}
+
+	return errors
+}
+
+// edgeIndexErrors returns an error message for every edge missing from the
+// out- or in-edge list of its current endpoints, and for every stale entry of
+// the edge index.
+func (g *OpenGraph) edgeIndexErrors() []string {
 	var errors []string
+	for _, e := range g.edges {
+		if !containsEdgePointer(g.outEdges[e.GetStartNodeID()], e) || !containsEdgePointer(g.inEdges[e.GetEndNodeID()], e) {
+			errors = append(errors, fmt.Sprintf("Edge %s (%s -> %s) is not indexed under its endpoints",
+				e.GetKind(), e.GetStartNodeID(), e.GetEndNodeID()))
+		}
+	}
+	outIndexed, inIndexed := 0, 0
+	for _, edges := range g.outEdges {
+		outIndexed += len(edges)
+	}
+	for _, edges := range g.inEdges {
+		inIndexed += len(edges)
+	}
+	if outIndexed != len(g.edges) || inIndexed != len(g.edges) {
+		errors = append(errors, fmt.Sprintf("Edge index has %d out-edge and %d in-edge entries for %d edges",
+			outIndexed, inIndexed, len(g.edges)))
+	}
+	return errors
+}
+
+// GetOrphanedEdges returns the edges referencing a node missing from the graph.
+//
+// AddEdge rejects such edges, but they can be added with AddEdgeWithoutValidation
+// or loaded from a file. Only id-matched endpoints are checked; name- and
+// property-matched endpoints are resolved by BloodHound at ingestion time.
+//
+// Returns:
+//
+//	[]*edge.Edge: The orphaned edges, in insertion order, or an empty slice if
+//	              there are none.
+func (g *OpenGraph) GetOrphanedEdges() []*edge.Edge {
+	return g.FilterEdgesByPredicate(g.referencesMissingNode)
+}
+
+// FindDanglingReferences returns every edge referencing a node missing from the
+// graph, however it was added. It is an alias of GetOrphanedEdges.
+//
+// Returns:
+//
+//	[]*edge.Edge: The dangling edges, in insertion order, or an empty slice if
+//	              there are none.
+func (g *OpenGraph) FindDanglingReferences() []*edge.Edge {
+	return g.GetOrphanedEdges()
+}
 
-	// Check for orphaned edges. Only id-matched endpoints reference local nodes;
-	// name- and property-matched endpoints are resolved at ingestion time.
+// orphanedEdgeErrors describes every edge endpoint that references a node
+// missing from the graph. Only id-matched endpoints reference local nodes;
+// name- and property-matched endpoints are resolved at ingestion time.
+func (g *OpenGraph) orphanedEdgeErrors() []string {
+	var errors []string
 	for _, e := range g.edges {
 		start := e.GetStart()
 		if start.GetMatchBy() == edge.MatchByID {
@@ -514,19 +1762,6 @@ func (g *OpenGraph) ValidateGraph() []string {
 		}
 	}
 
-	// Check for isolated nodes
-	var isolatedNodes []string
-	for id := range g.nodes {
-		if len(g.GetEdgesFromNode(id)) == 0 && len(g.GetEdgesToNode(id)) == 0 {
-			isolatedNodes = append(isolatedNodes, id)
-		}
-	}
-
-	if len(isolatedNodes) > 0 {
-		errors = append(errors, fmt.Sprintf("Found %d isolated nodes: %v",
-			len(isolatedNodes), isolatedNodes))
-	}
-
 	return errors
 }
 
@@ -538,6 +1773,10 @@ func (g *OpenGraph) ValidateGraph() []string {
 // and that the nodes and edges have valid IDs. If any validation fails,
 // the JSON is not returned.
 //
+// The output is deterministic: nodes are written in ID order and edges ordered
+// by start node ID, end node ID and kind, without reordering the edges of the
+// graph itself. Two graphs with the same content export byte-identical JSON.
+//
 // Arguments:
 //
 // includeMetadata bool: Whether to include metadata in the JSON.
@@ -548,13 +1787,70 @@ func (g *OpenGraph) ValidateGraph() []string {
 //	        (e.g., nodes or edges do not exist or have an invalid ID).
 //	error: An error if the JSON is not returned.
 func (g *OpenGraph) ExportJSON(includeMetadata bool) (string, error) {
+	return g.exportJSON(g.sortedNodes(), g.sortedEdges(), includeMetadata)
+}
+
+// ExportJSONSorted exports the graph to JSON format with a deterministic layout.
+//
+// The output is the same as ExportJSON, but the edges of the graph are also
+// sorted in place with SortEdgesBy("start"), so that later iterations over the
+// edges follow the exported order.
+//
+// Arguments:
+//
+// includeMetadata bool: Whether to include metadata in the JSON.
+//
+// Returns:
+//
+//	string: The JSON representation of the graph.
+//	error: An error if the graph could not be marshaled.
+func (g *OpenGraph) ExportJSONSorted(includeMetadata bool) (string, error) {
+	g.SortEdgesBy("start")
+	return g.exportJSON(g.sortedNodes(), g.edges, includeMetadata)
+}
+
+// GetConsistencyHash returns a fingerprint of the content of the graph.
+//
+// The graph is serialized to a canonical JSON document, laid out as by
+// ExportJSON and including the source kind metadata, and the document is
+// hashed with SHA-256. Graphs holding the same nodes, edges and properties
+// have the same hash regardless of insertion order, which makes it suitable for
+// cache invalidation or drift detection. The graph itself is not modified.
+//
+// Returns:
+//
+//	string: The hex-encoded SHA-256 hash of the canonical document.
+//	error: An error if the graph could not be marshaled.
+func (g *OpenGraph) GetConsistencyHash() (string, error) {
+	jsonData, err := json.Marshal(g.toDocument(g.sortedNodes(), g.sortedEdges(), true))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(jsonData)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// exportJSON marshals the given nodes and edges, in order, to the BloodHound
+// OpenGraph JSON format.
+func (g *OpenGraph) exportJSON(nodes []*node.Node, edges []*edge.Edge, includeMetadata bool) (string, error) {
+	jsonData, err := json.MarshalIndent(g.toDocument(nodes, edges, includeMetadata), "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonData), nil
+}
+
+// toDocument builds the BloodHound OpenGraph document holding the given nodes
+// and edges, in order, ready to be marshaled.
+func (g *OpenGraph) toDocument(nodes []*node.Node, edges []*edge.Edge, includeMetadata bool) map[string]interface{} {
 	graphData := make(map[string]interface{})
 	graphContent := make(map[string]interface{})
 
 	// Convert nodes to dict format
 	// Initialize nodesData as an empty slice (not nil) so it marshals to [] instead of null if no nodes exist.
-	nodesData := make([]map[string]interface{}, 0, len(g.nodes))
-	for _, n := range g.nodes {
+	nodesData := make([]map[string]interface{}, 0, len(nodes))
+	for _, n := range nodes {
 		nodesData = append(nodesData, n.ToDict())
 	}
 	graphContent["nodes"] = nodesData
@@ -562,12 +1858,12 @@ func (g *OpenGraph) ExportJSON(includeMetadata bool) (string, error) {
 	// Convert edges to dict format
 	// Initialize edgesData as an empty slice (not nil) so it marshals to [] instead of null if no edges exist.
 	// The original `var edgesData []map[string]interface{}` declares a nil slice.
-	// If `g.edges` is empty, the loop is skipped, and `edgesData` remains nil,
+	// If `edges` is empty, the loop is skipped, and `edgesData` remains nil,
 	// which `json.Marshal` converts to `null`.
 	// By using `make([]map[string]interface{}, 0)`, it's explicitly an empty slice,
 	// which `json.Marshal` converts to `[]`.
-	edgesData := make([]map[string]interface{}, 0, len(g.edges))
-	for _, e := range g.edges {
+	edgesData := make([]map[string]interface{}, 0, len(edges))
+	for _, e := range edges {
 		edgesData = append(edgesData, e.ToDict())
 	}
 	graphContent["edges"] = edgesData
@@ -580,12 +1876,7 @@ func (g *OpenGraph) ExportJSON(includeMetadata bool) (string, error) {
 		}
 	}
 
-	jsonData, err := json.MarshalIndent(graphData, "", "  ")
-	if err != nil {
-		return "", err
-	}
-
-	return string(jsonData), nil
+	return graphData
 }
 
 // ExportToFile exports the graph to a JSON file after performing validation checks.
@@ -610,6 +1901,26 @@ func (g *OpenGraph) ExportToFile(filename string) error {
 	return os.WriteFile(filename, []byte(jsonData), 0644)
 }
 
+// MarshalJSON encodes the graph in the BloodHound OpenGraph format, as
+// ExportJSON without metadata, so that a graph can be a field of a struct
+// passed to json.Marshal.
+//
+// As the metadata is left out, the source kind of the graph is lost: a graph
+// with a source kind does not decode back to an equal graph. Use ExportJSON
+// with metadata to keep it.
+//
+// Returns:
+//
+//	[]byte: The JSON representation of the graph.
+//	error: An error if the graph could not be marshaled.
+func (g *OpenGraph) MarshalJSON() ([]byte, error) {
+	jsonData, err := g.ExportJSON(false)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(jsonData), nil
+}
+
 // Graph imports
 
 // FromJSON imports graph data from a JSON string and appends it to the current graph.
@@ -732,6 +2043,32 @@ func (g *OpenGraph) FromJSON(jsonData string) error {
 	return nil
 }
 
+// UnmarshalJSON decodes a graph in the BloodHound OpenGraph format with
+// FromJSON, so that a graph can be a field of a struct passed to
+// json.Unmarshal.
+//
+// A zero OpenGraph, as allocated by json.Unmarshal, is first set up like one
+// returned by NewOpenGraph(""). The data is appended to a graph that already
+// has nodes or edges.
+//
+// Arguments:
+//
+//	data []byte: The JSON representation of the graph.
+//
+// Returns:
+//
+//	error: An error if the JSON could not be parsed or holds an invalid node or edge.
+func (g *OpenGraph) UnmarshalJSON(data []byte) error {
+	if g.nodes == nil {
+		g.nodes = make(map[string]*node.Node)
+		g.edges = make([]*edge.Edge, 0)
+		g.outEdges = make(map[string][]*edge.Edge)
+		g.inEdges = make(map[string][]*edge.Edge)
+		g.autoSourceKind = true
+	}
+	return g.FromJSON(string(data))
+}
+
 // FromJSONFile imports graph data from a JSON file and appends it to the current graph.
 //
 // It reads the file content and delegates to FromJSON.
@@ -758,6 +2095,7 @@ func (g *OpenGraph) FromJSONFile(filename string) error {
 //	int: The number of nodes if they exist, nil if validation failed
 //	     (e.g., nodes do not exist or have an invalid ID).
 func (g *OpenGraph) GetNodeCount() int {
+	defer g.rlock()()
 	return len(g.nodes)
 }
 
@@ -774,9 +2112,58 @@ func (g *OpenGraph) GetNodeCount() int {
 //	int: The number of edges if they exist, nil if validation failed
 //	     (e.g., edges do not exist or have an invalid ID).
 func (g *OpenGraph) GetEdgeCount() int {
+	defer g.rlock()()
 	return len(g.edges)
 }
 
+// NodeCountResult holds the node counts returned by GetNodeCountDetailed.
+type NodeCountResult struct {
+	// Total is the number of nodes.
+	Total int
+	// ByKind is the number of nodes having each kind. A node with several
+	// kinds is counted under each of them, so the counts may add up to more
+	// than Total.
+	ByKind map[string]int
+}
+
+// EdgeCountResult holds the edge counts returned by GetEdgeCountDetailed.
+type EdgeCountResult struct {
+	// Total is the number of edges.
+	Total int
+	// ByKind is the number of edges of each kind.
+	ByKind map[string]int
+}
+
+// GetNodeCountDetailed returns the number of nodes, overall and per kind.
+//
+// Returns:
+//
+//	NodeCountResult: The total number of nodes and the number of nodes having
+//	                 each kind.
+func (g *OpenGraph) GetNodeCountDetailed() NodeCountResult {
+	result := NodeCountResult{Total: len(g.nodes), ByKind: make(map[string]int)}
+	for _, n := range g.nodes {
+		for _, kind := range n.GetKinds() {
+			result.ByKind[kind]++
+		}
+	}
+	return result
+}
+
+// GetEdgeCountDetailed returns the number of edges, overall and per kind.
+//
+// Returns:
+//
+//	EdgeCountResult: The total number of edges and the number of edges of each
+//	                 kind.
+func (g *OpenGraph) GetEdgeCountDetailed() EdgeCountResult {
+	result := EdgeCountResult{Total: len(g.edges), ByKind: make(map[string]int)}
+	for _, e := range g.edges {
+		result.ByKind[e.GetKind()]++
+	}
+	return result
+}
+
 // Clear removes all nodes and edges after performing validation checks.
 //
 // It verifies that the nodes and edges exist in the graph,
@@ -790,8 +2177,22 @@ func (g *OpenGraph) GetEdgeCount() int {
 //	nil: If the nodes and edges were successfully removed, nil if validation failed
 //	     (e.g., nodes or edges do not exist or have an invalid ID).
 func (g *OpenGraph) Clear() {
+	removedNodes, removedEdges := g.nodes, g.edges
+
 	g.nodes = make(map[string]*node.Node)
 	g.edges = make([]*edge.Edge, 0)
+	g.rebuildEdgeIndex()
+	g.resetKindIndex()
+	for key := range g.propertyIndexes {
+		g.propertyIndexes[key] = make(propertyIndex)
+	}
+
+	for _, n := range removedNodes {
+		g.notifyNode(hookNodeRemoved, n)
+	}
+	for _, e := range removedEdges {
+		g.notifyEdge(hookEdgeRemoved, e)
+	}
 }
 
 // Len returns the total number of nodes and edges after performing validation checks.
@@ -816,6 +2217,10 @@ func (g *OpenGraph) Len() int {
 // and that the nodes and edges have valid IDs. If any validation fails,
 // the string representation is not returned.
 //
+// The node and edge counts are followed by their per-kind counts, sorted by
+// kind, e.g. "OpenGraph(nodes=5 [Base:5, Group:2, User:3], edges=4
+// [MemberOf:4], source_kind='Base')".
+//
 // Arguments:
 //
 // Returns:
@@ -823,8 +2228,44 @@ func (g *OpenGraph) Len() int {
 //	string: The string representation if it exists, nil if validation failed
 //	        (e.g., nodes or edges do not exist or have an invalid ID).
 func (g *OpenGraph) String() string {
-	return fmt.Sprintf("OpenGraph(nodes=%d, edges=%d, source_kind='%s')",
-		len(g.nodes), len(g.edges), g.sourceKind)
+	return fmt.Sprintf("OpenGraph(%s)", g.describe())
+}
+
+// Summary returns a one-line description of the graph: the String
+// representation extended with the number of connected components and whether
+// the graph has a directed cycle, e.g. "OpenGraph(nodes=2 [Base:2, User:2], edges=1
+// [Knows:1], source_kind='Base', components=1, has_cycles=false)".
+//
+// Returns:
+//
+//	string: The summary of the graph.
+func (g *OpenGraph) Summary() string {
+	_, err := g.GetTopologicalGenerations()
+	return fmt.Sprintf("OpenGraph(%s, components=%d, has_cycles=%t)",
+		g.describe(), len(g.GetConnectedComponents()), err != nil)
+}
+
+// describe returns the node and edge counts, per kind, and the source kind of
+// the graph, as shown by String and Summary.
+func (g *OpenGraph) describe() string {
+	nodes, edges := g.GetNodeCountDetailed(), g.GetEdgeCountDetailed()
+	return fmt.Sprintf("nodes=%d %s, edges=%d %s, source_kind='%s'",
+		nodes.Total, formatKindCounts(nodes.ByKind), edges.Total, formatKindCounts(edges.ByKind), g.sourceKind)
+}
+
+// formatKindCounts formats per-kind counts as "[A:1, B:2]", sorted by kind.
+func formatKindCounts(counts map[string]int) string {
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		parts = append(parts, fmt.Sprintf("%s:%d", kind, counts[kind]))
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
 }
 
 // Equal checks if two graphs are equal after performing validation checks.
@@ -861,3 +2302,141 @@ func (g *OpenGraph) Equal(other *OpenGraph) bool {
 	}
 	return true
 }
+
+// Internal helpers
+
+// sortedNodeIDs returns the IDs of all nodes in the graph in lexicographic order,
+// giving algorithms a deterministic iteration order over the nodes map.
+func (g *OpenGraph) sortedNodeIDs() []string {
+	ids := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// sortedNodes returns the nodes of the graph in ID order.
+func (g *OpenGraph) sortedNodes() []*node.Node {
+	nodes := make([]*node.Node, 0, len(g.nodes))
+	for _, id := range g.sortedNodeIDs() {
+		nodes = append(nodes, g.nodes[id])
+	}
+	return nodes
+}
+
+// sortedEdges returns a copy of the edges of the graph ordered by start node ID,
+// end node ID and kind. The edges of the graph are not reordered.
+func (g *OpenGraph) sortedEdges() []*edge.Edge {
+	edges := append([]*edge.Edge{}, g.edges...)
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edgeLess(edges[i], edges[j])
+	})
+	return edges
+}
+
+// indexEdge adds e to the indexes of the edges by start and end node ID.
+func (g *OpenGraph) indexEdge(e *edge.Edge) {
+	if g.outEdges == nil {
+		g.rebuildEdgeIndex()
+		return
+	}
+	g.outEdges[e.GetStartNodeID()] = append(g.outEdges[e.GetStartNodeID()], e)
+	g.inEdges[e.GetEndNodeID()] = append(g.inEdges[e.GetEndNodeID()], e)
+}
+
+// rebuildEdgeIndex rebuilds the indexes of the edges by start and end node ID
+// from the edges of the graph, e.g. after they were removed or reordered.
+func (g *OpenGraph) rebuildEdgeIndex() {
+	g.outEdges = make(map[string][]*edge.Edge)
+	g.inEdges = make(map[string][]*edge.Edge)
+	for _, e := range g.edges {
+		g.outEdges[e.GetStartNodeID()] = append(g.outEdges[e.GetStartNodeID()], e)
+		g.inEdges[e.GetEndNodeID()] = append(g.inEdges[e.GetEndNodeID()], e)
+	}
+}
+
+// containsEdgePointer reports whether edges contains e itself, not merely an
+// equal edge.
+func containsEdgePointer(edges []*edge.Edge, e *edge.Edge) bool {
+	for _, candidate := range edges {
+		if candidate == e {
+			return true
+		}
+	}
+	return false
+}
+
+// edgesOfKind returns the edges of kind among edges, as a new non-nil slice.
+func edgesOfKind(edges []*edge.Edge, kind string) []*edge.Edge {
+	matches := make([]*edge.Edge, 0)
+	for _, e := range edges {
+		if e.GetKind() == kind {
+			matches = append(matches, e)
+		}
+	}
+	return matches
+}
+
+// cloneNode returns a copy of n that shares no kinds or properties with it.
+func cloneNode(n *node.Node) *node.Node {
+	clone, _ := node.NewNode(n.GetID(), append([]string{}, n.GetKinds()...), n.GetProperties().Clone())
+	return clone
+}
+
+// cloneEdge returns a copy of e that shares no properties with it.
+func cloneEdge(e *edge.Edge) *edge.Edge {
+	clone, _ := edge.NewEdgeWithEndpoints(e.GetStart(), e.GetEnd(), e.GetKind(), e.GetProperties().Clone())
+	return clone
+}
+
+// successors returns, for every node, the IDs of the nodes it has an outgoing
+// edge to, in edge insertion order and without duplicates. Edges whose
+// endpoints are not nodes of the graph (e.g. name- or property-matched
+// endpoints) are ignored.
+func (g *OpenGraph) successors() map[string][]string {
+	return g.successorsWhere(nil)
+}
+
+// reachableFrom returns the set of nodes reachable from startID, itself
+// included, by following adjacency.
+func reachableFrom(startID string, adjacency map[string][]string) map[string]bool {
+	visited := map[string]bool{startID: true}
+	queue := []string{startID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[current] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return visited
+}
+
+// successorsWhere is like successors but only considers the edges accepted by
+// edgeFilter. A nil filter accepts every edge.
+func (g *OpenGraph) successorsWhere(edgeFilter func(*edge.Edge) bool) map[string][]string {
+	adjacency := make(map[string][]string, len(g.nodes))
+	seen := make(map[[2]string]bool)
+	for _, e := range g.edges {
+		if edgeFilter != nil && !edgeFilter(e) {
+			continue
+		}
+		start, end := e.GetStartNodeID(), e.GetEndNodeID()
+		if _, exists := g.nodes[start]; !exists {
+			continue
+		}
+		if _, exists := g.nodes[end]; !exists {
+			continue
+		}
+		if seen[[2]string{start, end}] {
+			continue
+		}
+		seen[[2]string{start, end}] = true
+		adjacency[start] = append(adjacency[start], end)
+	}
+	return adjacency
+}
@@ -1,6 +1,9 @@
 package gopengraph_test
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
 	"encoding/json"
@@ -167,6 +170,72 @@ func TestAddNode(t *testing.T) {
 	}
 }
 
+func TestGetNodesByAllAndAnyKinds(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User", "Admin"}, nil))
+	g.AddNode(newTestNode(t, "bob", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "srv", []string{"Computer"}, nil))
+
+	tests := []struct {
+		kinds   []string
+		wantAll []string
+		wantAny []string
+	}{
+		{[]string{}, []string{"alice", "bob", "srv"}, []string{}},
+		{[]string{"User"}, []string{"alice", "bob"}, []string{"alice", "bob"}},
+		{[]string{"User", "Admin"}, []string{"alice"}, []string{"alice", "bob"}},
+		{[]string{"Admin", "Computer"}, []string{}, []string{"alice", "srv"}},
+		{[]string{"Group"}, []string{}, []string{}},
+	}
+	for _, tt := range tests {
+		if got := nodeIDs(g.GetNodesByAllKinds(tt.kinds)); !reflect.DeepEqual(got, tt.wantAll) {
+			t.Errorf("Expected GetNodesByAllKinds(%v) to return %v, got %v", tt.kinds, tt.wantAll, got)
+		}
+		if got := nodeIDs(g.GetNodesByAnyKind(tt.kinds)); !reflect.DeepEqual(got, tt.wantAny) {
+			t.Errorf("Expected GetNodesByAnyKind(%v) to return %v, got %v", tt.kinds, tt.wantAny, got)
+		}
+	}
+}
+
+func TestGetNodesExcludingKinds(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User", "Base"}, nil))
+	g.AddNode(newTestNode(t, "bob", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "srv", []string{"Computer", "Base"}, nil))
+
+	tests := []struct {
+		kinds    []string
+		expected []string
+	}{
+		{nil, []string{"alice", "bob", "srv"}},
+		{[]string{"Base"}, []string{"bob"}},
+		{[]string{"Computer", "Group"}, []string{"alice", "bob"}},
+		{[]string{"User", "Computer"}, []string{}},
+	}
+	for _, tt := range tests {
+		if got := nodeIDs(g.GetNodesExcludingKinds(tt.kinds)); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Expected GetNodesExcludingKinds(%v) to return %v, got %v", tt.kinds, tt.expected, got)
+		}
+	}
+}
+
+func TestGetNodesBySourceKind(t *testing.T) {
+	g := gopengraph.NewOpenGraph("Base")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "bob", []string{"User"}, nil))
+	g.AddNodeWithoutValidation(newTestNode(t, "carol", []string{"User"}, nil))
+
+	if got := nodeIDs(g.GetNodesBySourceKind()); !reflect.DeepEqual(got, []string{"alice", "bob"}) {
+		t.Errorf("Expected [alice bob], got %v", got)
+	}
+
+	empty := gopengraph.NewOpenGraph("")
+	empty.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	if got := empty.GetNodesBySourceKind(); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty non-nil slice without a source kind, got %#v", got)
+	}
+}
+
 func TestFindPaths(t *testing.T) {
 	g := gopengraph.NewOpenGraph("test")
 
@@ -217,6 +286,43 @@ func TestFindPaths(t *testing.T) {
 	}
 }
 
+func TestFindPathsWithFilter(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "helpdesk", []string{"Group"}, nil))
+	g.AddNode(newTestNode(t, "ws01", []string{"Computer"}, nil))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+	g.AddEdge(newTestEdge(t, "alice", "helpdesk", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "helpdesk", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "alice", "ws01", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "ws01", "admins", "HasSession"))
+
+	if paths := g.FindPathsWithFilter("alice", "admins", 5, nil, nil); len(paths) != 2 {
+		t.Errorf("Expected 2 unfiltered paths, got %v", paths)
+	}
+
+	rejectAll := func(e *edge.Edge) bool { return false }
+	if paths := g.FindPathsWithFilter("alice", "admins", 5, rejectAll, nil); len(paths) != 0 {
+		t.Errorf("Expected no path when every edge is rejected, got %v", paths)
+	}
+
+	memberOf := func(e *edge.Edge) bool { return e.GetKind() == "MemberOf" }
+	expected := [][]string{{"alice", "helpdesk", "admins"}}
+	if paths := g.FindPathsWithFilter("alice", "admins", 5, memberOf, nil); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+
+	throughComputers := func(n *node.Node) bool { return n.HasKind("Computer") }
+	expected = [][]string{{"alice", "ws01", "admins"}}
+	if paths := g.FindPathsWithFilter("alice", "admins", 5, nil, throughComputers); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected %v, got %v", expected, paths)
+	}
+
+	if paths := g.FindPathsWithFilter("alice", "missing", 5, nil, nil); paths != nil {
+		t.Errorf("Expected nil paths for a non-existent node, got %v", paths)
+	}
+}
+
 func TestGetConnectedComponents(t *testing.T) {
 	g := gopengraph.NewOpenGraph("test")
 
@@ -333,3 +439,1280 @@ func TestJSONioInvolution(t *testing.T) {
 		t.Errorf("Expected graphs to be equal")
 	}
 }
+
+func TestIntSliceJSONRoundTrip(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "srv", []string{"Computer"}, map[string]interface{}{"ports": []int{80, 443}}))
+
+	data, err := g.ExportJSON(false)
+	if err != nil {
+		t.Fatalf("Failed to export graph: %v", err)
+	}
+	imported := gopengraph.NewOpenGraph("")
+	if err := imported.FromJSON(data); err != nil {
+		t.Fatalf("Failed to import graph: %v", err)
+	}
+
+	if got, ok := imported.GetNode("srv").GetProperties().GetIntSlice("ports"); !ok || !reflect.DeepEqual(got, []int{80, 443}) {
+		t.Errorf("Expected [80 443], got %v (ok=%v)", got, ok)
+	}
+}
+
+func TestOpenGraphJSONCodec(t *testing.T) {
+	type report struct {
+		Name  string                `json:"name"`
+		Graph *gopengraph.OpenGraph `json:"graph"`
+		Count int                   `json:"count"`
+	}
+
+	g := newTestGraph(t)
+	data, err := json.Marshal(report{Name: "weekly", Graph: g, Count: 2})
+	if err != nil {
+		t.Fatalf("Failed to marshal report: %v", err)
+	}
+
+	var got report
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Failed to unmarshal report: %v", err)
+	}
+	if got.Name != "weekly" || got.Count != 2 || got.Graph == nil {
+		t.Fatalf("Expected the report fields to round-trip, got %+v", got)
+	}
+
+	if !got.Graph.Equal(g) {
+		t.Errorf("Expected the embedded graph to round-trip, got %v", got.Graph)
+	}
+
+	// The decoded graph is fully usable.
+	if !got.Graph.AddNode(newTestNode(t, "carol", []string{"User"}, nil)) || got.Graph.GetNodeCount() != 4 {
+		t.Error("Expected to add a node to the decoded graph")
+	}
+
+	if err := json.Unmarshal([]byte(`{"graph": {"graph": {"nodes": [{"kinds": ["User"]}]}}}`), &got); err == nil {
+		t.Error("Expected an error for a node without an ID")
+	}
+}
+
+func TestOpenGraphJSONCodecDropsSourceKind(t *testing.T) {
+	g := gopengraph.NewOpenGraph("Base")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("Failed to marshal graph: %v", err)
+	}
+	got := gopengraph.NewOpenGraph("")
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("Failed to unmarshal graph: %v", err)
+	}
+	if got.GetSourceKind() != "" {
+		t.Errorf("Expected no source kind, got %q", got.GetSourceKind())
+	}
+	if got.Equal(g) {
+		t.Error("Expected a graph with a source kind not to round-trip to an equal graph")
+	}
+}
+
+func TestUpdateNodeProperties(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, map[string]interface{}{"name": "ALICE", "enabled": false}))
+
+	t.Run("applies updates and keeps other properties", func(t *testing.T) {
+		err := g.UpdateNodeProperties("alice", map[string]interface{}{"enabled": true, "logons": 4})
+		if err != nil {
+			t.Fatalf("UpdateNodeProperties failed: %v", err)
+		}
+		n := g.GetNode("alice")
+		if n.GetProperty("enabled") != true || n.GetProperty("logons") != 4 {
+			t.Errorf("Expected updates to be applied, got %v", n.GetProperties().ToDict())
+		}
+		if n.GetProperty("name") != "ALICE" {
+			t.Errorf("Expected name to be unchanged, got %v", n.GetProperty("name"))
+		}
+	})
+
+	t.Run("invalid value returns an error and applies nothing", func(t *testing.T) {
+		err := g.UpdateNodeProperties("alice", map[string]interface{}{
+			"name":   "CHANGED",
+			"nested": map[string]interface{}{"a": 1},
+		})
+		if err == nil {
+			t.Fatal("Expected an error for a nested object value")
+		}
+		if g.GetNode("alice").GetProperty("name") != "ALICE" {
+			t.Error("Expected no update to be applied when a value is invalid")
+		}
+	})
+
+	t.Run("missing node returns an error", func(t *testing.T) {
+		if err := g.UpdateNodeProperties("nobody", map[string]interface{}{"a": 1}); err == nil {
+			t.Error("Expected an error for a missing node")
+		}
+	})
+}
+
+func TestUpdateEdgeProperties(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+	e := newTestEdge(t, "alice", "admins", "MemberOf")
+	e.SetProperty("source", "ldap")
+	g.AddEdge(e)
+
+	if err := g.UpdateEdgeProperties("alice", "admins", "MemberOf", map[string]interface{}{"isacl": false}); err != nil {
+		t.Fatalf("UpdateEdgeProperties failed: %v", err)
+	}
+	if e.GetProperty("isacl") != false || e.GetProperty("source") != "ldap" {
+		t.Errorf("Unexpected edge properties: %v", e.GetProperties().ToDict())
+	}
+
+	if err := g.UpdateEdgeProperties("alice", "admins", "MemberOf", map[string]interface{}{"bad": []interface{}{1, "a"}}); err == nil {
+		t.Error("Expected an error for a mixed array value")
+	}
+	if err := g.UpdateEdgeProperties("alice", "admins", "AdminTo", map[string]interface{}{"a": 1}); err == nil {
+		t.Error("Expected an error for a missing edge")
+	}
+}
+
+func TestBulkSetPropertyOnNodesByKind(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "bob", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "carol", []string{"User"}, map[string]interface{}{"enabled": false}))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+	g.AddNode(newTestNode(t, "dc", []string{"Computer"}, map[string]interface{}{"enabled": false}))
+	g.BuildPropertyIndex("enabled")
+
+	if got := g.BulkSetPropertyOnNodesByKind("User", "enabled", true); got != 3 {
+		t.Errorf("Expected 3 nodes to be updated, got %d", got)
+	}
+	if got := nodeIDs(g.GetNodesByPropertyValue("enabled", true)); !reflect.DeepEqual(got, []string{"alice", "bob", "carol"}) {
+		t.Errorf("Expected [alice bob carol] to be enabled, got %v", got)
+	}
+	if g.GetNode("admins").GetProperties().HasProperty("enabled") {
+		t.Error("Expected admins to be unaffected")
+	}
+	if g.GetNode("dc").GetProperty("enabled") != false {
+		t.Error("Expected dc to be unaffected")
+	}
+
+	if got := g.BulkSetPropertyOnNodesByKind("User", "enabled", map[string]interface{}{"a": 1}); got != -1 {
+		t.Errorf("Expected -1 for an invalid value, got %d", got)
+	}
+	if got := g.BulkSetPropertyOnNodesByKind("Domain", "enabled", true); got != 0 {
+		t.Errorf("Expected 0 nodes to be updated, got %d", got)
+	}
+}
+
+func TestBulkSetPropertyOnEdgesByKind(t *testing.T) {
+	g := newTestGraph(t)
+	g.AddEdge(newTestEdge(t, "alice", "bob", "Knows"))
+
+	if got := g.BulkSetPropertyOnEdgesByKind("MemberOf", "isacl", false); got != 2 {
+		t.Errorf("Expected 2 edges to be updated, got %d", got)
+	}
+	if got := len(g.FilterEdgesByProperty("isacl", false)); got != 2 {
+		t.Errorf("Expected 2 edges with isacl, got %d", got)
+	}
+	if got := g.BulkSetPropertyOnEdgesByKind("Knows", "isacl", nil); got != -1 {
+		t.Errorf("Expected -1 for an invalid value, got %d", got)
+	}
+}
+
+func TestSetEdgeKind(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+	g.AddEdge(newTestEdge(t, "alice", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "alice", "admins", "GenericAll"))
+
+	if err := g.SetEdgeKind("alice", "admins", "MemberOf", "AdminTo"); err != nil {
+		t.Fatalf("SetEdgeKind failed: %v", err)
+	}
+	if len(g.GetEdgesByKind("MemberOf")) != 0 {
+		t.Error("Expected no MemberOf edge after SetEdgeKind")
+	}
+	if len(g.GetEdgesByKind("AdminTo")) != 1 {
+		t.Error("Expected the edge to be found by its new kind")
+	}
+
+	// Duplicate checks use the new kind.
+	if g.AddEdge(newTestEdge(t, "alice", "admins", "AdminTo")) {
+		t.Error("Expected AddEdge to reject a duplicate of the renamed edge")
+	}
+	if !g.AddEdge(newTestEdge(t, "alice", "admins", "MemberOf")) {
+		t.Error("Expected AddEdge to accept an edge of the old kind")
+	}
+	if err := g.SetEdgeKind("alice", "admins", "GenericAll", "AdminTo"); err == nil {
+		t.Error("Expected an error when the new kind duplicates an existing edge")
+	}
+
+	if err := g.SetEdgeKind("alice", "admins", "GenericAll", "bad kind"); err == nil {
+		t.Error("Expected an error for an invalid kind")
+	}
+	if err := g.SetEdgeKind("alice", "admins", "Owns", "AdminTo"); err == nil {
+		t.Error("Expected an error for a missing edge")
+	}
+}
+
+func TestGetOrphanedEdges(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "bob", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+	g.AddEdge(newTestEdge(t, "alice", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "bob", "admins", "MemberOf"))
+	g.AddEdgeWithoutValidation(newTestEdge(t, "alice", "missing", "AdminTo"))
+
+	byName, err := edge.NewEdgeWithEndpoints(edge.NewEndpointByID("alice"), edge.NewEndpointByName("DC01", "Computer"), "AdminTo", nil)
+	if err != nil {
+		t.Fatalf("Failed to create edge: %v", err)
+	}
+	g.AddEdgeWithoutValidation(byName)
+
+	if got := edgeKeys(g.GetOrphanedEdges()); !reflect.DeepEqual(got, []string{"alice-AdminTo-missing"}) {
+		t.Errorf("Expected [alice-AdminTo-missing], got %v", got)
+	}
+
+	// Edges added with AddEdge dangle once their endpoint is gone.
+	g.RemoveNodeByID("bob")
+	g.RemoveNodeByID("admins")
+	g.AddEdgeWithoutValidation(newTestEdge(t, "alice", "bob", "Knows"))
+	g.AddNode(newTestNode(t, "bob", []string{"User"}, nil))
+	g.AddEdgeWithoutValidation(newTestEdge(t, "ghost", "bob", "Knows"))
+	if got := edgeKeys(g.FindDanglingReferences()); !reflect.DeepEqual(got, []string{"alice-AdminTo-missing", "ghost-Knows-bob"}) {
+		t.Errorf("Expected [alice-AdminTo-missing ghost-Knows-bob], got %v", got)
+	}
+
+	if got := gopengraph.NewOpenGraph("").GetOrphanedEdges(); got == nil || len(got) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %#v", got)
+	}
+}
+
+func TestIsReachable(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "a", "b", "Knows"))
+	g.AddEdge(newTestEdge(t, "b", "c", "Knows"))
+
+	tests := []struct {
+		start, end string
+		expected   bool
+	}{
+		{"a", "c", true},
+		{"c", "a", false},
+		{"a", "d", false},
+		{"d", "d", true},
+		{"a", "missing", false},
+	}
+	for _, tt := range tests {
+		if got := g.IsReachable(tt.start, tt.end); got != tt.expected {
+			t.Errorf("Expected IsReachable(%s, %s) to be %v, got %v", tt.start, tt.end, tt.expected, got)
+		}
+	}
+}
+
+func TestGetDisconnectedNodePairs(t *testing.T) {
+	// Two components: a <-> b and c -> d.
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "a", "b", "Link"))
+	g.AddEdge(newTestEdge(t, "b", "a", "Link"))
+	g.AddEdge(newTestEdge(t, "c", "d", "Link"))
+
+	expected := [][2]string{
+		{"a", "c"}, {"a", "d"},
+		{"b", "c"}, {"b", "d"},
+		{"c", "a"}, {"c", "b"},
+		{"d", "a"}, {"d", "b"}, {"d", "c"},
+	}
+	got := g.GetDisconnectedNodePairs()
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+	for _, pair := range got {
+		if g.IsReachable(pair[0], pair[1]) {
+			t.Errorf("Expected %s not to reach %s", pair[0], pair[1])
+		}
+	}
+
+	if got := g.GetSampledDisconnectedPairs(100); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected every disconnected pair when sampling every pair, got %v", got)
+	}
+	sampled := g.GetSampledDisconnectedPairs(5)
+	if len(sampled) > 5 {
+		t.Errorf("Expected at most 5 pairs, got %v", sampled)
+	}
+	for _, pair := range sampled {
+		if g.IsReachable(pair[0], pair[1]) {
+			t.Errorf("Expected sampled pair %v to be disconnected", pair)
+		}
+	}
+	if got := g.GetSampledDisconnectedPairs(0); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty slice for zero samples, got %#v", got)
+	}
+	// Drawing 11 of the 12 pairs leaves out at most one disconnected pair.
+	dense := g.GetSampledDisconnectedPairs(11)
+	if len(dense) < len(expected)-1 {
+		t.Errorf("Expected at least %d pairs, got %v", len(expected)-1, dense)
+	}
+	for i, pair := range dense {
+		if g.IsReachable(pair[0], pair[1]) || pair[0] == pair[1] {
+			t.Errorf("Expected sampled pair %v to be disconnected", pair)
+		}
+		if i > 0 && dense[i-1] == pair {
+			t.Errorf("Expected pair %v to be sampled once", pair)
+		}
+	}
+
+	if got := gopengraph.NewOpenGraph("").GetDisconnectedNodePairs(); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty slice for an empty graph, got %#v", got)
+	}
+}
+
+func TestGetTopologicalGenerations(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"A", "B", "C", "D", "X", "Y"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	// A diamond, and a disconnected X -> Y.
+	g.AddEdge(newTestEdge(t, "A", "B", "Knows"))
+	g.AddEdge(newTestEdge(t, "A", "C", "Knows"))
+	g.AddEdge(newTestEdge(t, "B", "D", "Knows"))
+	g.AddEdge(newTestEdge(t, "C", "D", "Knows"))
+	g.AddEdge(newTestEdge(t, "X", "Y", "Knows"))
+
+	generations, err := g.GetTopologicalGenerations()
+	if err != nil {
+		t.Fatalf("GetTopologicalGenerations failed: %v", err)
+	}
+	expected := [][]string{{"A", "X"}, {"B", "C", "Y"}, {"D"}}
+	if !reflect.DeepEqual(generations, expected) {
+		t.Errorf("Expected %v, got %v", expected, generations)
+	}
+
+	g.AddEdge(newTestEdge(t, "D", "A", "Knows"))
+	if _, err := g.GetTopologicalGenerations(); err == nil {
+		t.Error("Expected an error for a cyclic graph")
+	}
+
+	empty, err := gopengraph.NewOpenGraph("").GetTopologicalGenerations()
+	if err != nil || len(empty) != 0 {
+		t.Errorf("Expected no layer for an empty graph, got %v (err=%v)", empty, err)
+	}
+}
+
+func TestContractNode(t *testing.T) {
+	tests := []struct {
+		name      string
+		keepEdges bool
+		expected  []string
+	}{
+		// The existing d -> c edge is not duplicated, and b -> a gives a -> a.
+		{"without keepEdges", false, []string{"a-Reaches-a", "a-Reaches-c", "d-Reaches-a", "d-Reaches-c"}},
+		{"with keepEdges", true, []string{"a-Reaches-a", "a-Reaches-c", "d-Reaches-a", "d-Reaches-c", "d-Reaches-c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := gopengraph.NewOpenGraph("")
+			for _, id := range []string{"a", "b", "c", "d"} {
+				g.AddNode(newTestNode(t, id, nil, nil))
+			}
+			g.AddEdge(newTestEdge(t, "a", "b", "MemberOf"))
+			g.AddEdge(newTestEdge(t, "d", "b", "MemberOf"))
+			g.AddEdge(newTestEdge(t, "b", "c", "AdminTo"))
+			g.AddEdge(newTestEdge(t, "b", "a", "AdminTo"))
+			g.AddEdge(newTestEdge(t, "d", "c", "Reaches"))
+
+			removed := 0
+			g.OnEdgeRemoved(func(*edge.Edge) { removed++ })
+
+			if err := g.ContractNode("b", "Reaches", tt.keepEdges); err != nil {
+				t.Fatalf("ContractNode failed: %v", err)
+			}
+			if g.GetNode("b") != nil {
+				t.Error("Expected b to be removed")
+			}
+			if !g.IsReachable("a", "c") {
+				t.Error("Expected c to remain reachable from a")
+			}
+			if removed != 4 {
+				t.Errorf("Expected 4 edge removals, got %d", removed)
+			}
+
+			g.SortEdgesBy("start")
+			if got := edgeKeys(g.FilterEdgesByPredicate(func(*edge.Edge) bool { return true })); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected edges %v, got %v", tt.expected, got)
+			}
+
+			if err := g.ContractNode("a", "bad kind", tt.keepEdges); err == nil {
+				t.Error("Expected an error for an invalid edge kind")
+			}
+			if g.GetNode("a") == nil {
+				t.Error("Expected the graph to be unchanged after an error")
+			}
+			if err := g.ContractNode("missing", "Reaches", tt.keepEdges); err == nil {
+				t.Error("Expected an error for a missing node")
+			}
+		})
+	}
+}
+
+func TestSetSourceKind(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "bob", nil, nil))
+	g.AddNode(newTestNode(t, "full", []string{"A", "B", "C"}, nil))
+	if g.HasSourceKind() {
+		t.Error("Expected no source kind")
+	}
+
+	g.SetSourceKind("NewSource")
+	if !g.HasSourceKind() || g.GetSourceKind() != "NewSource" {
+		t.Errorf("Expected source kind NewSource, got %q", g.GetSourceKind())
+	}
+	for _, id := range []string{"alice", "bob"} {
+		if !g.GetNode(id).HasKind("NewSource") {
+			t.Errorf("Expected %s to have the source kind", id)
+		}
+	}
+	if g.GetNode("full").HasKind("NewSource") {
+		t.Error("Expected a node with the maximum number of kinds to be unchanged")
+	}
+
+	g.SetSourceKind("")
+	if g.HasSourceKind() {
+		t.Error("Expected the source kind to be cleared")
+	}
+	if !reflect.DeepEqual(g.GetNode("alice").GetKinds(), []string{"User", "NewSource"}) {
+		t.Errorf("Expected the kinds of alice to be unchanged, got %v", g.GetNode("alice").GetKinds())
+	}
+}
+
+func TestGetNodeAndEdgeCountDetailed(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User", "Base"}, nil))
+	g.AddNode(newTestNode(t, "bob", []string{"User", "Base"}, nil))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+	g.AddEdge(newTestEdge(t, "alice", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "bob", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "admins", "alice", "Contains"))
+
+	nodes := g.GetNodeCountDetailed()
+	if nodes.Total != 3 || nodes.Total != g.GetNodeCount() {
+		t.Errorf("Expected 3 nodes in total, got %d", nodes.Total)
+	}
+	if !reflect.DeepEqual(nodes.ByKind, map[string]int{"User": 2, "Base": 2, "Group": 1}) {
+		t.Errorf("Expected map[Base:2 Group:1 User:2], got %v", nodes.ByKind)
+	}
+	sum := 0
+	for _, count := range nodes.ByKind {
+		sum += count
+	}
+	if sum <= nodes.Total {
+		t.Errorf("Expected the per-kind counts to add up to more than %d, got %d", nodes.Total, sum)
+	}
+
+	edges := g.GetEdgeCountDetailed()
+	if edges.Total != 3 {
+		t.Errorf("Expected 3 edges in total, got %d", edges.Total)
+	}
+	if !reflect.DeepEqual(edges.ByKind, map[string]int{"MemberOf": 2, "Contains": 1}) {
+		t.Errorf("Expected map[Contains:1 MemberOf:2], got %v", edges.ByKind)
+	}
+
+	g.Clear()
+	if got := g.GetNodeCountDetailed(); got.Total != 0 || got.ByKind == nil || len(got.ByKind) != 0 {
+		t.Errorf("Expected empty counts after Clear, got %+v", got)
+	}
+}
+
+func TestStringAndSummary(t *testing.T) {
+	g := gopengraph.NewOpenGraph("Base")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "bob", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+	g.AddNode(newTestNode(t, "dc", []string{"Computer"}, nil))
+	g.AddEdge(newTestEdge(t, "alice", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "bob", "admins", "MemberOf"))
+
+	str := g.String()
+	for _, want := range []string{
+		"nodes=4 [Base:4, Computer:1, Group:1, User:2]",
+		"edges=2 [MemberOf:2]",
+		"source_kind='Base'",
+	} {
+		if !strings.Contains(str, want) {
+			t.Errorf("Expected %q to contain %q", str, want)
+		}
+	}
+
+	summary := g.Summary()
+	for _, want := range []string{"nodes=4 [Base:4, Computer:1, Group:1, User:2]", "components=2", "has_cycles=false"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected %q to contain %q", summary, want)
+		}
+	}
+
+	g.AddEdge(newTestEdge(t, "admins", "alice", "Contains"))
+	if summary := g.Summary(); !strings.Contains(summary, "has_cycles=true") {
+		t.Errorf("Expected %q to contain %q", summary, "has_cycles=true")
+	}
+
+	if str := gopengraph.NewOpenGraph("").String(); str != "OpenGraph(nodes=0 [], edges=0 [], source_kind='')" {
+		t.Errorf("Unexpected string for an empty graph: %q", str)
+	}
+}
+
+func TestGetEdgesBetweenSets(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"a1", "a2", "b1", "b2", "c1"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "a1", "a2", "Link"))
+	g.AddEdge(newTestEdge(t, "a1", "b1", "Link"))
+	g.AddEdge(newTestEdge(t, "b2", "a2", "Link"))
+	g.AddEdge(newTestEdge(t, "b1", "b2", "Link"))
+	g.AddEdge(newTestEdge(t, "c1", "a1", "Link"))
+	g.AddEdge(newTestEdge(t, "c1", "b1", "Link"))
+
+	got := edgeKeys(g.GetEdgesBetweenSets([]string{"a1", "a2"}, []string{"b1", "b2"}))
+	if want := []string{"a1-Link-b1", "b2-Link-a2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if got := g.GetEdgesBetweenSets([]string{"a1"}, nil); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty slice for an empty set, got %#v", got)
+	}
+}
+
+func TestGetEdgesExcludingKinds(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"alice", "admins", "dc"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "alice", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "alice", "dc", "HasSession"))
+	g.AddEdge(newTestEdge(t, "admins", "dc", "AdminTo"))
+
+	got := edgeKeys(g.GetEdgesExcludingKinds([]string{"HasSession", "Unknown"}))
+	if want := []string{"alice-MemberOf-admins", "admins-AdminTo-dc"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := g.GetEdgesExcludingKinds(nil); len(got) != 3 {
+		t.Errorf("Expected every edge when excluding no kind, got %d", len(got))
+	}
+	if got := g.GetEdgesExcludingKinds([]string{"MemberOf", "HasSession", "AdminTo"}); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty slice when excluding every kind, got %#v", got)
+	}
+}
+
+func TestMultiGraph(t *testing.T) {
+	g := gopengraph.NewOpenGraphMulti("")
+	if !g.IsMultiGraph() || gopengraph.NewOpenGraph("").IsMultiGraph() {
+		t.Fatal("Expected only NewOpenGraphMulti to create a multigraph")
+	}
+	g.AddNode(newTestNode(t, "alice", nil, nil))
+	g.AddNode(newTestNode(t, "dc", nil, nil))
+
+	first := newTestEdge(t, "alice", "dc", "HasSession")
+	first.SetProperty("session", 1)
+	second := newTestEdge(t, "alice", "dc", "HasSession")
+	second.SetProperty("session", 2)
+	if !g.AddEdge(first) || !g.AddEdge(second) {
+		t.Fatal("Expected both parallel edges to be added")
+	}
+
+	for name, edges := range map[string][]*edge.Edge{
+		"GetEdgesFromNode":   g.GetEdgesFromNode("alice"),
+		"GetEdgesByNodePair": g.GetEdgesByNodePair("alice", "dc"),
+	} {
+		if len(edges) != 2 || !edges[0].IsSame(first) || !edges[1].IsSame(second) {
+			t.Errorf("Expected %s to return both parallel edges, got %v", name, edges)
+		}
+	}
+	if first.IsSame(second) || !first.Equal(second) {
+		t.Error("Expected the parallel edges to be equal but not the same")
+	}
+
+	// Derived graphs stay multigraphs.
+	if sub := g.MapEdges(func(e *edge.Edge) *edge.Edge { return e }); !sub.IsMultiGraph() || sub.GetEdgeCount() != 2 {
+		t.Errorf("Expected MapEdges to keep both parallel edges, got %d", sub.GetEdgeCount())
+	}
+
+	simple := gopengraph.NewOpenGraph("")
+	simple.AddNode(newTestNode(t, "alice", nil, nil))
+	simple.AddNode(newTestNode(t, "dc", nil, nil))
+	simple.AddEdge(newTestEdge(t, "alice", "dc", "HasSession"))
+	if simple.AddEdge(newTestEdge(t, "alice", "dc", "HasSession")) {
+		t.Error("Expected a duplicate edge to be rejected outside multigraph mode")
+	}
+}
+
+func TestUpsertNode(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	added := 0
+	g.OnNodeAdded(func(*node.Node) { added++ })
+
+	if !g.UpsertNode(newTestNode(t, "alice", []string{"User"}, map[string]interface{}{"name": "ALICE", "enabled": false})) {
+		t.Fatal("Expected a new node to be added")
+	}
+
+	incoming := newTestNode(t, "alice", []string{"User", "Base"}, map[string]interface{}{"enabled": true, "logons": 4})
+	if g.UpsertNode(incoming) {
+		t.Fatal("Expected an existing node to be merged")
+	}
+	if added != 1 {
+		t.Errorf("Expected the added hook to fire once, got %d", added)
+	}
+
+	n := g.GetNode("alice")
+	if n == incoming {
+		t.Error("Expected the existing node to be kept")
+	}
+	if !reflect.DeepEqual(n.GetKinds(), []string{"User", "Base"}) {
+		t.Errorf("Expected kinds [User Base], got %v", n.GetKinds())
+	}
+	expected := map[string]interface{}{"name": "ALICE", "enabled": true, "logons": 4}
+	if !reflect.DeepEqual(n.GetProperties().GetAllProperties(), expected) {
+		t.Errorf("Expected properties %v, got %v", expected, n.GetProperties().GetAllProperties())
+	}
+}
+
+func TestUpsertNodeUpdatesPropertyIndex(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.BuildPropertyIndex("enabled")
+	g.UpsertNode(newTestNode(t, "alice", nil, map[string]interface{}{"enabled": false}))
+	g.UpsertNode(newTestNode(t, "alice", nil, map[string]interface{}{"enabled": true}))
+
+	if got := g.GetNodesByPropertyValue("enabled", false); len(got) != 0 {
+		t.Errorf("Expected no disabled node, got %v", nodeIDs(got))
+	}
+	if got := nodeIDs(g.GetNodesByPropertyValue("enabled", true)); !reflect.DeepEqual(got, []string{"alice"}) {
+		t.Errorf("Expected [alice], got %v", got)
+	}
+}
+
+func TestUpsertEdge(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+
+	e := newTestEdge(t, "alice", "admins", "MemberOf")
+	e.SetProperty("source", "ldap")
+	e.SetProperty("isacl", false)
+	if !g.UpsertEdge(e) {
+		t.Fatal("Expected a new edge to be added")
+	}
+
+	incoming := newTestEdge(t, "alice", "admins", "MemberOf")
+	incoming.SetProperty("source", "smb")
+	incoming.SetProperty("seen", 2)
+	if g.UpsertEdge(incoming) {
+		t.Fatal("Expected an existing edge to be merged")
+	}
+	if g.GetEdgeCount() != 1 {
+		t.Fatalf("Expected 1 edge, got %d", g.GetEdgeCount())
+	}
+	expected := map[string]interface{}{"source": "smb", "isacl": false, "seen": 2}
+	if !reflect.DeepEqual(e.GetProperties().GetAllProperties(), expected) {
+		t.Errorf("Expected properties %v, got %v", expected, e.GetProperties().GetAllProperties())
+	}
+
+	if !g.UpsertEdge(newTestEdge(t, "alice", "admins", "AdminTo")) {
+		t.Error("Expected an edge of another kind to be added")
+	}
+	if g.UpsertEdge(newTestEdge(t, "alice", "nobody", "MemberOf")) {
+		t.Error("Expected an edge to a missing node to be rejected")
+	}
+}
+
+func TestReplaceNodeKinds(t *testing.T) {
+	g := gopengraph.NewOpenGraph("Base")
+	g.AddNode(newTestNode(t, "alice", []string{"User", "Tier1"}, nil))
+
+	if err := g.ReplaceNodeKinds("alice", []string{"NewKind"}); err != nil {
+		t.Fatalf("ReplaceNodeKinds failed: %v", err)
+	}
+	if kinds := g.GetNode("alice").GetKinds(); !reflect.DeepEqual(kinds, []string{"NewKind", "Base"}) {
+		t.Errorf("Expected kinds [NewKind Base], got %v", kinds)
+	}
+
+	if err := g.ReplaceNodeKinds("alice", []string{"A", "B", "C"}); err == nil {
+		t.Error("Expected an error when the source kind exceeds the kinds limit")
+	}
+	if err := g.ReplaceNodeKinds("nobody", []string{"User"}); err == nil {
+		t.Error("Expected an error for a missing node")
+	}
+}
+
+func TestReplaceNodeID(t *testing.T) {
+	g := gopengraph.NewOpenGraph("", gopengraph.WithKindIndex())
+	g.AddNode(newTestNode(t, "tmp-1", []string{"User"}, map[string]interface{}{"name": "ALICE"}))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+	g.AddEdge(newTestEdge(t, "tmp-1", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "admins", "tmp-1", "GenericAll"))
+	g.BuildPropertyIndex("name")
+
+	if err := g.ReplaceNodeID("tmp-1", "alice"); err != nil {
+		t.Fatalf("ReplaceNodeID failed: %v", err)
+	}
+	if g.GetNode("tmp-1") != nil || g.GetNode("alice") == nil || g.GetNode("alice").GetID() != "alice" {
+		t.Fatal("Expected the node to be stored under its new ID only")
+	}
+	if got := edgeKeys(g.GetEdgesFromNode("alice")); !reflect.DeepEqual(got, []string{"alice-MemberOf-admins"}) {
+		t.Errorf("Expected [alice-MemberOf-admins], got %v", got)
+	}
+	if got := edgeKeys(g.GetEdgesToNode("alice")); !reflect.DeepEqual(got, []string{"admins-GenericAll-alice"}) {
+		t.Errorf("Expected [admins-GenericAll-alice], got %v", got)
+	}
+	if got := nodeIDs(g.GetNodesByKind("User")); !reflect.DeepEqual(got, []string{"alice"}) {
+		t.Errorf("Expected the kind index to hold [alice], got %v", got)
+	}
+	if got := nodeIDs(g.GetNodesByPropertyValue("name", "ALICE")); !reflect.DeepEqual(got, []string{"alice"}) {
+		t.Errorf("Expected the property index to hold [alice], got %v", got)
+	}
+	if errors := g.ValidateGraph(); len(errors) != 0 {
+		t.Errorf("Expected a valid graph, got %v", errors)
+	}
+
+	if err := g.ReplaceNodeID("alice", "admins"); err == nil {
+		t.Error("Expected an error for an ID already in use")
+	}
+	if err := g.ReplaceNodeID("alice", ""); err == nil {
+		t.Error("Expected an error for an empty ID")
+	}
+	if err := g.ReplaceNodeID("nobody", "carol"); err == nil {
+		t.Error("Expected an error for a missing node")
+	}
+
+	// Changing the ID of a node of the graph behind its back is detected.
+	g.GetNode("alice").SetID("bob")
+	found := false
+	for _, errMsg := range g.ValidateGraph() {
+		if strings.Contains(errMsg, "Node stored under ID alice has ID bob") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected ValidateGraph to report the ID mismatch, got %v", g.ValidateGraph())
+	}
+}
+
+func TestSetEdgeEndpoints(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"a", "b", "c"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	ab := newTestEdge(t, "a", "b", "Knows")
+	g.AddEdge(ab)
+	g.AddEdge(newTestEdge(t, "a", "c", "Knows"))
+
+	if err := g.SetEdgeEndpoints(ab, edge.NewEndpointByID("c"), edge.NewEndpointByID("b")); err != nil {
+		t.Fatalf("SetEdgeEndpoints failed: %v", err)
+	}
+	if got := edgeKeys(g.GetEdgesFromNode("c")); !reflect.DeepEqual(got, []string{"c-Knows-b"}) {
+		t.Errorf("Expected [c-Knows-b], got %v", got)
+	}
+	if got := edgeKeys(g.GetEdgesFromNode("a")); !reflect.DeepEqual(got, []string{"a-Knows-c"}) {
+		t.Errorf("Expected [a-Knows-c], got %v", got)
+	}
+	if errors := g.ValidateGraph(); len(errors) != 0 {
+		t.Errorf("Expected a valid graph, got %v", errors)
+	}
+
+	if err := g.SetEdgeEndpoints(ab, edge.NewEndpointByID("a"), edge.NewEndpointByID("c")); err == nil {
+		t.Error("Expected an error for a duplicate edge")
+	}
+	if err := g.SetEdgeEndpoints(ab, edge.NewEndpointByID("nobody"), edge.NewEndpointByID("b")); err == nil {
+		t.Error("Expected an error for a missing node")
+	}
+	if err := g.SetEdgeEndpoints(newTestEdge(t, "a", "b", "Knows"), edge.NewEndpointByID("a"), edge.NewEndpointByID("b")); err == nil {
+		t.Error("Expected an error for an edge not in the graph")
+	}
+	if ab.GetStartNodeID() != "c" || ab.GetEndNodeID() != "b" {
+		t.Errorf("Expected the edge to be unchanged on error, got %s -> %s", ab.GetStartNodeID(), ab.GetEndNodeID())
+	}
+
+	// Changing the endpoints of an edge of the graph behind its back is detected.
+	ab.SetStart(edge.NewEndpointByID("b"))
+	found := false
+	for _, errMsg := range g.ValidateGraph() {
+		if strings.Contains(errMsg, "Edge Knows (b -> b) is not indexed under its endpoints") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected ValidateGraph to report the stale edge index, got %v", g.ValidateGraph())
+	}
+}
+
+func TestSortEdges(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"a", "b", "c"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "b", "a", "Knows"))
+	g.AddEdge(newTestEdge(t, "a", "c", "Knows"))
+	g.AddEdge(newTestEdge(t, "a", "b", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "a", "b", "AdminTo"))
+
+	if g.IsSorted() {
+		t.Fatal("Expected edges in insertion order not to be sorted")
+	}
+
+	g.SortEdgesBy("start")
+	if !g.IsSorted() {
+		t.Fatal("Expected edges to be sorted after SortEdgesBy(\"start\")")
+	}
+
+	expected := []string{"a-AdminTo-b", "a-MemberOf-b", "a-Knows-c", "b-Knows-a"}
+	edges := g.FilterEdgesByPredicate(func(e *edge.Edge) bool { return true })
+	for i, e := range edges {
+		got := e.GetStartNodeID() + "-" + e.GetKind() + "-" + e.GetEndNodeID()
+		if got != expected[i] {
+			t.Errorf("Expected edge %d to be %s, got %s", i, expected[i], got)
+		}
+	}
+}
+
+func TestSortEdgesByKind(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"a", "b", "c"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "b", "c", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "a", "c", "Knows"))
+	g.AddEdge(newTestEdge(t, "a", "b", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "c", "a", "AdminTo"))
+
+	describe := func() []string {
+		var result []string
+		for _, e := range g.FilterEdgesByPredicate(func(e *edge.Edge) bool { return true }) {
+			result = append(result, e.GetStartNodeID()+"-"+e.GetKind()+"-"+e.GetEndNodeID())
+		}
+		return result
+	}
+
+	g.SortEdgesByKind()
+	ascending := []string{"c-AdminTo-a", "a-Knows-c", "a-MemberOf-b", "b-MemberOf-c"}
+	if got := describe(); !reflect.DeepEqual(got, ascending) {
+		t.Errorf("Expected %v, got %v", ascending, got)
+	}
+
+	g.SortEdges(func(a, b *edge.Edge) bool { return a.GetKind() > b.GetKind() })
+	// The sort is stable: edges of the same kind keep their previous order.
+	descending := []string{"a-MemberOf-b", "b-MemberOf-c", "a-Knows-c", "c-AdminTo-a"}
+	if got := describe(); !reflect.DeepEqual(got, descending) {
+		t.Errorf("Expected %v, got %v", descending, got)
+	}
+}
+
+func TestSortEdgesByProperty(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	for _, e := range []struct {
+		end    string
+		weight interface{}
+	}{{"b", 10}, {"c", nil}, {"d", 2.5}} {
+		created := newTestEdge(t, "a", e.end, "Knows")
+		if e.weight != nil {
+			created.SetProperty("weight", e.weight)
+		}
+		g.AddEdge(created)
+	}
+
+	g.SortEdgesBy("weight")
+	var ends []string
+	for _, e := range g.FilterEdgesByPredicate(func(e *edge.Edge) bool { return true }) {
+		ends = append(ends, e.GetEndNodeID())
+	}
+	// Numbers compare numerically, and the edge without a weight comes last.
+	if expected := []string{"d", "b", "c"}; !reflect.DeepEqual(ends, expected) {
+		t.Errorf("Expected %v, got %v", expected, ends)
+	}
+}
+
+func TestSortNodes(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "1", []string{"User"}, map[string]interface{}{"name": "carol"}))
+	g.AddNode(newTestNode(t, "2", []string{"User"}, map[string]interface{}{"name": "alice"}))
+	g.AddNode(newTestNode(t, "3", []string{"Group"}, map[string]interface{}{"name": "bob"}))
+
+	byName := g.SortNodes(func(a, b *node.Node) bool {
+		return a.GetProperty("name").(string) < b.GetProperty("name").(string)
+	})
+	if got := nodeIDs(byName); !reflect.DeepEqual(got, []string{"2", "3", "1"}) {
+		t.Errorf("Expected [2 3 1], got %v", got)
+	}
+
+	// Nodes comparing equal come in ID order.
+	byKind := g.SortNodes(func(a, b *node.Node) bool {
+		return a.GetKinds()[0] < b.GetKinds()[0]
+	})
+	if got := nodeIDs(byKind); !reflect.DeepEqual(got, []string{"3", "1", "2"}) {
+		t.Errorf("Expected [3 1 2], got %v", got)
+	}
+}
+
+func TestExportJSONDeterministic(t *testing.T) {
+	g := gopengraph.NewOpenGraph("Base")
+	for _, id := range []string{"5", "3", "1", "4", "2"} {
+		g.AddNode(newTestNode(t, id, []string{"User"}, map[string]interface{}{"name": "user" + id, "rank": id}))
+	}
+	g.AddEdge(newTestEdge(t, "5", "1", "Knows"))
+	g.AddEdge(newTestEdge(t, "1", "5", "Knows"))
+	g.AddEdge(newTestEdge(t, "3", "2", "AdminTo"))
+
+	first, err := g.ExportJSON(true)
+	if err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+	second, err := g.ExportJSON(true)
+	if err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+	if first != second {
+		t.Errorf("Expected repeated exports to be byte-identical:\n%s\n%s", first, second)
+	}
+	if g.IsSorted() {
+		t.Error("Expected ExportJSON not to reorder the edges of the graph")
+	}
+}
+
+func TestExportJSONDeterministicAcrossGraphs(t *testing.T) {
+	build := func(ids []string) *gopengraph.OpenGraph {
+		g := gopengraph.NewOpenGraph("Base")
+		for _, id := range ids {
+			g.AddNode(newTestNode(t, id, []string{"User"}, map[string]interface{}{"name": "user" + id}))
+		}
+		for _, id := range ids {
+			if id != "1" {
+				g.AddEdge(newTestEdge(t, id, "1", "MemberOf"))
+			}
+		}
+		return g
+	}
+
+	forward, err := build([]string{"1", "2", "3", "4"}).ExportJSON(true)
+	if err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+	backward, err := build([]string{"4", "3", "2", "1"}).ExportJSON(true)
+	if err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+	if forward != backward {
+		t.Errorf("Expected graphs with the same content to export byte-identical JSON:\n%s\n%s", forward, backward)
+	}
+}
+
+func TestExportJSONSorted(t *testing.T) {
+	// build creates the same graph, inserting nodes and edges in the given order.
+	build := func(ids []string) *gopengraph.OpenGraph {
+		g := gopengraph.NewOpenGraph("Base")
+		for _, id := range ids {
+			g.AddNode(newTestNode(t, id, []string{"User"}, map[string]interface{}{"name": "user" + id}))
+		}
+		for _, id := range ids {
+			if id != "1" {
+				g.AddEdge(newTestEdge(t, "1", id, "Knows"))
+			}
+		}
+		return g
+	}
+
+	forward := build([]string{"1", "2", "3", "4"})
+	forwardJSON, err := forward.ExportJSONSorted(true)
+	if err != nil {
+		t.Fatalf("ExportJSONSorted failed: %v", err)
+	}
+	backward := build([]string{"4", "3", "2", "1"})
+	backwardJSON, err := backward.ExportJSONSorted(true)
+	if err != nil {
+		t.Fatalf("ExportJSONSorted failed: %v", err)
+	}
+
+	if !backward.IsSorted() {
+		t.Error("Expected ExportJSONSorted to sort the edges of the graph")
+	}
+	if forwardJSON != backwardJSON {
+		t.Errorf("Expected graphs with the same content to export byte-identical JSON:\n%s\n%s", forwardJSON, backwardJSON)
+	}
+}
+
+func TestGetConsistencyHash(t *testing.T) {
+	build := func(ids []string) *gopengraph.OpenGraph {
+		g := gopengraph.NewOpenGraph("Base")
+		for _, id := range ids {
+			g.AddNode(newTestNode(t, id, []string{"User"}, map[string]interface{}{"name": "user" + id}))
+		}
+		for _, id := range ids {
+			if id != "1" {
+				g.AddEdge(newTestEdge(t, "1", id, "Knows"))
+			}
+		}
+		return g
+	}
+
+	forward := build([]string{"1", "2", "3"})
+	backward := build([]string{"3", "2", "1"})
+
+	forwardHash, err := forward.GetConsistencyHash()
+	if err != nil {
+		t.Fatalf("GetConsistencyHash failed: %v", err)
+	}
+	backwardHash, err := backward.GetConsistencyHash()
+	if err != nil {
+		t.Fatalf("GetConsistencyHash failed: %v", err)
+	}
+	if len(forwardHash) != 64 {
+		t.Errorf("Expected a hex-encoded SHA-256 hash, got %q", forwardHash)
+	}
+	if forwardHash != backwardHash {
+		t.Errorf("Expected equivalent graphs to have the same hash, got %s and %s", forwardHash, backwardHash)
+	}
+	if backward.IsSorted() {
+		t.Error("Expected GetConsistencyHash not to reorder the edges of the graph")
+	}
+
+	forward.GetNode("2").SetProperty("name", "changed")
+	if changed, _ := forward.GetConsistencyHash(); changed == forwardHash {
+		t.Error("Expected a property change to change the hash")
+	}
+}
+
+func TestGetEdgesByStartAndEndAndKind(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"alice", "bob", "admins", "dc"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "alice", "dc", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "alice", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "bob", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "admins", "dc", "AdminTo"))
+
+	if got := edgeKeys(g.GetEdgesByStartAndKind("alice", "MemberOf")); !reflect.DeepEqual(got, []string{"alice-MemberOf-admins"}) {
+		t.Errorf("Expected [alice-MemberOf-admins], got %v", got)
+	}
+	if got := edgeKeys(g.GetEdgesByEndAndKind("admins", "MemberOf")); !reflect.DeepEqual(got, []string{"alice-MemberOf-admins", "bob-MemberOf-admins"}) {
+		t.Errorf("Expected [alice-MemberOf-admins bob-MemberOf-admins], got %v", got)
+	}
+
+	// The index follows reordering and removals.
+	g.SortEdges(func(a, b *edge.Edge) bool { return a.GetStartNodeID() > b.GetStartNodeID() })
+	if got := edgeKeys(g.GetEdgesByEndAndKind("admins", "MemberOf")); !reflect.DeepEqual(got, []string{"bob-MemberOf-admins", "alice-MemberOf-admins"}) {
+		t.Errorf("Expected the sorted order, got %v", got)
+	}
+	g.RemoveNodeByID("bob")
+	if got := edgeKeys(g.GetEdgesByEndAndKind("admins", "MemberOf")); !reflect.DeepEqual(got, []string{"alice-MemberOf-admins"}) {
+		t.Errorf("Expected [alice-MemberOf-admins] after removing bob, got %v", got)
+	}
+	if got := edgeKeys(g.GetEdgesToNode("dc")); !reflect.DeepEqual(got, []string{"alice-AdminTo-dc", "admins-AdminTo-dc"}) {
+		t.Errorf("Expected the two edges to dc, got %v", got)
+	}
+
+	g.Clear()
+	for _, edges := range [][]*edge.Edge{
+		g.GetEdgesByStartAndKind("alice", "MemberOf"),
+		g.GetEdgesByEndAndKind("admins", "MemberOf"),
+	} {
+		if edges == nil || len(edges) != 0 {
+			t.Errorf("Expected an empty non-nil slice, got %#v", edges)
+		}
+	}
+}
+
+// newEdgeLookupBenchmarkGraph builds a graph of 1 000 nodes, each with 10
+// outgoing edges of two kinds.
+func newEdgeLookupBenchmarkGraph(b *testing.B) *gopengraph.OpenGraph {
+	b.Helper()
+	g := gopengraph.NewOpenGraph("")
+	for i := 0; i < 1000; i++ {
+		g.AddNode(newTestNode(b, fmt.Sprintf("n%d", i), nil, nil))
+	}
+	kinds := []string{"MemberOf", "AdminTo"}
+	for i := 0; i < 1000; i++ {
+		for j := 1; j <= 10; j++ {
+			g.AddEdgeWithoutValidation(newTestEdge(b, fmt.Sprintf("n%d", i), fmt.Sprintf("n%d", (i+j)%1000), kinds[j%2]))
+		}
+	}
+	return g
+}
+
+func BenchmarkGetEdgesByStartAndKind(b *testing.B) {
+	g := newEdgeLookupBenchmarkGraph(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.GetEdgesByStartAndKind("n500", "MemberOf")
+	}
+}
+
+func BenchmarkFilterEdgesByStartAndKind(b *testing.B) {
+	g := newEdgeLookupBenchmarkGraph(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.FilterEdgesByPredicate(func(e *edge.Edge) bool {
+			return e.GetStartNodeID() == "n500" && e.GetKind() == "MemberOf"
+		})
+	}
+}
+
+func TestGetEdgesFromAndToNodeByKind(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"alice", "bob", "admins", "dc"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "alice", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "alice", "dc", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "alice", "bob", "Knows"))
+	g.AddEdge(newTestEdge(t, "alice", "dc", "HasSession"))
+	g.AddEdge(newTestEdge(t, "bob", "dc", "AdminTo"))
+
+	tests := []struct {
+		kind string
+		from []string
+		to   []string
+	}{
+		{"MemberOf", []string{"alice-MemberOf-admins"}, []string{}},
+		{"AdminTo", []string{"alice-AdminTo-dc"}, []string{"alice-AdminTo-dc", "bob-AdminTo-dc"}},
+		{"Knows", []string{"alice-Knows-bob"}, []string{}},
+		{"Contains", []string{}, []string{}},
+	}
+	for _, tt := range tests {
+		if got := edgeKeys(g.GetEdgesFromNodeByKind("alice", tt.kind)); !reflect.DeepEqual(got, tt.from) {
+			t.Errorf("Expected %v from alice for kind %s, got %v", tt.from, tt.kind, got)
+		}
+		if got := edgeKeys(g.GetEdgesToNodeByKind("dc", tt.kind)); !reflect.DeepEqual(got, tt.to) {
+			t.Errorf("Expected %v to dc for kind %s, got %v", tt.to, tt.kind, got)
+		}
+	}
+}
+
+func BenchmarkGetEdgesFromNodeByKind(b *testing.B) {
+	g := newEdgeLookupBenchmarkGraph(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.GetEdgesFromNodeByKind("n500", "MemberOf")
+	}
+}
+
+func BenchmarkGetEdgesFromNodeThenFilterByKind(b *testing.B) {
+	g := newEdgeLookupBenchmarkGraph(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var edges []*edge.Edge
+		for _, e := range g.GetEdgesFromNode("n500") {
+			if e.GetKind() == "MemberOf" {
+				edges = append(edges, e)
+			}
+		}
+	}
+}
+
+func TestGetEdgesByNodePairAndBetweenNodes(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", nil, nil))
+	g.AddNode(newTestNode(t, "bob", nil, nil))
+	g.AddNode(newTestNode(t, "carol", nil, nil))
+	g.AddEdge(newTestEdge(t, "alice", "bob", "Knows"))
+	g.AddEdge(newTestEdge(t, "alice", "bob", "Manages"))
+	g.AddEdge(newTestEdge(t, "bob", "alice", "Knows"))
+	g.AddEdge(newTestEdge(t, "alice", "carol", "Knows"))
+
+	kinds := func(edges []*edge.Edge) []string {
+		result := make([]string, 0, len(edges))
+		for _, e := range edges {
+			result = append(result, e.GetStartNodeID()+"-"+e.GetKind()+"-"+e.GetEndNodeID())
+		}
+		return result
+	}
+
+	if got := kinds(g.GetEdgesByNodePair("alice", "bob")); !reflect.DeepEqual(got, []string{"alice-Knows-bob", "alice-Manages-bob"}) {
+		t.Errorf("Expected the two edges from alice to bob, got %v", got)
+	}
+	if got := kinds(g.GetEdgesByNodePair("bob", "alice")); !reflect.DeepEqual(got, []string{"bob-Knows-alice"}) {
+		t.Errorf("Expected the edge from bob to alice, got %v", got)
+	}
+	if got := kinds(g.GetEdgesBetweenNodes("bob", "alice")); !reflect.DeepEqual(got, []string{"alice-Knows-bob", "alice-Manages-bob", "bob-Knows-alice"}) {
+		t.Errorf("Expected the three edges between alice and bob, got %v", got)
+	}
+
+	for _, edges := range [][]*edge.Edge{
+		g.GetEdgesByNodePair("bob", "carol"),
+		g.GetEdgesByNodePair("alice", "missing"),
+		g.GetEdgesBetweenNodes("missing", "alice"),
+	} {
+		if edges == nil || len(edges) != 0 {
+			t.Errorf("Expected an empty non-nil slice, got %#v", edges)
+		}
+	}
+}
+
+// newTestNode creates a node with the given kinds and properties, failing the
+// test if the node is invalid.
+func newTestNode(t testing.TB, id string, kinds []string, props map[string]interface{}) *node.Node {
+	t.Helper()
+	n, err := node.NewNode(id, kinds, properties.NewPropertiesFromMap(props))
+	if err != nil {
+		t.Fatalf("Failed to create node: %v", err)
+	}
+	return n
+}
+
+// newTestEdge creates an id-matched edge without properties, failing the test if
+// the edge is invalid.
+func newTestEdge(t testing.TB, start, end, kind string) *edge.Edge {
+	t.Helper()
+	e, err := edge.NewEdge(start, end, kind, properties.NewProperties())
+	if err != nil {
+		t.Fatalf("Failed to create edge: %v", err)
+	}
+	return e
+}
+
+// newTestGraph builds the graph shared by the tests: the users alice and bob,
+// members of the admins group through edges with a "source" property. Tests
+// add the nodes and edges they need on top of it.
+func newTestGraph(t *testing.T) *gopengraph.OpenGraph {
+	t.Helper()
+	g := gopengraph.NewOpenGraph("")
+
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, map[string]interface{}{"name": "ALICE", "logons": 3}))
+	g.AddNode(newTestNode(t, "bob", []string{"User"}, map[string]interface{}{"name": "BOB", "logons": 7}))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, map[string]interface{}{"name": "ADMINS", "logons": 3}))
+
+	e1 := newTestEdge(t, "alice", "admins", "MemberOf")
+	e1.SetProperty("source", "ldap")
+	g.AddEdge(e1)
+	e2 := newTestEdge(t, "bob", "admins", "MemberOf")
+	e2.SetProperty("source", "smb")
+	g.AddEdge(e2)
+
+	return g
+}
+
+// nodeIDs returns the IDs of nodes, in order.
+func nodeIDs(nodes []*node.Node) []string {
+	ids := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		ids = append(ids, n.GetID())
+	}
+	return ids
+}
+
+// edgeKeys returns "start-kind-end" keys for edges, in order.
+func edgeKeys(edges []*edge.Edge) []string {
+	keys := make([]string, 0, len(edges))
+	for _, e := range edges {
+		keys = append(keys, e.GetStartNodeID()+"-"+e.GetKind()+"-"+e.GetEndNodeID())
+	}
+	return keys
+}
@@ -0,0 +1,92 @@
+package gopengraph
+
+// Option configures an OpenGraph created by NewOpenGraph.
+type Option func(*OpenGraph)
+
+// Graph options
+
+// WithMultiEdges makes AddEdge accept several edges with the same endpoints and
+// kind, as NewOpenGraphMulti does.
+func WithMultiEdges() Option {
+	return func(g *OpenGraph) {
+		g.multiEdges = true
+	}
+}
+
+// WithAutoSourceKind makes AddNode, SetSourceKind and ReplaceNodeKinds add the
+// source kind of the graph to its nodes. This is the default.
+func WithAutoSourceKind() Option {
+	return func(g *OpenGraph) {
+		g.autoSourceKind = true
+	}
+}
+
+// WithoutAutoSourceKind keeps the kinds of the nodes as given: the source kind
+// of the graph is only recorded in its metadata.
+func WithoutAutoSourceKind() Option {
+	return func(g *OpenGraph) {
+		g.autoSourceKind = false
+	}
+}
+
+// WithConcurrency guards the graph with a mutex so that the following methods
+// may be called from several goroutines at once: AddNode,
+// AddNodeWithoutValidation, AddEdge, AddEdgeWithoutValidation, RemoveNodeByID,
+// GetNode, GetNodesByKind, GetEdgesFromNode, GetEdgesToNode, GetNodeCount and
+// GetEdgeCount. Other methods are not guarded and must not run concurrently
+// with any call on the graph. Hooks run after the mutex is released.
+func WithConcurrency() Option {
+	return func(g *OpenGraph) {
+		g.concurrent = true
+	}
+}
+
+// WithKindIndex builds the kind index used by GetNodesByKind upfront and keeps
+// it up to date as nodes and kinds change through the graph, instead of
+// building it on the first call and rebuilding it after every change. This
+// suits graphs that interleave node additions and kind queries.
+func WithKindIndex() Option {
+	return func(g *OpenGraph) {
+		g.eagerKindIndex = true
+		g.kindIndex = make(map[string]map[string]bool)
+	}
+}
+
+// options returns the options reproducing the configuration of g, for graphs
+// derived from it.
+func (g *OpenGraph) options() []Option {
+	var opts []Option
+	if g.multiEdges {
+		opts = append(opts, WithMultiEdges())
+	}
+	if !g.autoSourceKind {
+		opts = append(opts, WithoutAutoSourceKind())
+	}
+	if g.concurrent {
+		opts = append(opts, WithConcurrency())
+	}
+	if g.eagerKindIndex {
+		opts = append(opts, WithKindIndex())
+	}
+	return opts
+}
+
+// lock acquires the mutex of the graph for writing if WithConcurrency is set,
+// and returns the function releasing it.
+func (g *OpenGraph) lock() func() {
+	if !g.concurrent {
+		return func() {}
+	}
+	g.mu.Lock()
+	return g.mu.Unlock
+}
+
+// rlock acquires the mutex of the graph for reading if WithConcurrency is set,
+// and returns the function releasing it.
+func (g *OpenGraph) rlock() func() {
+	if !g.concurrent {
+		return func() {}
+	}
+	g.mu.RLock()
+	return g.mu.RUnlock
+}
@@ -0,0 +1,118 @@
+package gopengraph_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+func TestNewOpenGraphDefaults(t *testing.T) {
+	g := gopengraph.NewOpenGraph("Base")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "bob", []string{"User"}, nil))
+
+	if !g.GetNode("alice").HasKind("Base") {
+		t.Error("Expected the source kind to be added by default")
+	}
+	if g.IsMultiGraph() {
+		t.Error("Expected duplicate edges to be rejected by default")
+	}
+	g.AddEdge(newTestEdge(t, "alice", "bob", "Knows"))
+	if g.AddEdge(newTestEdge(t, "alice", "bob", "Knows")) {
+		t.Error("Expected a duplicate edge to be rejected by default")
+	}
+}
+
+func TestWithMultiEdges(t *testing.T) {
+	g := gopengraph.NewOpenGraph("", gopengraph.WithMultiEdges())
+	g.AddNode(newTestNode(t, "alice", nil, nil))
+	g.AddNode(newTestNode(t, "bob", nil, nil))
+	g.AddEdge(newTestEdge(t, "alice", "bob", "Knows"))
+	if !g.AddEdge(newTestEdge(t, "alice", "bob", "Knows")) || g.GetEdgeCount() != 2 {
+		t.Errorf("Expected both parallel edges to be added, got %d edges", g.GetEdgeCount())
+	}
+}
+
+func TestWithoutAutoSourceKind(t *testing.T) {
+	g := gopengraph.NewOpenGraph("Base", gopengraph.WithoutAutoSourceKind())
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	if g.GetNode("alice").HasKind("Base") {
+		t.Error("Expected AddNode not to add the source kind")
+	}
+	if err := g.ReplaceNodeKinds("alice", []string{"Person"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g.SetSourceKind("Other")
+	if kinds := g.GetNode("alice").GetKinds(); len(kinds) != 1 || kinds[0] != "Person" {
+		t.Errorf("Expected kinds [Person], got %v", kinds)
+	}
+
+	// WithAutoSourceKind restores the default when given last.
+	g = gopengraph.NewOpenGraph("Base", gopengraph.WithoutAutoSourceKind(), gopengraph.WithAutoSourceKind())
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	if !g.GetNode("alice").HasKind("Base") {
+		t.Error("Expected WithAutoSourceKind to add the source kind")
+	}
+}
+
+func TestWithKindIndex(t *testing.T) {
+	g := gopengraph.NewOpenGraph("", gopengraph.WithKindIndex())
+	for i := 0; i < 4; i++ {
+		g.AddNode(newTestNode(t, fmt.Sprintf("u%d", i), []string{"User"}, nil))
+		if got := len(g.GetNodesByKind("User")); got != i+1 {
+			t.Fatalf("Expected %d users, got %d", i+1, got)
+		}
+	}
+
+	g.RemoveNodeByID("u0")
+	if err := g.AddKindToNode("u1", "Admin"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := g.ReplaceNodeKinds("u2", []string{"Computer"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for kind, want := range map[string][]string{"User": {"u1", "u3"}, "Admin": {"u1"}, "Computer": {"u2"}} {
+		if got := sortedNodeIDsOfKind(g, kind); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("Expected %v for kind %s, got %v", want, kind, got)
+		}
+	}
+
+	g.Clear()
+	if got := g.GetNodesByKind("User"); len(got) != 0 {
+		t.Errorf("Expected no node after Clear, got %v", nodeIDs(got))
+	}
+}
+
+func TestWithConcurrency(t *testing.T) {
+	g := gopengraph.NewOpenGraph("Base", gopengraph.WithConcurrency())
+	g.AddNode(newTestNode(t, "hub", nil, nil))
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				id := fmt.Sprintf("n%d-%d", w, i)
+				n, _ := node.NewNode(id, nil, nil)
+				g.AddNode(n)
+				e, _ := edge.NewEdge(id, "hub", "Knows", nil)
+				g.AddEdge(e)
+				g.GetNodesByKind("Base")
+				g.GetEdgesToNode("hub")
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if g.GetNodeCount() != 401 || g.GetEdgeCount() != 400 {
+		t.Errorf("Expected 401 nodes and 400 edges, got %d and %d", g.GetNodeCount(), g.GetEdgeCount())
+	}
+	if got := len(g.GetNodesByKind("Base")); got != 401 {
+		t.Errorf("Expected 401 nodes of kind Base, got %d", got)
+	}
+}
@@ -0,0 +1,421 @@
+package gopengraph
+
+import (
+	"fmt"
+
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+// Path is a sequence of node IDs, from the start node to the end node, such
+// that consecutive nodes are joined by an edge of the graph.
+type Path []string
+
+// Path queries
+
+// GetPathsBetweenKinds finds the paths from any node of kind fromKind to any
+// node of kind toKind, e.g. from every User to every Domain Admins group.
+//
+// Paths are found with FindPathsWithFilter for every pair of distinct nodes, so
+// maxDepth has the same meaning as in FindPaths. A node having both kinds is
+// not considered a path by itself.
+//
+// Arguments:
+//
+//	fromKind string: The kind of the start nodes.
+//	toKind string: The kind of the end nodes.
+//	edgeKind string: The only kind of edges the paths may follow, or "" to
+//	                 follow edges of any kind.
+//	maxDepth int: The maximum depth of the paths to find.
+//
+// Returns:
+//
+//	[]Path: The paths, ordered by start node ID then end node ID, or an empty
+//	        slice if there are none.
+func (g *OpenGraph) GetPathsBetweenKinds(fromKind, toKind, edgeKind string, maxDepth int) []Path {
+	var edgeFilter func(*edge.Edge) bool
+	if edgeKind != "" {
+		edgeFilter = func(e *edge.Edge) bool {
+			return e.GetKind() == edgeKind
+		}
+	}
+
+	targets := g.GetNodesByAllKinds([]string{toKind})
+	paths := make([]Path, 0)
+	for _, from := range g.GetNodesByAllKinds([]string{fromKind}) {
+		for _, to := range targets {
+			if from.GetID() == to.GetID() {
+				continue
+			}
+			for _, path := range g.FindPathsWithFilter(from.GetID(), to.GetID(), maxDepth, edgeFilter, nil) {
+				paths = append(paths, Path(path))
+			}
+		}
+	}
+	return paths
+}
+
+// FindPathsWithKindConstraints finds the paths from one node to another whose
+// edges follow a sequence of kinds, e.g. [MemberOf, HasSession, AdminTo] to
+// model a specific privilege escalation chain.
+//
+// A path matches when it has exactly one edge per element of kindSequence and
+// its i-th edge has kind kindSequence[i], an empty kind accepting an edge of
+// any kind. Paths do not visit a node twice, and a step joined by several
+// matching edges yields the path once.
+//
+// Arguments:
+//
+//	startID string: The ID of the start node.
+//	endID string: The ID of the end node.
+//	maxDepth int: The maximum depth of the paths to find, so a kindSequence
+//	              longer than maxDepth matches no path.
+//	kindSequence []string: The kinds of the edges of the paths, in order.
+//
+// Returns:
+//
+//	[][]string: The paths, in the order of the edges leaving each node, or an
+//	            empty slice if there are none or either node does not exist.
+func (g *OpenGraph) FindPathsWithKindConstraints(startID, endID string, maxDepth int, kindSequence []string) [][]string {
+	if len(kindSequence) > maxDepth {
+		return make([][]string, 0)
+	}
+	return g.kindSequencePathsTo(startID, endID, kindSequence)
+}
+
+// FindPathsWithEdgeKindSequence finds the paths from one node to another whose
+// i-th edge has kind sequence[i], e.g. [MemberOf, HasSession, AdminTo].
+//
+// This is FindPathsWithKindConstraints without a depth limit: paths have
+// exactly len(sequence) edges, do not visit a node twice, and an empty kind
+// accepts an edge of any kind. An empty sequence only matches the path made of
+// startID alone, when startID and endID are the same node.
+//
+// Arguments:
+//
+//	startID string: The ID of the start node.
+//	endID string: The ID of the end node.
+//	sequence []string: The kinds of the edges of the paths, in order.
+//
+// Returns:
+//
+//	[][]string: The paths, in the order of the edges leaving each node, or an
+//	            empty slice if there are none or either node does not exist.
+func (g *OpenGraph) FindPathsWithEdgeKindSequence(startID, endID string, sequence []string) [][]string {
+	return g.kindSequencePathsTo(startID, endID, sequence)
+}
+
+// GetChainedPaths follows a chain of edge kinds from a node and returns every
+// path it leads to, e.g. the hosts reachable from a user through
+// [MemberOf, AdminTo].
+//
+// A path has exactly len(kindChain) edges, so len(kindChain)+1 node IDs, and
+// its i-th edge has kind kindChain[i], an empty kind accepting an edge of any
+// kind. As in FindPathsWithEdgeKindSequence, paths do not visit a node twice.
+//
+// Arguments:
+//
+//	startID string: The ID of the start node.
+//	kindChain []string: The kinds of the edges of the paths, in order.
+//
+// Returns:
+//
+//	[][]string: The paths, in the order of the edges leaving each node, or an
+//	            empty slice if there are none or the node does not exist.
+func (g *OpenGraph) GetChainedPaths(startID string, kindChain []string) [][]string {
+	return g.kindSequencePaths(startID, kindChain)
+}
+
+// kindSequencePathsTo returns the paths of kindSequencePaths that end at endID.
+func (g *OpenGraph) kindSequencePathsTo(startID, endID string, sequence []string) [][]string {
+	paths := make([][]string, 0)
+	if _, exists := g.nodes[endID]; !exists {
+		return paths
+	}
+	for _, path := range g.kindSequencePaths(startID, sequence) {
+		if path[len(path)-1] == endID {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// kindSequencePaths returns the simple paths from startID whose i-th edge has
+// kind sequence[i], an empty kind matching any edge.
+func (g *OpenGraph) kindSequencePaths(startID string, sequence []string) [][]string {
+	paths := make([][]string, 0)
+	if _, exists := g.nodes[startID]; !exists {
+		return paths
+	}
+
+	path := []string{startID}
+	onPath := map[string]bool{startID: true}
+	var walk func(current string)
+	walk = func(current string) {
+		step := len(path) - 1
+		if step == len(sequence) {
+			paths = append(paths, append([]string{}, path...))
+			return
+		}
+
+		followed := make(map[string]bool)
+		for _, e := range g.GetEdgesFromNode(current) {
+			nextID := e.GetEndNodeID()
+			if sequence[step] != "" && e.GetKind() != sequence[step] {
+				continue
+			}
+			if onPath[nextID] || followed[nextID] {
+				continue
+			}
+			if _, exists := g.nodes[nextID]; !exists {
+				continue
+			}
+			followed[nextID] = true
+
+			onPath[nextID] = true
+			path = append(path, nextID)
+			walk(nextID)
+			path = path[:len(path)-1]
+			delete(onPath, nextID)
+		}
+	}
+	walk(startID)
+	return paths
+}
+
+// FindShortestPathExcluding finds a shortest path from one node to another
+// that does not go through any of the given nodes, e.g. to check whether an
+// attack path survives the hardening of some hosts.
+//
+// Edges are followed in their direction and a shortest path has the fewest
+// edges; ties are broken by the insertion order of the edges.
+//
+// Arguments:
+//
+//	startID string: The ID of the start node.
+//	endID string: The ID of the end node.
+//	forbiddenNodes []string: The IDs of the nodes the path must avoid. A path
+//	                         starting or ending at one of them is never found.
+//
+// Returns:
+//
+//	[]string: The node IDs of the path, from startID to endID, or nil if there
+//	          is none.
+//	bool: True if a path was found.
+func (g *OpenGraph) FindShortestPathExcluding(startID, endID string, forbiddenNodes []string) ([]string, bool) {
+	forbidden := make(map[string]bool, len(forbiddenNodes))
+	for _, id := range forbiddenNodes {
+		forbidden[id] = true
+	}
+	if forbidden[startID] {
+		return nil, false
+	}
+
+	path := g.shortestPathWhere(startID, endID, func(e *edge.Edge) bool {
+		return !forbidden[e.GetEndNodeID()]
+	})
+	return path, path != nil
+}
+
+// FindShortestPathExcludingEdges finds a shortest path from one node to
+// another that does not use any of the given edges, e.g. to check whether an
+// attack path survives the removal of some permissions.
+//
+// Edges are excluded by identity, so an edge that is equal to a forbidden one
+// but is a different object, such as a parallel edge of a multigraph, can
+// still be used. Shortest paths are found as in FindShortestPathExcluding.
+//
+// Arguments:
+//
+//	startID string: The ID of the start node.
+//	endID string: The ID of the end node.
+//	forbiddenEdges []*edge.Edge: The edges of the graph the path must avoid.
+//
+// Returns:
+//
+//	[]string: The node IDs of the path, from startID to endID, or nil if there
+//	          is none.
+//	bool: True if a path was found.
+func (g *OpenGraph) FindShortestPathExcludingEdges(startID, endID string, forbiddenEdges []*edge.Edge) ([]string, bool) {
+	forbidden := make(map[*edge.Edge]bool, len(forbiddenEdges))
+	for _, e := range forbiddenEdges {
+		forbidden[e] = true
+	}
+
+	path := g.shortestPathWhere(startID, endID, func(e *edge.Edge) bool {
+		return !forbidden[e]
+	})
+	return path, path != nil
+}
+
+// shortestPathWhere returns a path from startID to endID with the fewest
+// edges, following only the edges accepted by edgeFilter, or nil if there is
+// none or either node does not exist.
+func (g *OpenGraph) shortestPathWhere(startID, endID string, edgeFilter func(*edge.Edge) bool) []string {
+	if _, exists := g.nodes[startID]; !exists {
+		return nil
+	}
+	if _, exists := g.nodes[endID]; !exists {
+		return nil
+	}
+
+	adjacency := g.successorsWhere(edgeFilter)
+	parent := map[string]string{startID: startID}
+	queue := []string{startID}
+	for len(queue) > 0 && queue[0] != endID {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[current] {
+			if _, visited := parent[next]; !visited {
+				parent[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	if _, reached := parent[endID]; !reached {
+		return nil
+	}
+
+	path := []string{endID}
+	for id := endID; id != startID; {
+		id = parent[id]
+		path = append(path, id)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// GetPathEdges returns the edges traversed by a path, such as one returned by
+// FindPaths.
+//
+// For every step of the path, all the edges from the node to the next one are
+// returned, in insertion order, so a step joined by edges of several kinds
+// contributes all of them and the caller decides which to use.
+//
+// Arguments:
+//
+//	path []string: The node IDs of the path, from start to end.
+//
+// Returns:
+//
+//	[]*edge.Edge: The edges of the path, step by step, or an empty slice for a
+//	              path of fewer than two nodes.
+//	error: An error if two consecutive nodes of the path are not joined by an
+//	       edge in the direction of the path.
+func (g *OpenGraph) GetPathEdges(path []string) ([]*edge.Edge, error) {
+	edges := make([]*edge.Edge, 0, max(len(path)-1, 0))
+	for i := 1; i < len(path); i++ {
+		step := g.GetEdgesByNodePair(path[i-1], path[i])
+		if len(step) == 0 {
+			return nil, fmt.Errorf("no edge from '%s' to '%s'", path[i-1], path[i])
+		}
+		edges = append(edges, step...)
+	}
+	return edges, nil
+}
+
+// GetCriticalNodes returns the nodes lying on every path from one node to
+// another, i.e. the nodes whose removal leaves endID unreachable from startID.
+//
+// The nodes are the strict dominators of endID in the dominator tree rooted at
+// startID, computed with the iterative algorithm of Cooper, Harvey and Kennedy
+// in time close to linear in the size of the graph.
+//
+// Arguments:
+//
+//	startID string: The ID of the node the paths start from.
+//	endID string: The ID of the node the paths lead to.
+//
+// Returns:
+//
+//	[]*node.Node: The critical nodes, excluding startID and endID, in the order
+//	              every path visits them, or an empty slice if there are none or
+//	              endID is not reachable from startID.
+func (g *OpenGraph) GetCriticalNodes(startID, endID string) []*node.Node {
+	critical := make([]*node.Node, 0)
+	if _, exists := g.nodes[startID]; !exists || startID == endID {
+		return critical
+	}
+
+	// Number the nodes reachable from startID in depth-first postorder.
+	adjacency := g.successors()
+	postorder := make(map[string]int)
+	var order []string
+	type frame struct {
+		id   string
+		next int
+	}
+	visited := map[string]bool{startID: true}
+	stack := []frame{{id: startID}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next < len(adjacency[top.id]) {
+			next := adjacency[top.id][top.next]
+			top.next++
+			if !visited[next] {
+				visited[next] = true
+				stack = append(stack, frame{id: next})
+			}
+			continue
+		}
+		postorder[top.id] = len(order)
+		order = append(order, top.id)
+		stack = stack[:len(stack)-1]
+	}
+	if !visited[endID] {
+		return critical
+	}
+
+	predecessors := make(map[string][]string, len(order))
+	for _, id := range order {
+		for _, next := range adjacency[id] {
+			predecessors[next] = append(predecessors[next], id)
+		}
+	}
+
+	intersect := func(idom map[string]string, a, b string) string {
+		for a != b {
+			for postorder[a] < postorder[b] {
+				a = idom[a]
+			}
+			for postorder[b] < postorder[a] {
+				b = idom[b]
+			}
+		}
+		return a
+	}
+
+	// Refine the immediate dominators in reverse postorder until stable.
+	idom := map[string]string{startID: startID}
+	for changed := true; changed; {
+		changed = false
+		for i := len(order) - 2; i >= 0; i-- {
+			id := order[i]
+			newIdom := ""
+			for _, pred := range predecessors[id] {
+				if _, processed := idom[pred]; !processed {
+					continue
+				}
+				if newIdom == "" {
+					newIdom = pred
+				} else {
+					newIdom = intersect(idom, pred, newIdom)
+				}
+			}
+			if idom[id] != newIdom {
+				idom[id] = newIdom
+				changed = true
+			}
+		}
+	}
+
+	for id := idom[endID]; id != startID; id = idom[id] {
+		critical = append(critical, g.nodes[id])
+	}
+	for i, j := 0, len(critical)-1; i < j; i, j = i+1, j-1 {
+		critical[i], critical[j] = critical[j], critical[i]
+	}
+	return critical
+}
@@ -0,0 +1,294 @@
+package gopengraph_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+	"github.com/TheManticoreProject/gopengraph/edge"
+)
+
+func TestGetPathsBetweenKinds(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "bob", []string{"User"}, nil))
+	g.AddNode(newTestNode(t, "helpdesk", []string{"Group"}, nil))
+	g.AddNode(newTestNode(t, "da", []string{"Group", "Tier0"}, nil))
+	g.AddNode(newTestNode(t, "dc", []string{"Computer", "Tier0"}, nil))
+	g.AddEdge(newTestEdge(t, "alice", "helpdesk", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "helpdesk", "da", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "bob", "dc", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "da", "dc", "AdminTo"))
+
+	t.Run("all edge kinds", func(t *testing.T) {
+		got := g.GetPathsBetweenKinds("User", "Tier0", "", 5)
+		expected := []gopengraph.Path{
+			{"alice", "helpdesk", "da"},
+			{"alice", "helpdesk", "da", "dc"},
+			{"bob", "dc"},
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("single edge kind", func(t *testing.T) {
+		got := g.GetPathsBetweenKinds("User", "Tier0", "MemberOf", 5)
+		expected := []gopengraph.Path{{"alice", "helpdesk", "da"}}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		got := g.GetPathsBetweenKinds("User", "Tier0", "", 1)
+		expected := []gopengraph.Path{{"bob", "dc"}}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("no match returns empty non-nil slice", func(t *testing.T) {
+		if got := g.GetPathsBetweenKinds("Computer", "User", "", 5); got == nil || len(got) != 0 {
+			t.Errorf("Expected empty non-nil slice, got %#v", got)
+		}
+	})
+}
+
+func TestFindPathsWithKindConstraints(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"alice", "helpdesk", "ws1", "ws2", "dc"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "alice", "helpdesk", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "helpdesk", "ws1", "HasSession"))
+	g.AddEdge(newTestEdge(t, "helpdesk", "ws2", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "ws1", "dc", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "ws2", "dc", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "alice", "dc", "AdminTo"))
+
+	tests := []struct {
+		name     string
+		maxDepth int
+		sequence []string
+		expected [][]string
+	}{
+		{"exact chain", 5, []string{"MemberOf", "HasSession", "AdminTo"}, [][]string{{"alice", "helpdesk", "ws1", "dc"}}},
+		{"wildcard step", 5, []string{"MemberOf", "", "AdminTo"}, [][]string{{"alice", "helpdesk", "ws1", "dc"}, {"alice", "helpdesk", "ws2", "dc"}}},
+		{"single edge", 5, []string{"AdminTo"}, [][]string{{"alice", "dc"}}},
+		{"broken chain", 5, []string{"MemberOf", "AdminTo"}, [][]string{}},
+		{"max depth", 2, []string{"MemberOf", "HasSession", "AdminTo"}, [][]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := g.FindPathsWithKindConstraints("alice", "dc", tt.maxDepth, tt.sequence)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if got := g.FindPathsWithKindConstraints("alice", "missing", 5, []string{""}); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty slice for a missing node, got %#v", got)
+	}
+}
+
+func TestFindPathsWithEdgeKindSequence(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"alice", "helpdesk", "ws1", "dc"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "alice", "helpdesk", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "helpdesk", "ws1", "HasSession"))
+	g.AddEdge(newTestEdge(t, "ws1", "dc", "AdminTo"))
+
+	sequence := []string{"MemberOf", "HasSession", "AdminTo"}
+	expected := [][]string{{"alice", "helpdesk", "ws1", "dc"}}
+	if got := g.FindPathsWithEdgeKindSequence("alice", "dc", sequence); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	if got := g.FindPathsWithEdgeKindSequence("alice", "dc", []string{"MemberOf", "AdminTo", "AdminTo"}); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty slice for a missing link, got %#v", got)
+	}
+
+	if got := g.FindPathsWithEdgeKindSequence("alice", "alice", nil); !reflect.DeepEqual(got, [][]string{{"alice"}}) {
+		t.Errorf("Expected the identity path for an empty sequence, got %v", got)
+	}
+	if got := g.FindPathsWithEdgeKindSequence("alice", "dc", nil); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty slice for an empty sequence between distinct nodes, got %#v", got)
+	}
+}
+
+func TestGetChainedPaths(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"alice", "helpdesk", "it", "ws1", "ws2", "dc"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "alice", "helpdesk", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "alice", "it", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "helpdesk", "ws1", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "helpdesk", "ws2", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "it", "dc", "HasSession"))
+	g.AddEdge(newTestEdge(t, "helpdesk", "alice", "GenericAll"))
+
+	tests := []struct {
+		name     string
+		chain    []string
+		expected [][]string
+	}{
+		{"two steps", []string{"MemberOf", "AdminTo"}, [][]string{{"alice", "helpdesk", "ws1"}, {"alice", "helpdesk", "ws2"}}},
+		{"wildcard step", []string{"MemberOf", ""}, [][]string{{"alice", "helpdesk", "ws1"}, {"alice", "helpdesk", "ws2"}, {"alice", "it", "dc"}}},
+		{"no revisit", []string{"MemberOf", "GenericAll"}, [][]string{}},
+		{"broken chain", []string{"AdminTo"}, [][]string{}},
+		{"empty chain", nil, [][]string{{"alice"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.GetChainedPaths("alice", tt.chain); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if got := g.GetChainedPaths("missing", []string{"MemberOf"}); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty slice for a missing node, got %#v", got)
+	}
+}
+
+func TestFindShortestPathExcluding(t *testing.T) {
+	// a -> b -> d is the shortest path, a -> c -> e -> d the detour.
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	for _, pair := range [][2]string{{"a", "b"}, {"b", "d"}, {"a", "c"}, {"c", "e"}, {"e", "d"}} {
+		g.AddEdge(newTestEdge(t, pair[0], pair[1], "Link"))
+	}
+
+	tests := []struct {
+		name      string
+		forbidden []string
+		expected  []string
+	}{
+		{"no forbidden node", nil, []string{"a", "b", "d"}},
+		{"detour", []string{"b"}, []string{"a", "c", "e", "d"}},
+		{"no path left", []string{"b", "e"}, nil},
+		{"forbidden start", []string{"a"}, nil},
+		{"forbidden end", []string{"d"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := g.FindShortestPathExcluding("a", "d", tt.forbidden)
+			if found != (tt.expected != nil) || !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v (found=%v)", tt.expected, got, found)
+			}
+		})
+	}
+
+	if got, found := g.FindShortestPathExcluding("a", "a", nil); !found || !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Expected [a] from a node to itself, got %v", got)
+	}
+	if _, found := g.FindShortestPathExcluding("a", "missing", nil); found {
+		t.Error("Expected no path to a missing node")
+	}
+}
+
+func TestFindShortestPathExcludingEdges(t *testing.T) {
+	g := gopengraph.NewOpenGraphMulti("")
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	ab := newTestEdge(t, "a", "b", "Link")
+	bd := newTestEdge(t, "b", "d", "Link")
+	g.AddEdge(ab)
+	g.AddEdge(bd)
+	g.AddEdge(newTestEdge(t, "a", "c", "Link"))
+	cd := newTestEdge(t, "c", "d", "Link")
+	g.AddEdge(cd)
+
+	if got, found := g.FindShortestPathExcludingEdges("a", "d", nil); !found || !reflect.DeepEqual(got, []string{"a", "b", "d"}) {
+		t.Errorf("Expected [a b d], got %v", got)
+	}
+	if got, found := g.FindShortestPathExcludingEdges("a", "d", []*edge.Edge{bd}); !found || !reflect.DeepEqual(got, []string{"a", "c", "d"}) {
+		t.Errorf("Expected [a c d] without b -> d, got %v", got)
+	}
+	if got, found := g.FindShortestPathExcludingEdges("a", "d", []*edge.Edge{ab, cd}); found || got != nil {
+		t.Errorf("Expected no path without a -> b and c -> d, got %v", got)
+	}
+
+	// A parallel edge is a different edge and can still be used.
+	g.AddEdge(newTestEdge(t, "b", "d", "Link"))
+	if got, found := g.FindShortestPathExcludingEdges("a", "d", []*edge.Edge{bd}); !found || !reflect.DeepEqual(got, []string{"a", "b", "d"}) {
+		t.Errorf("Expected [a b d] through the parallel edge, got %v", got)
+	}
+}
+
+func TestGetPathEdges(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"alice", "helpdesk", "dc"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "alice", "helpdesk", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "helpdesk", "dc", "AdminTo"))
+
+	path := g.FindPaths("alice", "dc", 5)[0]
+	edges, err := g.GetPathEdges(path)
+	if err != nil {
+		t.Fatalf("GetPathEdges failed: %v", err)
+	}
+	if got := edgeKeys(edges); !reflect.DeepEqual(got, []string{"alice-MemberOf-helpdesk", "helpdesk-AdminTo-dc"}) {
+		t.Errorf("Expected [alice-MemberOf-helpdesk helpdesk-AdminTo-dc], got %v", got)
+	}
+
+	// Every edge of a step is returned.
+	g.AddEdge(newTestEdge(t, "helpdesk", "dc", "CanRDP"))
+	edges, _ = g.GetPathEdges(gopengraph.Path{"helpdesk", "dc"})
+	if got := edgeKeys(edges); !reflect.DeepEqual(got, []string{"helpdesk-AdminTo-dc", "helpdesk-CanRDP-dc"}) {
+		t.Errorf("Expected both edges of the step, got %v", got)
+	}
+
+	if _, err := g.GetPathEdges([]string{"dc", "helpdesk"}); err == nil {
+		t.Error("Expected an error for a step against the edge direction")
+	}
+	if _, err := g.GetPathEdges([]string{"alice", "missing"}); err == nil {
+		t.Error("Expected an error for a missing node")
+	}
+	if edges, err := g.GetPathEdges([]string{"alice"}); err != nil || edges == nil || len(edges) != 0 {
+		t.Errorf("Expected no edge for a single-node path, got %v (err=%v)", edges, err)
+	}
+}
+
+func TestGetCriticalNodes(t *testing.T) {
+	// a -> b -> c -> e and b -> d -> e, then e -> f: every path from a to f
+	// goes through b and e, but not through c or d.
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "x"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	for _, pair := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "e"}, {"b", "d"}, {"d", "e"}, {"e", "f"}, {"f", "b"}} {
+		g.AddEdge(newTestEdge(t, pair[0], pair[1], "Link"))
+	}
+
+	if got := nodeIDs(g.GetCriticalNodes("a", "f")); !reflect.DeepEqual(got, []string{"b", "e"}) {
+		t.Errorf("Expected [b e], got %v", got)
+	}
+	if got := nodeIDs(g.GetCriticalNodes("a", "c")); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("Expected [b], got %v", got)
+	}
+	if got := nodeIDs(g.GetCriticalNodes("b", "e")); len(got) != 0 {
+		t.Errorf("Expected no critical node between b and e, got %v", got)
+	}
+
+	// Adding a bypass of b removes it from the critical nodes.
+	g.AddEdge(newTestEdge(t, "a", "d", "Link"))
+	if got := nodeIDs(g.GetCriticalNodes("a", "f")); !reflect.DeepEqual(got, []string{"e"}) {
+		t.Errorf("Expected [e] after adding a -> d, got %v", got)
+	}
+
+	for _, pair := range [][2]string{{"a", "x"}, {"missing", "f"}, {"a", "a"}} {
+		if got := g.GetCriticalNodes(pair[0], pair[1]); got == nil || len(got) != 0 {
+			t.Errorf("Expected an empty slice from %s to %s, got %#v", pair[0], pair[1], got)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package gopengraph
+
+import (
+	"sort"
+
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+// propertyIndex maps the values of a property key, as returned by
+// propertyValueKey, to the nodes holding them, keyed by node ID.
+type propertyIndex map[string]map[string]*node.Node
+
+// Property indexes
+
+// BuildPropertyIndex builds an index of the nodes by the values of a property
+// key, so that GetNodesByPropertyValue no longer scans every node for that key.
+//
+// The index is kept up to date when nodes are added or removed through the
+// graph and when their properties are changed with UpdateNodeProperties.
+// Properties changed directly on a node (e.g. with node.SetProperty) are not
+// tracked: call BuildPropertyIndex again after such changes. Building an index
+// that already exists rebuilds it.
+//
+// Arguments:
+//
+//	key string: The property key to index.
+func (g *OpenGraph) BuildPropertyIndex(key string) {
+	if g.propertyIndexes == nil {
+		g.propertyIndexes = make(map[string]propertyIndex)
+	}
+
+	index := make(propertyIndex)
+	g.propertyIndexes[key] = index
+	for _, n := range g.nodes {
+		index.add(key, n)
+	}
+}
+
+// InvalidatePropertyIndex removes the index of a property key built by
+// BuildPropertyIndex. GetNodesByPropertyValue falls back to a linear scan for
+// that key afterwards.
+//
+// Arguments:
+//
+//	key string: The property key whose index is removed.
+func (g *OpenGraph) InvalidatePropertyIndex(key string) {
+	delete(g.propertyIndexes, key)
+}
+
+// GetNodesByPropertyValue returns all nodes whose property key equals value.
+//
+// Values are compared as in FilterNodesByProperty. The lookup uses the index
+// built by BuildPropertyIndex for key when there is one, and scans all nodes
+// otherwise.
+//
+// Arguments:
+//
+//	key string: The property key to compare.
+//	value interface{}: The value the property must hold.
+//
+// Returns:
+//
+//	[]*node.Node: The matching nodes, in ID order, or an empty slice if none match.
+func (g *OpenGraph) GetNodesByPropertyValue(key string, value interface{}) []*node.Node {
+	index, indexed := g.propertyIndexes[key]
+	if !indexed {
+		nodes := g.FilterNodesByProperty(key, value)
+		sort.Slice(nodes, func(i, j int) bool {
+			return nodes[i].GetID() < nodes[j].GetID()
+		})
+		return nodes
+	}
+
+	matches := index[propertyValueKey(value)]
+	nodes := make([]*node.Node, 0, len(matches))
+	for _, n := range matches {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].GetID() < nodes[j].GetID()
+	})
+	return nodes
+}
+
+// indexNode adds n to every property index of the graph.
+func (g *OpenGraph) indexNode(n *node.Node) {
+	for key, index := range g.propertyIndexes {
+		index.add(key, n)
+	}
+}
+
+// unindexNode removes n from every property index of the graph.
+func (g *OpenGraph) unindexNode(n *node.Node) {
+	for key, index := range g.propertyIndexes {
+		index.remove(key, n)
+	}
+}
+
+// add indexes n under its value for key, if it has one.
+func (index propertyIndex) add(key string, n *node.Node) {
+	if !n.GetProperties().HasProperty(key) {
+		return
+	}
+	valueKey := propertyValueKey(n.GetProperty(key))
+	if index[valueKey] == nil {
+		index[valueKey] = make(map[string]*node.Node)
+	}
+	index[valueKey][n.GetID()] = n
+}
+
+// remove removes n from the index of key.
+func (index propertyIndex) remove(key string, n *node.Node) {
+	if !n.GetProperties().HasProperty(key) {
+		return
+	}
+	valueKey := propertyValueKey(n.GetProperty(key))
+	delete(index[valueKey], n.GetID())
+	if len(index[valueKey]) == 0 {
+		delete(index, valueKey)
+	}
+}
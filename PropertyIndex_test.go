@@ -0,0 +1,96 @@
+package gopengraph_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+)
+
+func TestGetNodesByPropertyValue(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("indexed=%v", indexed), func(t *testing.T) {
+			g := newTestGraph(t)
+			if indexed {
+				g.BuildPropertyIndex("logons")
+			}
+
+			if got := nodeIDs(g.GetNodesByPropertyValue("logons", 3)); !reflect.DeepEqual(got, []string{"admins", "alice"}) {
+				t.Errorf("Expected [admins alice], got %v", got)
+			}
+			if got := g.GetNodesByPropertyValue("logons", 3.0); len(got) != 0 {
+				t.Errorf("Expected a float64 value not to match int properties, got %v", nodeIDs(got))
+			}
+
+			// The index follows additions, removals and property updates.
+			g.AddNode(newTestNode(t, "carol", []string{"User"}, map[string]interface{}{"logons": 3}))
+			g.RemoveNodeByID("admins")
+			if err := g.UpdateNodeProperties("alice", map[string]interface{}{"logons": 7}); err != nil {
+				t.Fatalf("UpdateNodeProperties failed: %v", err)
+			}
+			if got := nodeIDs(g.GetNodesByPropertyValue("logons", 3)); !reflect.DeepEqual(got, []string{"carol"}) {
+				t.Errorf("Expected [carol], got %v", got)
+			}
+			if got := nodeIDs(g.GetNodesByPropertyValue("logons", 7)); !reflect.DeepEqual(got, []string{"alice", "bob"}) {
+				t.Errorf("Expected [alice bob], got %v", got)
+			}
+
+			g.Clear()
+			if got := g.GetNodesByPropertyValue("logons", 7); got == nil || len(got) != 0 {
+				t.Errorf("Expected an empty non-nil slice after Clear, got %#v", got)
+			}
+		})
+	}
+}
+
+func TestInvalidatePropertyIndex(t *testing.T) {
+	g := newTestGraph(t)
+	g.BuildPropertyIndex("name")
+
+	// Direct property changes are not tracked by the index...
+	g.GetNode("bob").SetProperty("name", "ROBERT")
+	if got := nodeIDs(g.GetNodesByPropertyValue("name", "BOB")); !reflect.DeepEqual(got, []string{"bob"}) {
+		t.Errorf("Expected the stale index to still return [bob], got %v", got)
+	}
+
+	// ...until the index is removed or rebuilt.
+	g.InvalidatePropertyIndex("name")
+	if got := g.GetNodesByPropertyValue("name", "BOB"); len(got) != 0 {
+		t.Errorf("Expected no node after invalidating the index, got %v", nodeIDs(got))
+	}
+	g.BuildPropertyIndex("name")
+	if got := nodeIDs(g.GetNodesByPropertyValue("name", "ROBERT")); !reflect.DeepEqual(got, []string{"bob"}) {
+		t.Errorf("Expected [bob] after rebuilding the index, got %v", got)
+	}
+}
+
+// newPropertyIndexBenchmarkGraph builds a graph of 100 000 nodes with a unique
+// "objectid" property.
+func newPropertyIndexBenchmarkGraph(b *testing.B) *gopengraph.OpenGraph {
+	b.Helper()
+	g := gopengraph.NewOpenGraph("")
+	for i := 0; i < 100000; i++ {
+		g.AddNode(newTestNode(b, fmt.Sprintf("n%d", i), nil, map[string]interface{}{
+			"objectid": fmt.Sprintf("S-1-5-21-%d", i),
+		}))
+	}
+	return g
+}
+
+func BenchmarkGetNodesByPropertyValueScan(b *testing.B) {
+	g := newPropertyIndexBenchmarkGraph(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.GetNodesByPropertyValue("objectid", "S-1-5-21-50000")
+	}
+}
+
+func BenchmarkGetNodesByPropertyValueIndexed(b *testing.B) {
+	g := newPropertyIndexBenchmarkGraph(b)
+	g.BuildPropertyIndex("objectid")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.GetNodesByPropertyValue("objectid", "S-1-5-21-50000")
+	}
+}
@@ -0,0 +1,350 @@
+package gopengraph
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+	"github.com/TheManticoreProject/gopengraph/properties"
+)
+
+// Graph queries
+
+// FilterNodesByProperty returns all nodes whose property key equals value.
+//
+// Values are compared with reflect.DeepEqual, so an int property does not match
+// a float64 value of the same magnitude.
+//
+// Arguments:
+//
+//	key string: The property key to compare.
+//	value interface{}: The value the property must hold.
+//
+// Returns:
+//
+//	[]*node.Node: The matching nodes, or an empty slice if none match.
+func (g *OpenGraph) FilterNodesByProperty(key string, value interface{}) []*node.Node {
+	return g.FilterNodesByPredicate(func(n *node.Node) bool {
+		return n.GetProperties().HasProperty(key) && reflect.DeepEqual(n.GetProperty(key), value)
+	})
+}
+
+// FilterEdgesByProperty returns all edges whose property key equals value.
+//
+// Values are compared with reflect.DeepEqual.
+//
+// Arguments:
+//
+//	key string: The property key to compare.
+//	value interface{}: The value the property must hold.
+//
+// Returns:
+//
+//	[]*edge.Edge: The matching edges, or an empty slice if none match.
+func (g *OpenGraph) FilterEdgesByProperty(key string, value interface{}) []*edge.Edge {
+	return g.FilterEdgesByPredicate(func(e *edge.Edge) bool {
+		return e.GetProperties().HasProperty(key) && reflect.DeepEqual(e.GetProperty(key), value)
+	})
+}
+
+// GetNodesByKindAndProperty returns all nodes having the given kind and whose
+// property key equals value, in a single pass over the nodes.
+//
+// Values are compared with reflect.DeepEqual, as in FilterNodesByProperty.
+//
+// Arguments:
+//
+//	kind string: The kind the nodes must have.
+//	key string: The property key to compare.
+//	value interface{}: The value the property must hold.
+//
+// Returns:
+//
+//	[]*node.Node: The matching nodes, in ID order, or an empty slice if none match.
+func (g *OpenGraph) GetNodesByKindAndProperty(kind, key string, value interface{}) []*node.Node {
+	nodes := g.FilterNodesByPredicate(func(n *node.Node) bool {
+		return n.HasKind(kind) && n.GetProperties().HasProperty(key) && reflect.DeepEqual(n.GetProperty(key), value)
+	})
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].GetID() < nodes[j].GetID()
+	})
+	return nodes
+}
+
+// GetNodesByProperties returns all nodes having every property of criteria
+// with the same value, e.g. {"enabled": true, "domain": "CORP.LOCAL"}.
+//
+// Values are compared with reflect.DeepEqual, as in FilterNodesByProperty.
+//
+// Arguments:
+//
+//	criteria map[string]interface{}: The values the properties must hold, keyed
+//	                                 by property key. An empty map matches every
+//	                                 node.
+//
+// Returns:
+//
+//	[]*node.Node: The matching nodes, in ID order, or an empty slice if none match.
+func (g *OpenGraph) GetNodesByProperties(criteria map[string]interface{}) []*node.Node {
+	nodes := g.FilterNodesByPredicate(func(n *node.Node) bool {
+		return propertiesMatch(n.GetProperties(), criteria)
+	})
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].GetID() < nodes[j].GetID()
+	})
+	return nodes
+}
+
+// GetEdgesByProperties returns all edges having every property of criteria
+// with the same value.
+//
+// Values are compared with reflect.DeepEqual, as in FilterEdgesByProperty.
+//
+// Arguments:
+//
+//	criteria map[string]interface{}: The values the properties must hold, keyed
+//	                                 by property key. An empty map matches every
+//	                                 edge.
+//
+// Returns:
+//
+//	[]*edge.Edge: The matching edges, in insertion order, or an empty slice if
+//	              none match.
+func (g *OpenGraph) GetEdgesByProperties(criteria map[string]interface{}) []*edge.Edge {
+	return g.FilterEdgesByPredicate(func(e *edge.Edge) bool {
+		return propertiesMatch(e.GetProperties(), criteria)
+	})
+}
+
+// GetNodesByCommunity returns the nodes assigned to a community by a
+// clustering, such as the output of a community detection algorithm.
+//
+// Arguments:
+//
+//	community int: The community of the nodes to return.
+//	clusters map[string]int: The community of each node, keyed by node ID.
+//	                         IDs that are not nodes of the graph are ignored.
+//
+// Returns:
+//
+//	[]*node.Node: The nodes of the community, in ID order, or an empty slice if
+//	              there are none.
+func (g *OpenGraph) GetNodesByCommunity(community int, clusters map[string]int) []*node.Node {
+	nodes := make([]*node.Node, 0)
+	for id, c := range clusters {
+		if n, exists := g.nodes[id]; exists && c == community {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].GetID() < nodes[j].GetID()
+	})
+	return nodes
+}
+
+// FindNodesWithProperty returns all nodes having every one of the given
+// property keys, whatever their values, e.g.
+// g.FindNodesWithProperty("email", "manager").
+//
+// Arguments:
+//
+//	keys ...string: The property keys the nodes must all have. No keys
+//	                matches every node.
+//
+// Returns:
+//
+//	[]*node.Node: The matching nodes, in ID order, or an empty slice if none match.
+func (g *OpenGraph) FindNodesWithProperty(keys ...string) []*node.Node {
+	nodes := g.FilterNodesByPredicate(func(n *node.Node) bool {
+		for _, key := range keys {
+			if !n.GetProperties().HasProperty(key) {
+				return false
+			}
+		}
+		return true
+	})
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].GetID() < nodes[j].GetID()
+	})
+	return nodes
+}
+
+// FindNodesWithPropertyRegex returns all nodes whose string property key
+// matches a regular expression, e.g. `(?i)^admin` for names starting with
+// "admin" in any case.
+//
+// The pattern matches anywhere in the value unless anchored. Nodes whose value
+// for key is not a string are never returned.
+//
+// Arguments:
+//
+//	key string: The property key to match.
+//	pattern string: The regular expression, in the syntax of package regexp.
+//
+// Returns:
+//
+//	[]*node.Node: The matching nodes, in ID order, or an empty slice if none match.
+//	error: An error if pattern is not a valid regular expression.
+func (g *OpenGraph) FindNodesWithPropertyRegex(key string, pattern string) ([]*node.Node, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	nodes := g.FilterNodesByPredicate(func(n *node.Node) bool {
+		return stringPropertyMatches(n.GetProperties(), key, re)
+	})
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].GetID() < nodes[j].GetID()
+	})
+	return nodes, nil
+}
+
+// FindEdgesWithPropertyRegex returns all edges whose string property key
+// matches a regular expression, as FindNodesWithPropertyRegex does for nodes.
+//
+// Arguments:
+//
+//	key string: The property key to match.
+//	pattern string: The regular expression, in the syntax of package regexp.
+//
+// Returns:
+//
+//	[]*edge.Edge: The matching edges, in insertion order, or an empty slice if
+//	              none match.
+//	error: An error if pattern is not a valid regular expression.
+func (g *OpenGraph) FindEdgesWithPropertyRegex(key string, pattern string) ([]*edge.Edge, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	return g.FilterEdgesByPredicate(func(e *edge.Edge) bool {
+		return stringPropertyMatches(e.GetProperties(), key, re)
+	}), nil
+}
+
+// FilterNodesByPredicate returns all nodes for which pred returns true.
+//
+// The returned slice is a snapshot: reordering or truncating it does not affect
+// the graph.
+//
+// Arguments:
+//
+//	pred func(*node.Node) bool: The predicate a node must satisfy.
+//
+// Returns:
+//
+//	[]*node.Node: The matching nodes, or an empty slice if none match.
+func (g *OpenGraph) FilterNodesByPredicate(pred func(*node.Node) bool) []*node.Node {
+	nodes := make([]*node.Node, 0)
+	for _, n := range g.nodes {
+		if pred(n) {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// FilterEdgesByPredicate returns all edges for which pred returns true, in
+// insertion order.
+//
+// The returned slice is a snapshot: reordering or truncating it does not affect
+// the graph.
+//
+// Arguments:
+//
+//	pred func(*edge.Edge) bool: The predicate an edge must satisfy.
+//
+// Returns:
+//
+//	[]*edge.Edge: The matching edges, or an empty slice if none match.
+func (g *OpenGraph) FilterEdgesByPredicate(pred func(*edge.Edge) bool) []*edge.Edge {
+	edges := make([]*edge.Edge, 0)
+	for _, e := range g.edges {
+		if pred(e) {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// GetEdgesWithBothEndpointsOf returns the edges whose start and end nodes both
+// have the given kind, e.g. Group-to-Group relationships for kind "Group".
+//
+// Edges whose endpoints are not nodes of the graph are never returned.
+//
+// Arguments:
+//
+//	kind string: The kind both endpoints must have.
+//
+// Returns:
+//
+//	[]*edge.Edge: The matching edges, or an empty slice if none match.
+func (g *OpenGraph) GetEdgesWithBothEndpointsOf(kind string) []*edge.Edge {
+	return g.FilterEdgesByPredicate(func(e *edge.Edge) bool {
+		start, startExists := g.nodes[e.GetStartNodeID()]
+		end, endExists := g.nodes[e.GetEndNodeID()]
+		return startExists && endExists && start.HasKind(kind) && end.HasKind(kind)
+	})
+}
+
+// Provenance
+
+// GetNodesModifiedBy returns the nodes whose propertyKey property is the
+// string actor, in ID order.
+//
+// Arguments:
+//
+//	actor string: The actor to look for.
+//	propertyKey string: The property holding the actor, e.g. "modified_by".
+//
+// Returns:
+//
+//	[]*node.Node: The matching nodes, or an empty slice if none match.
+func (g *OpenGraph) GetNodesModifiedBy(actor string, propertyKey string) []*node.Node {
+	nodes := g.FilterNodesByProperty(propertyKey, actor)
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].GetID() < nodes[j].GetID()
+	})
+	return nodes
+}
+
+// GetEdgesModifiedBy returns the edges whose propertyKey property is the
+// string actor, in insertion order.
+//
+// Paired with the convention of recording the author of a change in a property
+// such as "modified_by", this gives a basic audit trail of the graph.
+//
+// Arguments:
+//
+//	actor string: The actor to look for.
+//	propertyKey string: The property holding the actor, e.g. "modified_by".
+//
+// Returns:
+//
+//	[]*edge.Edge: The matching edges, or an empty slice if none match.
+func (g *OpenGraph) GetEdgesModifiedBy(actor string, propertyKey string) []*edge.Edge {
+	return g.FilterEdgesByProperty(propertyKey, actor)
+}
+
+// stringPropertyMatches reports whether the property key of p is a string
+// matched by re.
+func stringPropertyMatches(p *properties.Properties, key string, re *regexp.Regexp) bool {
+	value, ok := p.GetProperty(key).(string)
+	return ok && re.MatchString(value)
+}
+
+// propertiesMatch reports whether p has every key of criteria with a value
+// deeply equal to the expected one.
+func propertiesMatch(p *properties.Properties, criteria map[string]interface{}) bool {
+	for key, value := range criteria {
+		if !p.HasProperty(key) || !reflect.DeepEqual(p.GetProperty(key), value) {
+			return false
+		}
+	}
+	return true
+}
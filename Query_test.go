@@ -0,0 +1,286 @@
+package gopengraph_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+func TestFilterNodesByProperty(t *testing.T) {
+	g := newTestGraph(t)
+
+	t.Run("string property", func(t *testing.T) {
+		nodes := g.FilterNodesByProperty("name", "BOB")
+		if len(nodes) != 1 || nodes[0].GetID() != "bob" {
+			t.Errorf("Expected only bob, got %v", nodes)
+		}
+	})
+
+	t.Run("integer property", func(t *testing.T) {
+		nodes := g.FilterNodesByProperty("logons", 3)
+		if len(nodes) != 2 {
+			t.Errorf("Expected 2 nodes, got %d", len(nodes))
+		}
+	})
+
+	t.Run("no match returns empty non-nil slice", func(t *testing.T) {
+		nodes := g.FilterNodesByProperty("logons", 3.0)
+		if nodes == nil || len(nodes) != 0 {
+			t.Errorf("Expected empty non-nil slice, got %#v", nodes)
+		}
+	})
+}
+
+func TestFilterEdgesByProperty(t *testing.T) {
+	g := newTestGraph(t)
+
+	edges := g.FilterEdgesByProperty("source", "ldap")
+	if len(edges) != 1 || edges[0].GetStartNodeID() != "alice" {
+		t.Errorf("Expected the alice edge, got %v", edges)
+	}
+
+	edges = g.FilterEdgesByProperty("source", "kerberos")
+	if edges == nil || len(edges) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %#v", edges)
+	}
+}
+
+func TestGetNodesByKindAndProperty(t *testing.T) {
+	g := newTestGraph(t)
+	g.AddNode(newTestNode(t, "carol", []string{"User"}, map[string]interface{}{"name": "CAROL", "logons": 3}))
+
+	nodes := g.GetNodesByKindAndProperty("User", "logons", 3)
+	if len(nodes) != 2 || nodes[0].GetID() != "alice" || nodes[1].GetID() != "carol" {
+		t.Errorf("Expected [alice carol], got %v", nodes)
+	}
+	if nodes := g.GetNodesByKindAndProperty("Computer", "logons", 3); nodes == nil || len(nodes) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %#v", nodes)
+	}
+}
+
+func TestGetNodesAndEdgesByProperties(t *testing.T) {
+	g := newTestGraph(t)
+	g.AddNode(newTestNode(t, "carol", []string{"User"}, map[string]interface{}{"name": "CAROL", "logons": 3}))
+
+	tests := []struct {
+		name     string
+		criteria map[string]interface{}
+		expected []string
+	}{
+		{"single property", map[string]interface{}{"logons": 3}, []string{"admins", "alice", "carol"}},
+		{"intersection", map[string]interface{}{"logons": 3, "name": "CAROL"}, []string{"carol"}},
+		{"disjoint values", map[string]interface{}{"logons": 7, "name": "CAROL"}, []string{}},
+		{"missing property", map[string]interface{}{"logons": 3, "email": nil}, []string{}},
+		{"empty criteria", map[string]interface{}{}, []string{"admins", "alice", "bob", "carol"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nodeIDs(g.GetNodesByProperties(tt.criteria)); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	// Combined with a kind filter, the criteria select the same nodes as
+	// GetNodesByKindAndProperty.
+	users := make([]string, 0)
+	for _, n := range g.GetNodesByProperties(map[string]interface{}{"logons": 3}) {
+		if n.HasKind("User") {
+			users = append(users, n.GetID())
+		}
+	}
+	if expected := nodeIDs(g.GetNodesByKindAndProperty("User", "logons", 3)); !reflect.DeepEqual(users, expected) {
+		t.Errorf("Expected %v, got %v", expected, users)
+	}
+
+	if got := edgeKeys(g.GetEdgesByProperties(map[string]interface{}{"source": "smb"})); !reflect.DeepEqual(got, []string{"bob-MemberOf-admins"}) {
+		t.Errorf("Expected [bob-MemberOf-admins], got %v", got)
+	}
+	if got := g.GetEdgesByProperties(nil); len(got) != 2 {
+		t.Errorf("Expected every edge for nil criteria, got %d", len(got))
+	}
+	if got := g.GetEdgesByProperties(map[string]interface{}{"source": "kerberos"}); got == nil || len(got) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %#v", got)
+	}
+}
+
+func TestGetNodesByCommunity(t *testing.T) {
+	g := newTestGraph(t)
+	clusters := map[string]int{"alice": 1, "bob": 2, "admins": 1, "ghost": 1}
+
+	if got := nodeIDs(g.GetNodesByCommunity(1, clusters)); !reflect.DeepEqual(got, []string{"admins", "alice"}) {
+		t.Errorf("Expected [admins alice], got %v", got)
+	}
+	if got := g.GetNodesByCommunity(3, clusters); got == nil || len(got) != 0 {
+		t.Errorf("Expected an empty slice for an unknown community, got %#v", got)
+	}
+}
+
+func TestFindNodesWithProperty(t *testing.T) {
+	g := newTestGraph(t)
+	g.GetNode("bob").SetProperty("email", "bob@corp.local")
+	g.GetNode("admins").SetProperty("email", "admins@corp.local")
+	g.GetNode("admins").SetProperty("manager", "alice")
+
+	tests := []struct {
+		keys []string
+		want []string
+	}{
+		{[]string{"email"}, []string{"admins", "bob"}},
+		{[]string{"email", "manager"}, []string{"admins"}},
+		{[]string{"email", "missing"}, []string{}},
+		{nil, []string{"admins", "alice", "bob"}},
+	}
+	for _, tt := range tests {
+		if got := nodeIDs(g.FindNodesWithProperty(tt.keys...)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Expected %v for keys %v, got %v", tt.want, tt.keys, got)
+		}
+	}
+}
+
+func TestFindNodesWithPropertyRegex(t *testing.T) {
+	g := newTestGraph(t)
+
+	nodes, err := g.FindNodesWithPropertyRegex("name", "^(?i)a")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := nodeIDs(nodes); !reflect.DeepEqual(got, []string{"admins", "alice"}) {
+		t.Errorf("Expected [admins alice], got %v", got)
+	}
+
+	// Non-string values never match.
+	nodes, err = g.FindNodesWithPropertyRegex("logons", "3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(nodes) != 0 {
+		t.Errorf("Expected no node for a numeric property, got %v", nodeIDs(nodes))
+	}
+
+	if _, err := g.FindNodesWithPropertyRegex("name", "(unclosed"); err == nil {
+		t.Error("Expected error for an invalid pattern, got nil")
+	}
+}
+
+func TestFindEdgesWithPropertyRegex(t *testing.T) {
+	g := newTestGraph(t)
+
+	edges, err := g.FindEdgesWithPropertyRegex("source", "^(ldap|smb)$")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := edgeKeys(edges); !reflect.DeepEqual(got, []string{"alice-MemberOf-admins", "bob-MemberOf-admins"}) {
+		t.Errorf("Expected both MemberOf edges, got %v", got)
+	}
+
+	edges, err = g.FindEdgesWithPropertyRegex("source", "da")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := edgeKeys(edges); !reflect.DeepEqual(got, []string{"alice-MemberOf-admins"}) {
+		t.Errorf("Expected [alice-MemberOf-admins], got %v", got)
+	}
+
+	if _, err := g.FindEdgesWithPropertyRegex("source", "[a-"); err == nil {
+		t.Error("Expected error for an invalid pattern, got nil")
+	}
+}
+
+func TestFilterByPredicate(t *testing.T) {
+	g := newTestGraph(t)
+
+	t.Run("nodes by kind membership", func(t *testing.T) {
+		users := g.FilterNodesByPredicate(func(n *node.Node) bool { return n.HasKind("User") })
+		if len(users) != 2 {
+			t.Errorf("Expected 2 users, got %d", len(users))
+		}
+	})
+
+	t.Run("edges by endpoint", func(t *testing.T) {
+		edges := g.FilterEdgesByPredicate(func(e *edge.Edge) bool { return e.GetEndNodeID() == "admins" })
+		if len(edges) != 2 {
+			t.Errorf("Expected 2 edges, got %d", len(edges))
+		}
+	})
+
+	t.Run("no match returns empty non-nil slice", func(t *testing.T) {
+		nodes := g.FilterNodesByPredicate(func(n *node.Node) bool { return false })
+		if nodes == nil || len(nodes) != 0 {
+			t.Errorf("Expected empty non-nil slice, got %#v", nodes)
+		}
+		edges := g.FilterEdgesByPredicate(func(e *edge.Edge) bool { return false })
+		if edges == nil || len(edges) != 0 {
+			t.Errorf("Expected empty non-nil slice, got %#v", edges)
+		}
+	})
+
+	t.Run("result is a snapshot", func(t *testing.T) {
+		edges := g.FilterEdgesByPredicate(func(e *edge.Edge) bool { return true })
+		edges[0] = nil
+		edges = edges[:0]
+		if g.GetEdgeCount() != 2 || len(g.GetEdgesByKind("MemberOf")) != 2 {
+			t.Error("Expected mutating the result not to affect the graph")
+		}
+
+		nodes := g.FilterNodesByPredicate(func(n *node.Node) bool { return true })
+		nodes[0] = nil
+		if g.GetNodeCount() != 3 || g.GetNode("alice") == nil {
+			t.Error("Expected mutating the result not to affect the graph")
+		}
+	})
+}
+
+func TestGetEdgesWithBothEndpointsOf(t *testing.T) {
+	g := newTestGraph(t)
+	g.AddNode(newTestNode(t, "operators", []string{"Group"}, nil))
+	g.AddEdge(newTestEdge(t, "operators", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "alice", "bob", "Knows"))
+	g.AddEdgeWithoutValidation(newTestEdge(t, "admins", "missing", "MemberOf"))
+
+	groups := g.GetEdgesWithBothEndpointsOf("Group")
+	if len(groups) != 1 || groups[0].GetStartNodeID() != "operators" || groups[0].GetEndNodeID() != "admins" {
+		t.Errorf("Expected the operators -> admins edge, got %v", groups)
+	}
+
+	users := g.GetEdgesWithBothEndpointsOf("User")
+	if len(users) != 1 || users[0].GetKind() != "Knows" {
+		t.Errorf("Expected the alice -> bob edge, got %v", users)
+	}
+
+	if computers := g.GetEdgesWithBothEndpointsOf("Computer"); computers == nil || len(computers) != 0 {
+		t.Errorf("Expected an empty non-nil slice, got %#v", computers)
+	}
+}
+
+func TestGetEdgesModifiedBy(t *testing.T) {
+	g := newTestGraph(t)
+	g.GetEdgesFromNode("alice")[0].SetProperty("modified_by", "collector")
+	g.GetEdgesFromNode("bob")[0].SetProperty("modified_by", "analyst")
+	g.AddEdge(newTestEdge(t, "alice", "bob", "AdminTo"))
+
+	got := g.GetEdgesModifiedBy("analyst", "modified_by")
+	if len(got) != 1 || got[0].GetStartNodeID() != "bob" {
+		t.Errorf("Expected the bob edge, got %v", got)
+	}
+	if got := g.GetEdgesModifiedBy("analyst", "created_by"); got == nil || len(got) != 0 {
+		t.Errorf("Expected empty non-nil slice for an unknown key, got %#v", got)
+	}
+}
+
+func TestGetNodesModifiedBy(t *testing.T) {
+	g := newTestGraph(t)
+	g.GetNode("bob").SetProperty("modified_by", "analyst")
+	g.GetNode("alice").SetProperty("modified_by", "analyst")
+	g.GetNode("admins").SetProperty("modified_by", "collector")
+
+	got := g.GetNodesModifiedBy("analyst", "modified_by")
+	if len(got) != 2 || got[0].GetID() != "alice" || got[1].GetID() != "bob" {
+		t.Errorf("Expected [alice bob], got %v", got)
+	}
+	if got := g.GetNodesModifiedBy("nobody", "modified_by"); got == nil || len(got) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %#v", got)
+	}
+}
@@ -0,0 +1,203 @@
+package gopengraph
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+// svgLayoutIterations is the number of force-directed layout iterations run by
+// VisualizeToSVG.
+const svgLayoutIterations = 50
+
+// SVGOptions controls the rendering of VisualizeToSVG. Zero fields take the
+// values of DefaultSVGOptions.
+type SVGOptions struct {
+	// Width and Height are the dimensions of the canvas, in pixels.
+	Width  float64
+	Height float64
+	// NodeRadius is the radius of the node circles, in pixels.
+	NodeRadius float64
+	// FontSize is the size of the node and edge labels, in pixels.
+	FontSize float64
+}
+
+// DefaultSVGOptions returns the options used for the zero fields of SVGOptions.
+func DefaultSVGOptions() SVGOptions {
+	return SVGOptions{
+		Width:      800,
+		Height:     600,
+		NodeRadius: 20,
+		FontSize:   12,
+	}
+}
+
+// Visualization
+
+// VisualizeToSVG renders the graph as a standalone SVG document.
+//
+// Nodes are drawn as circles labeled with their display name: the "displayname"
+// property, else the "name" property, else the node ID. Edges are drawn as
+// lines between their endpoints, labeled with their kind; edges whose endpoints
+// are not both nodes of the graph are not drawn. Node positions are computed
+// with a simple force-directed layout, starting from a circle in node ID order,
+// so the output is deterministic. The rendering is meant for small graphs.
+//
+// Arguments:
+//
+//	w io.Writer: The writer to write the SVG document to.
+//	opts SVGOptions: The canvas size, node radius and font size.
+//
+// Returns:
+//
+//	error: An error if the document could not be written.
+func (g *OpenGraph) VisualizeToSVG(w io.Writer, opts SVGOptions) error {
+	defaults := DefaultSVGOptions()
+	if opts.Width <= 0 {
+		opts.Width = defaults.Width
+	}
+	if opts.Height <= 0 {
+		opts.Height = defaults.Height
+	}
+	if opts.NodeRadius <= 0 {
+		opts.NodeRadius = defaults.NodeRadius
+	}
+	if opts.FontSize <= 0 {
+		opts.FontSize = defaults.FontSize
+	}
+
+	ids := g.sortedNodeIDs()
+	positions := g.svgLayout(ids, opts)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%g\" height=\"%g\" viewBox=\"0 0 %g %g\">\n",
+		opts.Width, opts.Height, opts.Width, opts.Height)
+	fmt.Fprintf(&b, "  <g font-family=\"sans-serif\" font-size=\"%g\" text-anchor=\"middle\">\n", opts.FontSize)
+
+	for _, e := range g.edges {
+		start, startExists := positions[e.GetStartNodeID()]
+		end, endExists := positions[e.GetEndNodeID()]
+		if !startExists || !endExists {
+			continue
+		}
+		fmt.Fprintf(&b, "    <line x1=\"%.2f\" y1=\"%.2f\" x2=\"%.2f\" y2=\"%.2f\" stroke=\"#999999\"/>\n",
+			start[0], start[1], end[0], end[1])
+		fmt.Fprintf(&b, "    <text x=\"%.2f\" y=\"%.2f\" fill=\"#666666\">%s</text>\n",
+			(start[0]+end[0])/2, (start[1]+end[1])/2, html.EscapeString(e.GetKind()))
+	}
+
+	for _, id := range ids {
+		p := positions[id]
+		fmt.Fprintf(&b, "    <circle cx=\"%.2f\" cy=\"%.2f\" r=\"%g\" fill=\"#4f81bd\" stroke=\"#2f4f7f\"/>\n",
+			p[0], p[1], opts.NodeRadius)
+		fmt.Fprintf(&b, "    <text x=\"%.2f\" y=\"%.2f\">%s</text>\n",
+			p[0], p[1]+opts.NodeRadius+opts.FontSize, html.EscapeString(svgDisplayName(g.nodes[id])))
+	}
+
+	b.WriteString("  </g>\n</svg>\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write SVG: %w", err)
+	}
+	return nil
+}
+
+// svgLayout places the nodes ids on the canvas with a Fruchterman-Reingold
+// force-directed layout: all nodes repel each other, edges pull their
+// endpoints together, and the displacement is bounded by a cooling temperature.
+func (g *OpenGraph) svgLayout(ids []string, opts SVGOptions) map[string][2]float64 {
+	positions := make(map[string][2]float64, len(ids))
+	if len(ids) == 0 {
+		return positions
+	}
+
+	margin := opts.NodeRadius + opts.FontSize
+	minX, maxX := margin, math.Max(margin, opts.Width-margin)
+	minY, maxY := margin, math.Max(margin, opts.Height-margin)
+	centerX, centerY := opts.Width/2, opts.Height/2
+
+	radius := math.Min(maxX-minX, maxY-minY) / 2
+	for i, id := range ids {
+		angle := 2 * math.Pi * float64(i) / float64(len(ids))
+		positions[id] = [2]float64{centerX + radius*math.Cos(angle), centerY + radius*math.Sin(angle)}
+	}
+	if len(ids) == 1 {
+		positions[ids[0]] = [2]float64{centerX, centerY}
+		return positions
+	}
+
+	adjacency := g.successors()
+	k := math.Sqrt((maxX - minX) * (maxY - minY) / float64(len(ids)))
+	temperature := math.Max(maxX-minX, maxY-minY) / 10
+
+	for iteration := 0; iteration < svgLayoutIterations; iteration++ {
+		displacement := make(map[string][2]float64, len(ids))
+
+		for i, a := range ids {
+			for _, b := range ids[i+1:] {
+				dx, dy, distance := svgDelta(positions[a], positions[b])
+				force := k * k / distance
+				da, db := displacement[a], displacement[b]
+				da[0] += dx / distance * force
+				da[1] += dy / distance * force
+				db[0] -= dx / distance * force
+				db[1] -= dy / distance * force
+				displacement[a], displacement[b] = da, db
+			}
+		}
+
+		for _, a := range ids {
+			for _, b := range adjacency[a] {
+				if a == b {
+					continue
+				}
+				dx, dy, distance := svgDelta(positions[a], positions[b])
+				force := distance * distance / k
+				da, db := displacement[a], displacement[b]
+				da[0] -= dx / distance * force
+				da[1] -= dy / distance * force
+				db[0] += dx / distance * force
+				db[1] += dy / distance * force
+				displacement[a], displacement[b] = da, db
+			}
+		}
+
+		for _, id := range ids {
+			d := displacement[id]
+			length := math.Hypot(d[0], d[1])
+			if length == 0 {
+				continue
+			}
+			step := math.Min(length, temperature)
+			p := positions[id]
+			p[0] = math.Min(maxX, math.Max(minX, p[0]+d[0]/length*step))
+			p[1] = math.Min(maxY, math.Max(minY, p[1]+d[1]/length*step))
+			positions[id] = p
+		}
+
+		temperature *= 1 - 1/float64(svgLayoutIterations)
+	}
+
+	return positions
+}
+
+// svgDelta returns the vector from b to a and its length, which is kept
+// strictly positive so that overlapping nodes still push each other apart.
+func svgDelta(a, b [2]float64) (float64, float64, float64) {
+	dx, dy := a[0]-b[0], a[1]-b[1]
+	return dx, dy, math.Max(math.Hypot(dx, dy), 0.01)
+}
+
+// svgDisplayName returns the label of n in VisualizeToSVG.
+func svgDisplayName(n *node.Node) string {
+	for _, key := range []string{"displayname", "name"} {
+		if name, ok := n.GetProperty(key).(string); ok && name != "" {
+			return name
+		}
+	}
+	return n.GetID()
+}
@@ -0,0 +1,83 @@
+package gopengraph_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+)
+
+func TestVisualizeToSVG(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "1", []string{"User"}, map[string]interface{}{"displayname": "alice", "name": "ALICE"}))
+	g.AddNode(newTestNode(t, "2", []string{"User"}, map[string]interface{}{"name": "BOB"}))
+	g.AddNode(newTestNode(t, "3", []string{"Group"}, map[string]interface{}{"name": "R&D <team>"}))
+	g.AddNode(newTestNode(t, "4", nil, nil))
+	g.AddEdge(newTestEdge(t, "1", "3", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "2", "3", "MemberOf"))
+	g.AddEdgeWithoutValidation(newTestEdge(t, "4", "missing", "Knows"))
+
+	var buf bytes.Buffer
+	if err := g.VisualizeToSVG(&buf, gopengraph.SVGOptions{Width: 400, Height: 300}); err != nil {
+		t.Fatalf("VisualizeToSVG failed: %v", err)
+	}
+	svg := buf.String()
+
+	var document struct {
+		XMLName xml.Name `xml:"svg"`
+		Width   string   `xml:"width,attr"`
+		Group   struct {
+			Circles []struct{} `xml:"circle"`
+			Lines   []struct{} `xml:"line"`
+			Texts   []string   `xml:"text"`
+		} `xml:"g"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &document); err != nil {
+		t.Fatalf("Expected well-formed SVG, got %v:\n%s", err, svg)
+	}
+
+	if document.Width != "400" {
+		t.Errorf("Expected width 400, got %s", document.Width)
+	}
+	if len(document.Group.Circles) != 4 {
+		t.Errorf("Expected 4 circles, got %d", len(document.Group.Circles))
+	}
+	if len(document.Group.Lines) != 2 {
+		t.Errorf("Expected 2 lines, got %d", len(document.Group.Lines))
+	}
+
+	labels := strings.Join(document.Group.Texts, ",")
+	for _, label := range []string{"alice", "BOB", "R&D <team>", "4", "MemberOf"} {
+		if !strings.Contains(labels, label) {
+			t.Errorf("Expected label %q, got %s", label, labels)
+		}
+	}
+	if strings.Contains(labels, "ALICE") {
+		t.Errorf("Expected displayname to take precedence over name, got %s", labels)
+	}
+}
+
+func TestVisualizeToSVGDeterministic(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for _, id := range []string{"a", "b", "c", "d"} {
+		g.AddNode(newTestNode(t, id, nil, nil))
+	}
+	g.AddEdge(newTestEdge(t, "a", "b", "Knows"))
+	g.AddEdge(newTestEdge(t, "b", "c", "Knows"))
+
+	var first, second bytes.Buffer
+	if err := g.VisualizeToSVG(&first, gopengraph.SVGOptions{}); err != nil {
+		t.Fatalf("VisualizeToSVG failed: %v", err)
+	}
+	if err := g.VisualizeToSVG(&second, gopengraph.SVGOptions{}); err != nil {
+		t.Fatalf("VisualizeToSVG failed: %v", err)
+	}
+	if first.String() != second.String() {
+		t.Error("Expected identical output for the same graph")
+	}
+	if !strings.Contains(first.String(), `width="800"`) {
+		t.Errorf("Expected the default width for zero options, got:\n%s", first.String())
+	}
+}
@@ -0,0 +1,507 @@
+package gopengraph
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/TheManticoreProject/gopengraph/properties"
+)
+
+// Schema inference
+
+// GetNodePropertyTypes infers the Go type of each property key used by the nodes
+// of the graph.
+//
+// The type name is the one printed by the %T verb (e.g. "string", "int",
+// "[]string"). When a key holds values of different types on different nodes,
+// the distinct type names are sorted and joined with "|" (e.g. "float64|int").
+//
+// Returns:
+//
+//	map[string]string: The inferred type name of each property key.
+func (g *OpenGraph) GetNodePropertyTypes() map[string]string {
+	bags := make([]*properties.Properties, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		bags = append(bags, n.GetProperties())
+	}
+	return inferPropertyTypes(bags)
+}
+
+// GetEdgePropertyTypes infers the Go type of each property key used by the edges
+// of the graph.
+//
+// Type names follow the same rules as GetNodePropertyTypes.
+//
+// Returns:
+//
+//	map[string]string: The inferred type name of each property key.
+func (g *OpenGraph) GetEdgePropertyTypes() map[string]string {
+	bags := make([]*properties.Properties, 0, len(g.edges))
+	for _, e := range g.edges {
+		bags = append(bags, e.GetProperties())
+	}
+	return inferPropertyTypes(bags)
+}
+
+// GetNodePropertyCardinality counts the distinct values of each property key
+// across all nodes of the graph.
+//
+// Values of different types are distinct (the int 1 and the float64 1 count as
+// two values) and slices are compared element by element. A high cardinality
+// suggests an ID-like field, while a cardinality of 1 or 2 suggests a flag.
+//
+// Returns:
+//
+//	map[string]int: The number of distinct non-nil values of each property key.
+func (g *OpenGraph) GetNodePropertyCardinality() map[string]int {
+	bags := make([]*properties.Properties, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		bags = append(bags, n.GetProperties())
+	}
+	return propertyCardinality(bags)
+}
+
+// GetEdgePropertyCardinality counts the distinct values of each property key
+// across all edges of the graph.
+//
+// Values are compared as in GetNodePropertyCardinality; together, the two
+// methods describe the cardinality of the whole property schema.
+//
+// Returns:
+//
+//	map[string]int: The number of distinct non-nil values of each property key.
+func (g *OpenGraph) GetEdgePropertyCardinality() map[string]int {
+	bags := make([]*properties.Properties, 0, len(g.edges))
+	for _, e := range g.edges {
+		bags = append(bags, e.GetProperties())
+	}
+	return propertyCardinality(bags)
+}
+
+// GetPropertyKeyUsage counts how many nodes and edges use each property key.
+//
+// Comparing the counts with GetNodeCount and GetEdgeCount separates universal
+// properties, present on every object, from sparse ones.
+//
+// Returns:
+//
+//	map[string][2]int: For each property key, the number of nodes ([0]) and the
+//	                   number of edges ([1]) that set it.
+func (g *OpenGraph) GetPropertyKeyUsage() map[string][2]int {
+	usage := make(map[string][2]int)
+	for _, n := range g.nodes {
+		for key := range n.GetProperties().GetAllProperties() {
+			counts := usage[key]
+			counts[0]++
+			usage[key] = counts
+		}
+	}
+	for _, e := range g.edges {
+		for key := range e.GetProperties().GetAllProperties() {
+			counts := usage[key]
+			counts[1]++
+			usage[key] = counts
+		}
+	}
+	return usage
+}
+
+// GetKindsAdjacencyMap lists the kinds of edges found between each pair of
+// node kinds, giving a schema-level view of the structure of the graph, e.g.
+// map[User:map[Group:[MemberOf]]].
+//
+// An edge is recorded under every kind of its start node and every kind of its
+// end node. Edges whose endpoints are not nodes of the graph, and nodes without
+// kinds, are ignored.
+//
+// Returns:
+//
+//	map[string]map[string][]string: The sorted, distinct edge kinds, keyed by
+//	                                start node kind then end node kind.
+func (g *OpenGraph) GetKindsAdjacencyMap() map[string]map[string][]string {
+	seen := make(map[[3]string]bool)
+	adjacency := make(map[string]map[string][]string)
+	for _, e := range g.edges {
+		start, startExists := g.nodes[e.GetStartNodeID()]
+		end, endExists := g.nodes[e.GetEndNodeID()]
+		if !startExists || !endExists {
+			continue
+		}
+		for _, fromKind := range start.GetKinds() {
+			for _, toKind := range end.GetKinds() {
+				key := [3]string{fromKind, toKind, e.GetKind()}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				if adjacency[fromKind] == nil {
+					adjacency[fromKind] = make(map[string][]string)
+				}
+				adjacency[fromKind][toKind] = append(adjacency[fromKind][toKind], e.GetKind())
+			}
+		}
+	}
+
+	for _, targets := range adjacency {
+		for _, edgeKinds := range targets {
+			sort.Strings(edgeKinds)
+		}
+	}
+	return adjacency
+}
+
+// ComparePropertySchemas compares the property keys used by the nodes and
+// edges of the graph with those of another graph, e.g. to reconcile graphs
+// collected from different data sources.
+//
+// Types are inferred over the nodes and edges together, as GetNodePropertyTypes
+// does, so a key holding an int in one graph and an int or a float64 in the
+// other has the types "int" and "float64|int".
+//
+// Arguments:
+//
+//	other *OpenGraph: The graph to compare with.
+//
+// Returns:
+//
+//	onlyInSelf []string: The sorted keys used only by the graph.
+//	onlyInOther []string: The sorted keys used only by other.
+//	sharedWithDifferentTypes map[string][2]string: The keys used by both graphs
+//	                                               with different types, mapped
+//	                                               to the type in the graph ([0])
+//	                                               and in other ([1]).
+func (g *OpenGraph) ComparePropertySchemas(other *OpenGraph) (onlyInSelf, onlyInOther []string, sharedWithDifferentTypes map[string][2]string) {
+	selfTypes, otherTypes := g.allPropertyTypes(), other.allPropertyTypes()
+
+	onlyInSelf = make([]string, 0)
+	onlyInOther = make([]string, 0)
+	sharedWithDifferentTypes = make(map[string][2]string)
+	for key, selfType := range selfTypes {
+		otherType, shared := otherTypes[key]
+		if !shared {
+			onlyInSelf = append(onlyInSelf, key)
+		} else if selfType != otherType {
+			sharedWithDifferentTypes[key] = [2]string{selfType, otherType}
+		}
+	}
+	for key := range otherTypes {
+		if _, shared := selfTypes[key]; !shared {
+			onlyInOther = append(onlyInOther, key)
+		}
+	}
+	sort.Strings(onlyInSelf)
+	sort.Strings(onlyInOther)
+	return onlyInSelf, onlyInOther, sharedWithDifferentTypes
+}
+
+// allPropertyTypes infers the type of each property key used by the nodes or
+// the edges of the graph.
+func (g *OpenGraph) allPropertyTypes() map[string]string {
+	bags := make([]*properties.Properties, 0, len(g.nodes)+len(g.edges))
+	for _, n := range g.nodes {
+		bags = append(bags, n.GetProperties())
+	}
+	for _, e := range g.edges {
+		bags = append(bags, e.GetProperties())
+	}
+	return inferPropertyTypes(bags)
+}
+
+// Property values
+
+// GetNodePropertyUnion returns the distinct values of a property key across all
+// nodes, e.g. every "role" used in the graph.
+//
+// Values are compared with reflect.DeepEqual, so an int and a float64 of the
+// same magnitude are distinct values.
+//
+// Arguments:
+//
+//	key string: The property key.
+//
+// Returns:
+//
+//	[]interface{}: The distinct values, in the order they first occur when
+//	               visiting the nodes in ID order, or an empty slice if no node
+//	               sets key.
+func (g *OpenGraph) GetNodePropertyUnion(key string) []interface{} {
+	bags := make([]*properties.Properties, 0, len(g.nodes))
+	for _, n := range g.sortedNodes() {
+		bags = append(bags, n.GetProperties())
+	}
+	return propertyUnion(bags, key)
+}
+
+// GetEdgePropertyUnion returns the distinct values of a property key across all
+// edges, compared as in GetNodePropertyUnion.
+//
+// Arguments:
+//
+//	key string: The property key.
+//
+// Returns:
+//
+//	[]interface{}: The distinct values, in the order they first occur when
+//	               visiting the edges in insertion order, or an empty slice if
+//	               no edge sets key.
+func (g *OpenGraph) GetEdgePropertyUnion(key string) []interface{} {
+	bags := make([]*properties.Properties, 0, len(g.edges))
+	for _, e := range g.edges {
+		bags = append(bags, e.GetProperties())
+	}
+	return propertyUnion(bags, key)
+}
+
+// GetNodePropertyDistribution counts the nodes holding each value of a
+// property key.
+//
+// Values are compared as in GetNodePropertyUnion. Array values cannot be map
+// keys, so they are counted under their fmt.Sprint representation, e.g.
+// "[a b]".
+//
+// Arguments:
+//
+//	key string: The property key.
+//
+// Returns:
+//
+//	map[interface{}]int: The number of nodes holding each value.
+func (g *OpenGraph) GetNodePropertyDistribution(key string) map[interface{}]int {
+	distribution := make(map[interface{}]int)
+	for _, n := range g.nodes {
+		if !n.GetProperties().HasProperty(key) {
+			continue
+		}
+		value := n.GetProperty(key)
+		if !reflect.TypeOf(value).Comparable() {
+			value = fmt.Sprint(value)
+		}
+		distribution[value]++
+	}
+	return distribution
+}
+
+// GetNodePropertyHistogram buckets the numeric values of a node property key
+// into equal-width bins spanning the smallest to the largest value.
+//
+// Nodes whose value for key is not a single finite number are ignored. The largest
+// value is counted in the last bin. When every value is the same, all the nodes
+// are counted in the first bin.
+//
+// Arguments:
+//
+//	key string: The property key.
+//	bins int: The number of bins, at least 1.
+//
+// Returns:
+//
+//	[]float64: The lower boundary of each bin.
+//	[]int: The number of nodes in each bin.
+//	error: An error if bins is less than 1, or if no node holds a numeric
+//	       value for key.
+func (g *OpenGraph) GetNodePropertyHistogram(key string, bins int) ([]float64, []int, error) {
+	if bins < 1 {
+		return nil, nil, fmt.Errorf("histogram requires at least one bin, got %d", bins)
+	}
+
+	var values []float64
+	found := false
+	for _, n := range g.nodes {
+		if !n.GetProperties().HasProperty(key) {
+			continue
+		}
+		found = true
+		if f, ok := toFloat64(n.GetProperty(key)); ok && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			values = append(values, f)
+		}
+	}
+	if !found {
+		return nil, nil, fmt.Errorf("no node has property '%s'", key)
+	}
+	if len(values) == 0 {
+		return nil, nil, fmt.Errorf("property '%s' has no numeric values", key)
+	}
+
+	lowest, highest := values[0], values[0]
+	for _, f := range values {
+		lowest = math.Min(lowest, f)
+		highest = math.Max(highest, f)
+	}
+	// The range of finite values can exceed math.MaxFloat64, so the
+	// computations are done on halved values, whose range cannot.
+	halfRange := highest/2 - lowest/2
+
+	boundaries := make([]float64, bins)
+	for i := range boundaries {
+		boundaries[i] = (lowest/2 + float64(i)*(halfRange/float64(bins))) * 2
+	}
+	counts := make([]int, bins)
+	for _, f := range values {
+		bin := 0
+		if halfRange > 0 {
+			bin = int((f/2 - lowest/2) / halfRange * float64(bins))
+			bin = max(0, min(bin, bins-1))
+		}
+		counts[bin]++
+	}
+	return boundaries, counts, nil
+}
+
+// propertyUnion returns the distinct values of key across bags, in order of
+// first occurrence.
+func propertyUnion(bags []*properties.Properties, key string) []interface{} {
+	values := make([]interface{}, 0)
+	seen := make(map[string]bool)
+	for _, p := range bags {
+		if !p.HasProperty(key) {
+			continue
+		}
+		value := p.GetProperty(key)
+		if valueKey := propertyValueKey(value); !seen[valueKey] {
+			seen[valueKey] = true
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Property normalization
+
+// NormalizeNumericProperties restores the natural types of node and edge
+// property values after a JSON round trip.
+//
+// JSON has a single number type, so FromJSON loads every number as a float64.
+// This method converts float64 values without a fractional part to int, both as
+// single values and as elements of []interface{} arrays, and converts the
+// strings "true" and "false" to bool.
+//
+// Returns:
+//
+//	int: The number of properties, across all nodes and edges, that were updated.
+func (g *OpenGraph) NormalizeNumericProperties() int {
+	updated := 0
+	for _, n := range g.nodes {
+		changes := normalizedProperties(n.GetProperties())
+		if len(changes) == 0 {
+			continue
+		}
+		g.unindexNode(n)
+		for key, value := range changes {
+			n.SetProperty(key, value)
+		}
+		g.indexNode(n)
+		updated += len(changes)
+	}
+	for _, e := range g.edges {
+		for key, value := range normalizedProperties(e.GetProperties()) {
+			e.SetProperty(key, value)
+			updated++
+		}
+	}
+	return updated
+}
+
+// normalizedProperties returns the normalized values of the properties of p
+// that NormalizeNumericProperties changes, keyed by property key.
+func normalizedProperties(p *properties.Properties) map[string]interface{} {
+	changes := make(map[string]interface{})
+	for key, value := range p.GetAllProperties() {
+		if normalized, changed := normalizePropertyValue(value); changed {
+			changes[key] = normalized
+		}
+	}
+	return changes
+}
+
+// inferPropertyTypes collects the distinct type names of every property key
+// across bags and joins them into a single type description per key.
+func inferPropertyTypes(bags []*properties.Properties) map[string]string {
+	seen := make(map[string]map[string]bool)
+	for _, p := range bags {
+		for key, value := range p.GetAllProperties() {
+			if seen[key] == nil {
+				seen[key] = make(map[string]bool)
+			}
+			seen[key][fmt.Sprintf("%T", value)] = true
+		}
+	}
+
+	types := make(map[string]string, len(seen))
+	for key, names := range seen {
+		distinct := make([]string, 0, len(names))
+		for name := range names {
+			distinct = append(distinct, name)
+		}
+		sort.Strings(distinct)
+		types[key] = strings.Join(distinct, "|")
+	}
+	return types
+}
+
+// propertyCardinality counts the distinct non-nil values of every property key
+// across bags.
+func propertyCardinality(bags []*properties.Properties) map[string]int {
+	distinct := make(map[string]map[string]bool)
+	for _, p := range bags {
+		for key, value := range p.GetAllProperties() {
+			if value == nil {
+				continue
+			}
+			if distinct[key] == nil {
+				distinct[key] = make(map[string]bool)
+			}
+			distinct[key][propertyValueKey(value)] = true
+		}
+	}
+
+	cardinality := make(map[string]int, len(distinct))
+	for key, values := range distinct {
+		cardinality[key] = len(values)
+	}
+	return cardinality
+}
+
+// propertyValueKey returns a comparable representation of a property value that
+// distinguishes both the type and the content of the value.
+func propertyValueKey(value interface{}) string {
+	return fmt.Sprintf("%T:%#v", value, value)
+}
+
+// normalizePropertyValue returns the normalized form of a property value, as
+// described by NormalizeNumericProperties, and whether it differs from value.
+func normalizePropertyValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt && v < math.MaxInt {
+			return int(v), true
+		}
+	case string:
+		switch v {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	case []interface{}:
+		// Strings are left unchanged inside arrays, where converting some of
+		// them to bool would produce an invalid mixed-type array.
+		normalized := make([]interface{}, len(v))
+		changed := false
+		for i, element := range v {
+			normalized[i] = element
+			if f, ok := element.(float64); ok {
+				if n, ok := normalizePropertyValue(f); ok {
+					normalized[i] = n
+					changed = true
+				}
+			}
+		}
+		if changed {
+			return normalized, true
+		}
+	}
+	return value, false
+}
@@ -0,0 +1,353 @@
+package gopengraph_test
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+)
+
+func TestGetNodePropertyTypes(t *testing.T) {
+	g := newTestGraph(t)
+	g.AddNode(newTestNode(t, "carol", []string{"User"}, map[string]interface{}{
+		"name": "CAROL", "enabled": true, "logons": 4.5, "spns": []string{"http/web"},
+	}))
+
+	expected := map[string]string{
+		"name":    "string",
+		"enabled": "bool",
+		"logons":  "float64|int",
+		"spns":    "[]string",
+	}
+	if got := g.GetNodePropertyTypes(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestGetEdgePropertyTypes(t *testing.T) {
+	g := newTestGraph(t)
+	e := newTestEdge(t, "alice", "bob", "Knows")
+	e.SetProperty("source", true)
+	e.SetProperty("weight", 1)
+	g.AddEdge(e)
+
+	expected := map[string]string{
+		"source": "bool|string",
+		"weight": "int",
+	}
+	if got := g.GetEdgePropertyTypes(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	if got := gopengraph.NewOpenGraph("").GetEdgePropertyTypes(); len(got) != 0 {
+		t.Errorf("Expected no property types for an empty graph, got %v", got)
+	}
+}
+
+func TestGetNodePropertyCardinality(t *testing.T) {
+	g := newTestGraph(t)
+	g.AddNode(newTestNode(t, "carol", []string{"User"}, map[string]interface{}{
+		"name": "CAROL", "enabled": true, "logons": 3,
+	}))
+
+	expected := map[string]int{
+		"name":    4,
+		"enabled": 1,
+		"logons":  2,
+	}
+	if got := g.GetNodePropertyCardinality(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestGetNodePropertyCardinalityDistinguishesTypes(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "a", nil, map[string]interface{}{"value": 1}))
+	g.AddNode(newTestNode(t, "b", nil, map[string]interface{}{"value": 1.0}))
+	g.AddNode(newTestNode(t, "c", nil, map[string]interface{}{"value": "1"}))
+
+	if got := g.GetNodePropertyCardinality()["value"]; got != 3 {
+		t.Errorf("Expected 3 distinct values, got %d", got)
+	}
+}
+
+func TestGetEdgePropertyCardinality(t *testing.T) {
+	g := newTestGraph(t)
+	e := newTestEdge(t, "admins", "alice", "Contains")
+	e.SetProperty("source", "ldap")
+	e.SetProperty("weight", 1.0)
+	g.AddEdge(e)
+
+	expected := map[string]int{
+		"source": 2,
+		"weight": 1,
+	}
+	if got := g.GetEdgePropertyCardinality(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestGetPropertyKeyUsage(t *testing.T) {
+	g := newTestGraph(t)
+	g.GetEdgesByKind("MemberOf")[0].SetProperty("name", "membership")
+
+	expected := map[string][2]int{
+		"name":   {3, 1},
+		"logons": {3, 0},
+		"source": {0, 2},
+	}
+	if got := g.GetPropertyKeyUsage(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestGetKindsAdjacencyMap(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "alice", []string{"User", "Person"}, nil))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+	g.AddNode(newTestNode(t, "dc", []string{"Computer"}, nil))
+	g.AddNode(newTestNode(t, "untyped", nil, nil))
+	g.AddEdge(newTestEdge(t, "alice", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "alice", "dc", "HasSession"))
+	g.AddEdge(newTestEdge(t, "alice", "dc", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "admins", "dc", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "untyped", "dc", "AdminTo"))
+
+	want := map[string]map[string][]string{
+		"User":   {"Group": {"MemberOf"}, "Computer": {"AdminTo", "HasSession"}},
+		"Person": {"Group": {"MemberOf"}, "Computer": {"AdminTo", "HasSession"}},
+		"Group":  {"Computer": {"AdminTo"}},
+	}
+	if got := g.GetKindsAdjacencyMap(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestComparePropertySchemas(t *testing.T) {
+	ldap := gopengraph.NewOpenGraph("")
+	ldap.AddNode(newTestNode(t, "alice", nil, map[string]interface{}{"name": "alice", "logons": 3, "dn": "CN=alice"}))
+	ldap.AddNode(newTestNode(t, "bob", nil, map[string]interface{}{"name": "bob", "enabled": true}))
+
+	smb := gopengraph.NewOpenGraph("")
+	smb.AddNode(newTestNode(t, "alice", nil, map[string]interface{}{"name": "alice", "logons": 2.5}))
+	smb.AddNode(newTestNode(t, "share", nil, map[string]interface{}{"enabled": "yes"}))
+	smb.AddNode(newTestNode(t, "dc", nil, nil))
+	e := newTestEdge(t, "alice", "share", "CanRead")
+	e.SetProperty("path", "\\\\dc\\share")
+	smb.AddEdge(e)
+
+	onlyInSelf, onlyInOther, different := ldap.ComparePropertySchemas(smb)
+	if !reflect.DeepEqual(onlyInSelf, []string{"dn"}) {
+		t.Errorf("Expected [dn] only in self, got %v", onlyInSelf)
+	}
+	if !reflect.DeepEqual(onlyInOther, []string{"path"}) {
+		t.Errorf("Expected [path] only in other, got %v", onlyInOther)
+	}
+	want := map[string][2]string{"logons": {"int", "float64"}, "enabled": {"bool", "string"}}
+	if !reflect.DeepEqual(different, want) {
+		t.Errorf("Expected %v, got %v", want, different)
+	}
+
+	onlyInSelf, onlyInOther, different = ldap.ComparePropertySchemas(ldap)
+	if len(onlyInSelf) != 0 || len(onlyInOther) != 0 || len(different) != 0 {
+		t.Errorf("Expected no difference with itself, got %v %v %v", onlyInSelf, onlyInOther, different)
+	}
+}
+
+func TestGetNodePropertyUnionAndDistribution(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	roles := []string{"admin", "user", "user", "guest", "user", "admin", "user", "guest", "user", "user"}
+	for i, role := range roles {
+		g.AddNode(newTestNode(t, fmt.Sprintf("n%d", i), nil, map[string]interface{}{"role": role}))
+	}
+	g.AddNode(newTestNode(t, "other", nil, map[string]interface{}{"tags": []string{"a", "b"}}))
+
+	union := g.GetNodePropertyUnion("role")
+	if !reflect.DeepEqual(union, []interface{}{"admin", "user", "guest"}) {
+		t.Errorf("Expected [admin user guest], got %v", union)
+	}
+
+	distribution := g.GetNodePropertyDistribution("role")
+	expected := map[interface{}]int{"admin": 2, "user": 6, "guest": 2}
+	if !reflect.DeepEqual(distribution, expected) {
+		t.Errorf("Expected %v, got %v", expected, distribution)
+	}
+
+	if got := g.GetNodePropertyDistribution("tags"); !reflect.DeepEqual(got, map[interface{}]int{"[a b]": 1}) {
+		t.Errorf("Expected array values to be counted by their string form, got %v", got)
+	}
+	if got := g.GetNodePropertyUnion("missing"); got == nil || len(got) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %#v", got)
+	}
+}
+
+func TestGetNodePropertyHistogram(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for i, logons := range []interface{}{0, 1, 4, 5, 9, 10, "many"} {
+		g.AddNode(newTestNode(t, fmt.Sprintf("n%d", i), nil, map[string]interface{}{"logons": logons}))
+	}
+
+	boundaries, counts, err := g.GetNodePropertyHistogram("logons", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(boundaries, []float64{0, 5}) {
+		t.Errorf("Expected boundaries [0 5], got %v", boundaries)
+	}
+	if !reflect.DeepEqual(counts, []int{3, 3}) {
+		t.Errorf("Expected counts [3 3], got %v", counts)
+	}
+
+	g.AddNode(newTestNode(t, "constant", nil, map[string]interface{}{"weight": 2.5}))
+	if _, counts, err := g.GetNodePropertyHistogram("weight", 4); err != nil || !reflect.DeepEqual(counts, []int{1, 0, 0, 0}) {
+		t.Errorf("Expected counts [1 0 0 0] for a single value, got %v (%v)", counts, err)
+	}
+
+	g.AddNode(newTestNode(t, "named", nil, map[string]interface{}{"name": "alice"}))
+	for _, tt := range []struct {
+		key  string
+		bins int
+	}{{"missing", 2}, {"name", 2}, {"logons", 0}} {
+		if _, _, err := g.GetNodePropertyHistogram(tt.key, tt.bins); err == nil {
+			t.Errorf("Expected error for key %q with %d bins, got nil", tt.key, tt.bins)
+		}
+	}
+}
+
+func TestGetNodePropertyHistogramExtremeValues(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	for i, weight := range []interface{}{-math.MaxFloat64, 0.0, math.MaxFloat64, math.Inf(1), math.Inf(-1)} {
+		g.AddNode(newTestNode(t, fmt.Sprintf("n%d", i), nil, map[string]interface{}{"weight": weight}))
+	}
+
+	for _, bins := range []int{1, 2, 3} {
+		boundaries, counts, err := g.GetNodePropertyHistogram("weight", bins)
+		if err != nil {
+			t.Fatalf("Unexpected error with %d bins: %v", bins, err)
+		}
+		total := 0
+		for _, c := range counts {
+			total += c
+		}
+		if total != 3 {
+			t.Errorf("Expected the 3 finite values to be counted with %d bins, got %v", bins, counts)
+		}
+		if boundaries[0] != -math.MaxFloat64 {
+			t.Errorf("Expected the first boundary to be the smallest value, got %v", boundaries[0])
+		}
+		for _, b := range boundaries {
+			if math.IsInf(b, 0) || math.IsNaN(b) {
+				t.Errorf("Expected finite boundaries with %d bins, got %v", bins, boundaries)
+			}
+		}
+	}
+
+	if _, counts, err := g.GetNodePropertyHistogram("weight", 2); err != nil || !reflect.DeepEqual(counts, []int{1, 2}) {
+		t.Errorf("Expected counts [1 2], got %v (%v)", counts, err)
+	}
+
+	infinite := gopengraph.NewOpenGraph("")
+	infinite.AddNode(newTestNode(t, "inf", nil, map[string]interface{}{"weight": math.Inf(1)}))
+	if _, _, err := infinite.GetNodePropertyHistogram("weight", 2); err == nil {
+		t.Error("Expected an error when no value is finite")
+	}
+}
+
+func TestGetEdgePropertyUnion(t *testing.T) {
+	g := newTestGraph(t)
+	e := newTestEdge(t, "alice", "bob", "Knows")
+	e.SetProperty("source", "ldap")
+	g.AddEdge(e)
+
+	if got := g.GetEdgePropertyUnion("source"); !reflect.DeepEqual(got, []interface{}{"ldap", "smb"}) {
+		t.Errorf("Expected [ldap smb], got %v", got)
+	}
+}
+
+func TestNormalizeNumericProperties(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	err := g.FromJSON(`{
+		"graph": {
+			"nodes": [
+				{"id": "1", "kinds": ["User"], "properties": {
+					"logons": 3, "score": 2.5, "enabled": "true", "admin": "false",
+					"name": "ALICE", "ports": [80, 443], "ratios": [0.5, 1]
+				}},
+				{"id": "2", "kinds": ["User"], "properties": {"name": "BOB"}}
+			],
+			"edges": [
+				{"start": {"value": "1"}, "end": {"value": "2"}, "kind": "Knows", "properties": {"weight": 1}}
+			]
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+
+	if got := g.NormalizeNumericProperties(); got != 6 {
+		t.Errorf("Expected 6 updated properties, got %d", got)
+	}
+
+	alice := g.GetNode("1")
+	expected := map[string]interface{}{
+		"logons":  3,
+		"score":   2.5,
+		"enabled": true,
+		"admin":   false,
+		"name":    "ALICE",
+		"ports":   []interface{}{80, 443},
+		"ratios":  []interface{}{0.5, 1},
+	}
+	if got := alice.GetProperties().GetAllProperties(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, got)
+	}
+	if got := g.GetEdgesByKind("Knows")[0].GetProperty("weight"); got != 1 {
+		t.Errorf("Expected edge weight to be normalized to int 1, got %#v", got)
+	}
+
+	if got := g.NormalizeNumericProperties(); got != 0 {
+		t.Errorf("Expected normalization to be idempotent, got %d updates", got)
+	}
+}
+
+func TestNormalizeNumericPropertiesUpdatesPropertyIndex(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	if err := g.FromJSON(`{"graph": {"nodes": [{"id": "1", "kinds": ["User"], "properties": {"logons": 3}}]}}`); err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+	g.BuildPropertyIndex("logons")
+
+	g.NormalizeNumericProperties()
+	indexed := nodeIDs(g.GetNodesByPropertyValue("logons", 3))
+	scanned := nodeIDs(g.FilterNodesByProperty("logons", 3))
+	if !reflect.DeepEqual(indexed, []string{"1"}) || !reflect.DeepEqual(indexed, scanned) {
+		t.Errorf("Expected the index to find [1] like a scan, got %v (scan %v)", indexed, scanned)
+	}
+	if got := g.GetNodesByPropertyValue("logons", 3.0); len(got) != 0 {
+		t.Errorf("Expected the float64 value to be unindexed, got %v", nodeIDs(got))
+	}
+}
+
+func TestNormalizeNumericPropertiesIntRange(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	g.AddNode(newTestNode(t, "n", nil, map[string]interface{}{"big": float64(1 << 40), "huge": 1e300}))
+
+	g.NormalizeNumericProperties()
+
+	// 2^40 only fits in a 64-bit int; on 32-bit platforms it stays a float64
+	// rather than wrapping.
+	big := int64(1) << 40
+	var expected interface{} = float64(big)
+	if strconv.IntSize == 64 {
+		expected = int(big)
+	}
+	if got := g.GetNode("n").GetProperty("big"); got != expected {
+		t.Errorf("Expected %#v, got %#v", expected, got)
+	}
+	if got := g.GetNode("n").GetProperty("huge"); got != 1e300 {
+		t.Errorf("Expected float64 1e300 to be left unchanged, got %#v", got)
+	}
+}
@@ -0,0 +1,158 @@
+package gopengraph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"github.com/TheManticoreProject/gopengraph/edge"
+)
+
+// GraphSnapshot records the structure of a graph at a point in time, as taken
+// by Snapshot. It holds the node IDs and the edges of the graph, but not copies
+// of them: properties and kinds are not recorded.
+type GraphSnapshot struct {
+	// NodeCount is the number of nodes of the graph.
+	NodeCount int
+	// EdgeCount is the number of edges of the graph.
+	EdgeCount int
+	// Hash is the hex-encoded SHA-256 hash of the sorted node IDs and edge
+	// (start, kind, end) triples. Graphs with the same structure have the same
+	// hash.
+	Hash string
+
+	nodeIDs map[string]bool
+	edges   map[string][]*edge.Edge
+}
+
+// GraphDiff lists the changes to the structure of a graph since a snapshot, as
+// returned by DiffFromSnapshot.
+type GraphDiff struct {
+	AddedNodeIDs   []string
+	RemovedNodeIDs []string
+	AddedEdges     []*edge.Edge
+	RemovedEdges   []*edge.Edge
+}
+
+// IsEmpty reports whether the diff holds no change.
+func (d GraphDiff) IsEmpty() bool {
+	return len(d.AddedNodeIDs) == 0 && len(d.RemovedNodeIDs) == 0 && len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// Snapshots
+
+// Snapshot records the node IDs and edges of the graph, to find out later what
+// was added or removed with DiffFromSnapshot.
+//
+// Edges are identified by their (start, kind, end) triple, so parallel edges
+// are counted. Only references to the edges are kept, so taking a snapshot is
+// much cheaper than cloning the graph.
+//
+// Returns:
+//
+//	*GraphSnapshot: The snapshot of the graph.
+func (g *OpenGraph) Snapshot() *GraphSnapshot {
+	snap := &GraphSnapshot{
+		NodeCount: len(g.nodes),
+		EdgeCount: len(g.edges),
+		nodeIDs:   make(map[string]bool, len(g.nodes)),
+		edges:     make(map[string][]*edge.Edge),
+	}
+	for id := range g.nodes {
+		snap.nodeIDs[id] = true
+	}
+	for _, e := range g.edges {
+		key := snapshotEdgeKey(e)
+		snap.edges[key] = append(snap.edges[key], e)
+	}
+	snap.Hash = snap.hash()
+	return snap
+}
+
+// DiffFromSnapshot lists the nodes and edges added to or removed from the
+// graph since a snapshot was taken.
+//
+// An edge whose kind was changed since the snapshot is reported as removed and
+// added again. Removed edges are the edge objects recorded by the snapshot, so
+// they reflect any change made to them since.
+//
+// Arguments:
+//
+//	snap *GraphSnapshot: The snapshot to compare with, taken from this graph.
+//
+// Returns:
+//
+//	GraphDiff: The added and removed node IDs, sorted, the added edges, in
+//	           insertion order, and the removed edges, in creation order.
+func (g *OpenGraph) DiffFromSnapshot(snap *GraphSnapshot) GraphDiff {
+	diff := GraphDiff{
+		AddedNodeIDs:   make([]string, 0),
+		RemovedNodeIDs: make([]string, 0),
+		AddedEdges:     make([]*edge.Edge, 0),
+		RemovedEdges:   make([]*edge.Edge, 0),
+	}
+
+	current := g.Snapshot()
+	if current.Hash == snap.Hash {
+		return diff
+	}
+
+	for id := range current.nodeIDs {
+		if !snap.nodeIDs[id] {
+			diff.AddedNodeIDs = append(diff.AddedNodeIDs, id)
+		}
+	}
+	for id := range snap.nodeIDs {
+		if !current.nodeIDs[id] {
+			diff.RemovedNodeIDs = append(diff.RemovedNodeIDs, id)
+		}
+	}
+	sort.Strings(diff.AddedNodeIDs)
+	sort.Strings(diff.RemovedNodeIDs)
+
+	// Edges sharing a triple are matched in order, so only the surplus on
+	// either side is reported.
+	matched := make(map[string]int)
+	for _, e := range g.edges {
+		key := snapshotEdgeKey(e)
+		if matched[key] < len(snap.edges[key]) {
+			matched[key]++
+		} else {
+			diff.AddedEdges = append(diff.AddedEdges, e)
+		}
+	}
+	for key, edges := range snap.edges {
+		diff.RemovedEdges = append(diff.RemovedEdges, edges[matched[key]:]...)
+	}
+	sort.SliceStable(diff.RemovedEdges, func(i, j int) bool {
+		return diff.RemovedEdges[i].GetID() < diff.RemovedEdges[j].GetID()
+	})
+	return diff
+}
+
+// hash computes the Hash of the snapshot.
+func (snap *GraphSnapshot) hash() string {
+	nodeIDs := make([]string, 0, len(snap.nodeIDs))
+	for id := range snap.nodeIDs {
+		nodeIDs = append(nodeIDs, id)
+	}
+	sort.Strings(nodeIDs)
+
+	edgeKeys := make([]string, 0, len(snap.edges))
+	for key, edges := range snap.edges {
+		for range edges {
+			edgeKeys = append(edgeKeys, key)
+		}
+	}
+	sort.Strings(edgeKeys)
+
+	sum := sha256.Sum256([]byte(strings.Join(nodeIDs, "\x00") + "\x01" + strings.Join(edgeKeys, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// snapshotEdgeKey returns the (start, kind, end) triple identifying e in a
+// snapshot.
+func snapshotEdgeKey(e *edge.Edge) string {
+	return e.GetStartNodeID() + "\x1f" + e.GetKind() + "\x1f" + e.GetEndNodeID()
+}
@@ -0,0 +1,48 @@
+package gopengraph_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffFromSnapshot(t *testing.T) {
+	g := newTestGraph(t)
+	snap := g.Snapshot()
+	if snap.NodeCount != 3 || snap.EdgeCount != 2 {
+		t.Errorf("Expected 3 nodes and 2 edges, got %d and %d", snap.NodeCount, snap.EdgeCount)
+	}
+	if diff := g.DiffFromSnapshot(snap); !diff.IsEmpty() {
+		t.Errorf("Expected an empty diff without changes, got %+v", diff)
+	}
+
+	for _, id := range []string{"carol", "dave", "erin"} {
+		g.AddNode(newTestNode(t, id, []string{"User"}, nil))
+	}
+	diff := g.DiffFromSnapshot(snap)
+	if !reflect.DeepEqual(diff.AddedNodeIDs, []string{"carol", "dave", "erin"}) {
+		t.Errorf("Expected [carol dave erin] to be added, got %v", diff.AddedNodeIDs)
+	}
+	if len(diff.RemovedNodeIDs) != 0 || len(diff.AddedEdges) != 0 || len(diff.RemovedEdges) != 0 {
+		t.Errorf("Expected only added nodes, got %+v", diff)
+	}
+
+	g.AddEdge(newTestEdge(t, "carol", "admins", "MemberOf"))
+	g.RemoveNodeByID("bob")
+	diff = g.DiffFromSnapshot(snap)
+	if !reflect.DeepEqual(diff.RemovedNodeIDs, []string{"bob"}) {
+		t.Errorf("Expected [bob] to be removed, got %v", diff.RemovedNodeIDs)
+	}
+	if got := edgeKeys(diff.AddedEdges); !reflect.DeepEqual(got, []string{"carol-MemberOf-admins"}) {
+		t.Errorf("Expected [carol-MemberOf-admins] to be added, got %v", got)
+	}
+	if got := edgeKeys(diff.RemovedEdges); !reflect.DeepEqual(got, []string{"bob-MemberOf-admins"}) {
+		t.Errorf("Expected [bob-MemberOf-admins] to be removed, got %v", got)
+	}
+
+	if g.Snapshot().Hash == snap.Hash {
+		t.Error("Expected the hash to change with the structure")
+	}
+	if newTestGraph(t).Snapshot().Hash != snap.Hash {
+		t.Error("Expected graphs with the same structure to have the same hash")
+	}
+}
@@ -0,0 +1,155 @@
+package gopengraph
+
+import (
+	"fmt"
+
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+// SubgraphFilter selects the part of a graph extracted by Apply. Criteria left
+// at their zero value select everything; the others must all be satisfied.
+type SubgraphFilter struct {
+	// NodeKinds keeps the nodes having at least one of these kinds.
+	NodeKinds []string
+	// EdgeKinds keeps the edges having one of these kinds.
+	EdgeKinds []string
+	// NodePredicate keeps the nodes for which it returns true.
+	NodePredicate func(*node.Node) bool
+	// EdgePredicate keeps the edges for which it returns true.
+	EdgePredicate func(*edge.Edge) bool
+	// MaxDepthFromNode is the ID of a node whose neighborhood is added back to
+	// the subgraph, even if its nodes do not match the node criteria.
+	MaxDepthFromNode string
+	// MaxDepth is the number of hops from MaxDepthFromNode, following outgoing
+	// edges that match the edge criteria, within which nodes are added back.
+	MaxDepth int
+	// IncludeOrphans keeps the selected nodes that are left without any edge in
+	// the subgraph. They are dropped otherwise.
+	IncludeOrphans bool
+}
+
+// Subgraphs
+
+// Apply extracts the subgraph of g selected by the filter.
+//
+// Nodes matching all node criteria are selected first. When MaxDepthFromNode is
+// set, the nodes reachable from it within MaxDepth hops are added to the
+// selection. Edges matching all edge criteria and whose endpoints were both
+// selected are then kept, and finally nodes left without edges are dropped
+// unless IncludeOrphans is set.
+//
+// The subgraph has the source kind of g and holds copies of its nodes and
+// edges, so it can be modified without affecting g. A nil filter is treated as
+// an empty filter.
+//
+// Arguments:
+//
+//	g *OpenGraph: The graph to extract the subgraph from.
+//
+// Returns:
+//
+//	*OpenGraph: The subgraph.
+//	error: An error if g is nil, MaxDepthFromNode is not a node of g or MaxDepth is negative.
+func (f *SubgraphFilter) Apply(g *OpenGraph) (*OpenGraph, error) {
+	if g == nil {
+		return nil, fmt.Errorf("graph cannot be nil")
+	}
+	if f == nil {
+		f = &SubgraphFilter{}
+	}
+	if f.MaxDepth < 0 {
+		return nil, fmt.Errorf("max depth cannot be negative, got %d", f.MaxDepth)
+	}
+
+	selected := make(map[string]bool)
+	for id, n := range g.nodes {
+		if f.matchesNode(n) {
+			selected[id] = true
+		}
+	}
+
+	if f.MaxDepthFromNode != "" {
+		if _, exists := g.nodes[f.MaxDepthFromNode]; !exists {
+			return nil, fmt.Errorf("node '%s' not found", f.MaxDepthFromNode)
+		}
+		selected[f.MaxDepthFromNode] = true
+		frontier := []string{f.MaxDepthFromNode}
+		for depth := 0; depth < f.MaxDepth && len(frontier) > 0; depth++ {
+			var next []string
+			for _, id := range frontier {
+				for _, e := range g.GetEdgesFromNode(id) {
+					end := e.GetEndNodeID()
+					if _, exists := g.nodes[end]; !exists || selected[end] || !f.matchesEdge(e) {
+						continue
+					}
+					selected[end] = true
+					next = append(next, end)
+				}
+			}
+			frontier = next
+		}
+	}
+
+	subgraph := NewOpenGraph(g.sourceKind, g.options()...)
+	connected := make(map[string]bool)
+	var edges []*edge.Edge
+	for _, e := range g.edges {
+		if selected[e.GetStartNodeID()] && selected[e.GetEndNodeID()] && f.matchesEdge(e) {
+			edges = append(edges, e)
+			connected[e.GetStartNodeID()] = true
+			connected[e.GetEndNodeID()] = true
+		}
+	}
+
+	for _, id := range g.sortedNodeIDs() {
+		if selected[id] && (f.IncludeOrphans || connected[id]) {
+			subgraph.AddNodeWithoutValidation(cloneNode(g.nodes[id]))
+		}
+	}
+	for _, e := range edges {
+		subgraph.AddEdgeWithoutValidation(cloneEdge(e))
+	}
+
+	return subgraph, nil
+}
+
+// Subgraph extracts the subgraph selected by filter. It is a shorthand for
+// filter.Apply(g).
+//
+// Arguments:
+//
+//	filter *SubgraphFilter: The criteria selecting the subgraph.
+//
+// Returns:
+//
+//	*OpenGraph: The subgraph.
+//	error: An error if the filter is invalid for the graph.
+func (g *OpenGraph) Subgraph(filter *SubgraphFilter) (*OpenGraph, error) {
+	return filter.Apply(g)
+}
+
+// matchesNode reports whether n satisfies the node criteria of the filter.
+func (f *SubgraphFilter) matchesNode(n *node.Node) bool {
+	if len(f.NodeKinds) > 0 && !n.HasAnyKind(f.NodeKinds) {
+		return false
+	}
+	return f.NodePredicate == nil || f.NodePredicate(n)
+}
+
+// matchesEdge reports whether e satisfies the edge criteria of the filter.
+func (f *SubgraphFilter) matchesEdge(e *edge.Edge) bool {
+	if len(f.EdgeKinds) > 0 {
+		found := false
+		for _, kind := range f.EdgeKinds {
+			if e.GetKind() == kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return f.EdgePredicate == nil || f.EdgePredicate(e)
+}
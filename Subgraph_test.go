@@ -0,0 +1,147 @@
+package gopengraph_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+// subgraphSummary returns the sorted node IDs and the edges of g.
+func subgraphSummary(g *gopengraph.OpenGraph) ([]string, []string) {
+	ids := nodeIDs(g.SortNodes(func(a, b *node.Node) bool { return false }))
+	var edges []string
+	for _, e := range g.FilterEdgesByPredicate(func(e *edge.Edge) bool { return true }) {
+		edges = append(edges, e.GetStartNodeID()+"-"+e.GetKind()+"-"+e.GetEndNodeID())
+	}
+	return ids, edges
+}
+
+func TestSubgraphFilter(t *testing.T) {
+	// Two users in a group that is admin of a computer, plus an isolated user.
+	g := gopengraph.NewOpenGraph("Base")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, map[string]interface{}{"enabled": true}))
+	g.AddNode(newTestNode(t, "bob", []string{"User"}, map[string]interface{}{"enabled": false}))
+	g.AddNode(newTestNode(t, "carol", []string{"User"}, map[string]interface{}{"enabled": true}))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+	g.AddNode(newTestNode(t, "srv", []string{"Computer"}, nil))
+	g.AddEdge(newTestEdge(t, "alice", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "bob", "admins", "MemberOf"))
+	g.AddEdge(newTestEdge(t, "admins", "srv", "AdminTo"))
+	g.AddEdge(newTestEdge(t, "alice", "bob", "Knows"))
+
+	tests := []struct {
+		name          string
+		filter        *gopengraph.SubgraphFilter
+		expectedNodes []string
+		expectedEdges []string
+	}{
+		{
+			name:          "empty filter drops orphans",
+			filter:        &gopengraph.SubgraphFilter{},
+			expectedNodes: []string{"admins", "alice", "bob", "srv"},
+			expectedEdges: []string{"alice-MemberOf-admins", "bob-MemberOf-admins", "admins-AdminTo-srv", "alice-Knows-bob"},
+		},
+		{
+			name:          "include orphans",
+			filter:        &gopengraph.SubgraphFilter{IncludeOrphans: true},
+			expectedNodes: []string{"admins", "alice", "bob", "carol", "srv"},
+			expectedEdges: []string{"alice-MemberOf-admins", "bob-MemberOf-admins", "admins-AdminTo-srv", "alice-Knows-bob"},
+		},
+		{
+			name:          "node kinds",
+			filter:        &gopengraph.SubgraphFilter{NodeKinds: []string{"User", "Group"}},
+			expectedNodes: []string{"admins", "alice", "bob"},
+			expectedEdges: []string{"alice-MemberOf-admins", "bob-MemberOf-admins", "alice-Knows-bob"},
+		},
+		{
+			name:          "edge kinds",
+			filter:        &gopengraph.SubgraphFilter{EdgeKinds: []string{"MemberOf"}},
+			expectedNodes: []string{"admins", "alice", "bob"},
+			expectedEdges: []string{"alice-MemberOf-admins", "bob-MemberOf-admins"},
+		},
+		{
+			name: "node predicate",
+			filter: &gopengraph.SubgraphFilter{
+				NodePredicate: func(n *node.Node) bool { return n.GetProperty("enabled") != false },
+			},
+			expectedNodes: []string{"admins", "alice", "srv"},
+			expectedEdges: []string{"alice-MemberOf-admins", "admins-AdminTo-srv"},
+		},
+		{
+			name: "edge predicate",
+			filter: &gopengraph.SubgraphFilter{
+				EdgePredicate: func(e *edge.Edge) bool { return e.GetStartNodeID() == "alice" },
+			},
+			expectedNodes: []string{"admins", "alice", "bob"},
+			expectedEdges: []string{"alice-MemberOf-admins", "alice-Knows-bob"},
+		},
+		{
+			name: "max depth re-adds filtered nodes",
+			filter: &gopengraph.SubgraphFilter{
+				NodeKinds:        []string{"Group"},
+				MaxDepthFromNode: "alice",
+				MaxDepth:         1,
+			},
+			expectedNodes: []string{"admins", "alice", "bob"},
+			expectedEdges: []string{"alice-MemberOf-admins", "bob-MemberOf-admins", "alice-Knows-bob"},
+		},
+		{
+			name: "combined",
+			filter: &gopengraph.SubgraphFilter{
+				NodeKinds:        []string{"Computer"},
+				EdgeKinds:        []string{"MemberOf", "AdminTo"},
+				MaxDepthFromNode: "bob",
+				MaxDepth:         2,
+				NodePredicate:    func(n *node.Node) bool { return n.GetID() != "carol" },
+			},
+			expectedNodes: []string{"admins", "bob", "srv"},
+			expectedEdges: []string{"bob-MemberOf-admins", "admins-AdminTo-srv"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subgraph, err := tt.filter.Apply(g)
+			if err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			nodes, edges := subgraphSummary(subgraph)
+			if !reflect.DeepEqual(nodes, tt.expectedNodes) {
+				t.Errorf("Expected nodes %v, got %v", tt.expectedNodes, nodes)
+			}
+			if !reflect.DeepEqual(edges, tt.expectedEdges) {
+				t.Errorf("Expected edges %v, got %v", tt.expectedEdges, edges)
+			}
+		})
+	}
+
+	t.Run("copies the graph", func(t *testing.T) {
+		subgraph, err := g.Subgraph(nil)
+		if err != nil {
+			t.Fatalf("Subgraph failed: %v", err)
+		}
+		if subgraph.GetSourceKind() != "Base" {
+			t.Errorf("Expected source kind Base, got %s", subgraph.GetSourceKind())
+		}
+
+		subgraph.GetNode("alice").SetProperty("enabled", false)
+		if g.GetNode("alice").GetProperty("enabled") != true {
+			t.Error("Expected modifying the subgraph not to affect the original graph")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := (&gopengraph.SubgraphFilter{MaxDepthFromNode: "missing"}).Apply(g); err == nil {
+			t.Error("Expected an error for an unknown MaxDepthFromNode")
+		}
+		if _, err := (&gopengraph.SubgraphFilter{MaxDepthFromNode: "alice", MaxDepth: -1}).Apply(g); err == nil {
+			t.Error("Expected an error for a negative MaxDepth")
+		}
+		if _, err := (&gopengraph.SubgraphFilter{}).Apply(nil); err == nil {
+			t.Error("Expected an error for a nil graph")
+		}
+	})
+}
@@ -0,0 +1,217 @@
+package gopengraph
+
+import (
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+// Transformations
+
+// MapNodes builds a new graph whose nodes are the results of fn applied to the
+// nodes of g.
+//
+// fn receives a copy of every node, in ID order, so it can modify and return it
+// without affecting g. Nodes for which fn returns nil are left out, and when fn
+// returns several nodes with the same ID, only the first is kept. The edges of
+// g are copied, except those referencing a node that is not in the new graph,
+// e.g. because it was left out or its ID was changed by fn.
+//
+// The new graph has the source kind of g but none of its hooks or property
+// indexes.
+//
+// Arguments:
+//
+//	fn func(*node.Node) *node.Node: The function returning the node replacing
+//	                                its argument, or nil to drop it.
+//
+// Returns:
+//
+//	*OpenGraph: The new graph.
+func (g *OpenGraph) MapNodes(fn func(*node.Node) *node.Node) *OpenGraph {
+	mapped := NewOpenGraph(g.sourceKind, g.options()...)
+	for _, n := range g.sortedNodes() {
+		result := fn(cloneNode(n))
+		if result == nil {
+			continue
+		}
+		if _, exists := mapped.nodes[result.GetID()]; exists {
+			continue
+		}
+		mapped.AddNodeWithoutValidation(result)
+	}
+
+	for _, e := range g.edges {
+		if mapped.referencesMissingNode(e) {
+			continue
+		}
+		mapped.AddEdgeWithoutValidation(cloneEdge(e))
+	}
+	return mapped
+}
+
+// MapEdges builds a new graph with copies of the nodes of g and the results of
+// fn applied to its edges.
+//
+// fn receives a copy of every edge, in insertion order, so it can modify and
+// return it without affecting g. Edges for which fn returns nil are left out.
+// Unlike MapNodes, the results are kept even when they reference nodes missing
+// from the graph; GetOrphanedEdges finds them.
+//
+// The new graph has the source kind of g but none of its hooks or property
+// indexes.
+//
+// Arguments:
+//
+//	fn func(*edge.Edge) *edge.Edge: The function returning the edge replacing
+//	                                its argument, or nil to drop it.
+//
+// Returns:
+//
+//	*OpenGraph: The new graph.
+func (g *OpenGraph) MapEdges(fn func(*edge.Edge) *edge.Edge) *OpenGraph {
+	mapped := NewOpenGraph(g.sourceKind, g.options()...)
+	for _, n := range g.sortedNodes() {
+		mapped.AddNodeWithoutValidation(cloneNode(n))
+	}
+
+	for _, e := range g.edges {
+		if result := fn(cloneEdge(e)); result != nil {
+			mapped.AddEdgeWithoutValidation(result)
+		}
+	}
+	return mapped
+}
+
+// ReduceNodes folds the nodes of the graph, in ID order, into a single value,
+// e.g. to compute a custom statistic.
+//
+// Arguments:
+//
+//	initial interface{}: The initial value of the accumulator.
+//	fn func(accumulator interface{}, n *node.Node) interface{}: The function
+//	    returning the accumulator updated with a node.
+//
+// Returns:
+//
+//	interface{}: The final value of the accumulator, or initial if the graph
+//	             has no node.
+func (g *OpenGraph) ReduceNodes(initial interface{}, fn func(accumulator interface{}, n *node.Node) interface{}) interface{} {
+	accumulator := initial
+	for _, n := range g.sortedNodes() {
+		accumulator = fn(accumulator, n)
+	}
+	return accumulator
+}
+
+// ReduceEdges folds the edges of the graph, in insertion order, into a single
+// value, like ReduceNodes.
+//
+// Arguments:
+//
+//	initial interface{}: The initial value of the accumulator.
+//	fn func(accumulator interface{}, e *edge.Edge) interface{}: The function
+//	    returning the accumulator updated with an edge.
+//
+// Returns:
+//
+//	interface{}: The final value of the accumulator, or initial if the graph
+//	             has no edge.
+func (g *OpenGraph) ReduceEdges(initial interface{}, fn func(accumulator interface{}, e *edge.Edge) interface{}) interface{} {
+	accumulator := initial
+	for _, e := range g.edges {
+		accumulator = fn(accumulator, e)
+	}
+	return accumulator
+}
+
+// ZipGraphs combines g with another graph, e.g. to merge two collections with
+// custom conflict resolution.
+//
+// Nodes with the same ID in both graphs are combined by nodeZip, and edges
+// with the same endpoints and kind by edgeZip; both functions receive copies,
+// g's element first, and their nil results are left out. A nil function keeps
+// the element of g. Nodes and edges present in only one graph are copied
+// unchanged. Edges of g come first, in insertion order, followed by the edges
+// found only in other.
+//
+// The new graph has the source kind of g, or that of other if g has none, and
+// no hooks or property indexes.
+//
+// Arguments:
+//
+//	other *OpenGraph: The graph to combine with g.
+//	nodeZip func(*node.Node, *node.Node) *node.Node: The function combining
+//	    two nodes with the same ID.
+//	edgeZip func(*edge.Edge, *edge.Edge) *edge.Edge: The function combining
+//	    two edges with the same endpoints and kind.
+//
+// Returns:
+//
+//	*OpenGraph: The new graph.
+func (g *OpenGraph) ZipGraphs(other *OpenGraph, nodeZip func(*node.Node, *node.Node) *node.Node, edgeZip func(*edge.Edge, *edge.Edge) *edge.Edge) *OpenGraph {
+	sourceKind := g.sourceKind
+	if sourceKind == "" {
+		sourceKind = other.sourceKind
+	}
+	zipped := NewOpenGraph(sourceKind, g.options()...)
+
+	for _, n := range g.sortedNodes() {
+		result := cloneNode(n)
+		if match, exists := other.nodes[n.GetID()]; exists && nodeZip != nil {
+			result = nodeZip(result, cloneNode(match))
+		}
+		if result != nil {
+			zipped.AddNodeWithoutValidation(result)
+		}
+	}
+	for _, n := range other.sortedNodes() {
+		if _, exists := g.nodes[n.GetID()]; !exists {
+			zipped.AddNodeWithoutValidation(cloneNode(n))
+		}
+	}
+
+	matched := make(map[*edge.Edge]bool)
+	for _, e := range g.edges {
+		result := cloneEdge(e)
+		if match := other.findEqualEdge(e); match != nil {
+			matched[match] = true
+			if edgeZip != nil {
+				result = edgeZip(result, cloneEdge(match))
+			}
+		}
+		if result != nil {
+			zipped.AddEdgeWithoutValidation(result)
+		}
+	}
+	for _, e := range other.edges {
+		if !matched[e] && g.findEqualEdge(e) == nil {
+			zipped.AddEdgeWithoutValidation(cloneEdge(e))
+		}
+	}
+
+	return zipped
+}
+
+// findEqualEdge returns the first edge of the graph equal to e, or nil.
+func (g *OpenGraph) findEqualEdge(e *edge.Edge) *edge.Edge {
+	for _, candidate := range g.outEdges[e.GetStartNodeID()] {
+		if candidate.Equal(e) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// referencesMissingNode reports whether an id-matched endpoint of e is not a
+// node of the graph.
+func (g *OpenGraph) referencesMissingNode(e *edge.Edge) bool {
+	for _, endpoint := range []edge.Endpoint{e.GetStart(), e.GetEnd()} {
+		if endpoint.GetMatchBy() != edge.MatchByID {
+			continue
+		}
+		if _, exists := g.nodes[endpoint.GetValue()]; !exists {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package gopengraph_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/node"
+)
+
+func TestMapNodes(t *testing.T) {
+	g := newTestGraph(t)
+	g.SetSourceKind("Base")
+
+	mapped := g.MapNodes(func(n *node.Node) *node.Node {
+		if n.HasKind("Group") {
+			return nil
+		}
+		n.SetProperty("name", strings.ToLower(n.GetProperty("name").(string)))
+		return n
+	})
+
+	if got := nodeIDs(mapped.GetNodesByAllKinds(nil)); !reflect.DeepEqual(got, []string{"alice", "bob"}) {
+		t.Errorf("Expected nodes [alice bob], got %v", got)
+	}
+	if got := mapped.GetNode("alice").GetProperty("name"); got != "alice" {
+		t.Errorf("Expected the mapped name alice, got %v", got)
+	}
+	if mapped.GetEdgeCount() != 0 {
+		t.Errorf("Expected the edges to the dropped group to be pruned, got %d edges", mapped.GetEdgeCount())
+	}
+	if mapped.GetSourceKind() != "Base" {
+		t.Errorf("Expected source kind Base, got %s", mapped.GetSourceKind())
+	}
+
+	// The original graph is unchanged.
+	if got := g.GetNode("alice").GetProperty("name"); got != "ALICE" {
+		t.Errorf("Expected the original name ALICE, got %v", got)
+	}
+	if g.GetNodeCount() != 3 || g.GetEdgeCount() != 2 {
+		t.Errorf("Expected the original graph to be unchanged, got %s", g)
+	}
+}
+
+func TestMapNodesKeepsEdges(t *testing.T) {
+	g := newTestGraph(t)
+
+	identity := g.MapNodes(func(n *node.Node) *node.Node { return n })
+	if !identity.Equal(g) {
+		t.Errorf("Expected the identity mapping to produce an equal graph, got %s", identity)
+	}
+
+	renamed := g.MapNodes(func(n *node.Node) *node.Node {
+		if n.GetID() != "bob" {
+			return n
+		}
+		renamed, _ := node.NewNode("robert", n.GetKinds(), n.GetProperties())
+		return renamed
+	})
+	if got := edgeKeys(renamed.GetEdgesToNode("admins")); !reflect.DeepEqual(got, []string{"alice-MemberOf-admins"}) {
+		t.Errorf("Expected only the alice edge to be kept, got %v", got)
+	}
+}
+
+func TestMapEdges(t *testing.T) {
+	g := newTestGraph(t)
+
+	mapped := g.MapEdges(func(e *edge.Edge) *edge.Edge {
+		if e.GetProperty("source") == "smb" {
+			return nil
+		}
+		e.SetProperty("source", "collector")
+		return e.Reverse()
+	})
+
+	if mapped.GetNodeCount() != 3 {
+		t.Errorf("Expected the 3 nodes to be kept, got %d", mapped.GetNodeCount())
+	}
+	edges := mapped.GetEdgesFromNode("admins")
+	if got := edgeKeys(edges); !reflect.DeepEqual(got, []string{"admins-MemberOf-alice"}) {
+		t.Fatalf("Expected [admins-MemberOf-alice], got %v", got)
+	}
+	if edges[0].GetProperty("source") != "collector" {
+		t.Errorf("Expected the mapped source, got %v", edges[0].GetProperty("source"))
+	}
+	if got := g.GetEdgesFromNode("alice")[0].GetProperty("source"); got != "ldap" {
+		t.Errorf("Expected the original edge to be unchanged, got %v", got)
+	}
+
+	// Results referencing missing nodes are kept.
+	dangling := g.MapEdges(func(e *edge.Edge) *edge.Edge {
+		moved, _ := edge.NewEdge(e.GetStartNodeID(), "missing", e.GetKind(), nil)
+		return moved
+	})
+	if got := len(dangling.GetOrphanedEdges()); got != 2 {
+		t.Errorf("Expected 2 orphaned edges, got %d", got)
+	}
+}
+
+func TestReduceNodes(t *testing.T) {
+	g := newTestGraph(t)
+
+	total := g.ReduceNodes(0, func(accumulator interface{}, n *node.Node) interface{} {
+		logons, _ := n.GetProperties().GetInt("logons")
+		return accumulator.(int) + logons
+	})
+	if total != 13 {
+		t.Errorf("Expected 13 logons, got %v", total)
+	}
+
+	order := g.ReduceNodes("", func(accumulator interface{}, n *node.Node) interface{} {
+		return accumulator.(string) + n.GetID() + ","
+	})
+	if order != "admins,alice,bob," {
+		t.Errorf("Expected the nodes in ID order, got %v", order)
+	}
+}
+
+func TestReduceEdges(t *testing.T) {
+	g := newTestGraph(t)
+	g.AddEdge(newTestEdge(t, "alice", "bob", "Knows"))
+
+	kinds := g.ReduceEdges(map[string]int{}, func(accumulator interface{}, e *edge.Edge) interface{} {
+		accumulator.(map[string]int)[e.GetKind()]++
+		return accumulator
+	})
+	if !reflect.DeepEqual(kinds, map[string]int{"MemberOf": 2, "Knows": 1}) {
+		t.Errorf("Expected 2 MemberOf and 1 Knows, got %v", kinds)
+	}
+
+	order := g.ReduceEdges(nil, func(accumulator interface{}, e *edge.Edge) interface{} {
+		keys, _ := accumulator.([]string)
+		return append(keys, e.GetStartNodeID()+"-"+e.GetEndNodeID())
+	})
+	if !reflect.DeepEqual(order, []string{"alice-admins", "bob-admins", "alice-bob"}) {
+		t.Errorf("Expected the edges in insertion order, got %v", order)
+	}
+}
+
+func TestZipGraphs(t *testing.T) {
+	g := gopengraph.NewOpenGraph("Base")
+	g.AddNode(newTestNode(t, "alice", []string{"User"}, map[string]interface{}{"logons": 3}))
+	g.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+	e := newTestEdge(t, "alice", "admins", "MemberOf")
+	e.SetProperty("seen", 1)
+	g.AddEdge(e)
+
+	other := gopengraph.NewOpenGraph("")
+	other.AddNode(newTestNode(t, "alice", []string{"User"}, map[string]interface{}{"logons": 4}))
+	other.AddNode(newTestNode(t, "bob", []string{"User"}, nil))
+	other.AddNode(newTestNode(t, "admins", []string{"Group"}, nil))
+	e = newTestEdge(t, "alice", "admins", "MemberOf")
+	e.SetProperty("seen", 2)
+	other.AddEdge(e)
+	other.AddEdge(newTestEdge(t, "bob", "admins", "MemberOf"))
+
+	zipped := g.ZipGraphs(other,
+		func(a, b *node.Node) *node.Node {
+			x, _ := a.GetProperties().GetInt("logons")
+			y, _ := b.GetProperties().GetInt("logons")
+			a.SetProperty("logons", x+y)
+			return a
+		},
+		func(a, b *edge.Edge) *edge.Edge {
+			x, _ := a.GetProperties().GetInt("seen")
+			y, _ := b.GetProperties().GetInt("seen")
+			a.SetProperty("seen", x+y)
+			return a
+		},
+	)
+
+	if got := nodeIDs(zipped.GetNodesByAllKinds(nil)); !reflect.DeepEqual(got, []string{"admins", "alice", "bob"}) {
+		t.Errorf("Expected nodes [admins alice bob], got %v", got)
+	}
+	if got := zipped.GetNode("alice").GetProperty("logons"); got != 7 {
+		t.Errorf("Expected the zipped logons 7, got %v", got)
+	}
+	if got := edgeKeys(zipped.GetEdgesToNode("admins")); !reflect.DeepEqual(got, []string{"alice-MemberOf-admins", "bob-MemberOf-admins"}) {
+		t.Errorf("Expected both memberships, got %v", got)
+	}
+	if got := zipped.GetEdgesFromNode("alice")[0].GetProperty("seen"); got != 3 {
+		t.Errorf("Expected the zipped seen 3, got %v", got)
+	}
+	if zipped.GetSourceKind() != "Base" {
+		t.Errorf("Expected source kind Base, got %s", zipped.GetSourceKind())
+	}
+	if got := g.GetNode("alice").GetProperty("logons"); got != 3 {
+		t.Errorf("Expected the original graph to be unchanged, got %v", got)
+	}
+
+	// Nil zip functions keep the elements of g, and nil results drop them.
+	kept := g.ZipGraphs(other, nil, func(a, b *edge.Edge) *edge.Edge { return nil })
+	if got := kept.GetNode("alice").GetProperty("logons"); got != 3 {
+		t.Errorf("Expected the node of g to be kept, got %v", got)
+	}
+	if got := edgeKeys(kept.GetEdgesToNode("admins")); !reflect.DeepEqual(got, []string{"bob-MemberOf-admins"}) {
+		t.Errorf("Expected only the unmatched edge, got %v", got)
+	}
+}
@@ -0,0 +1,62 @@
+package gopengraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML exports and imports
+
+// ExportToYAML writes the graph to w in YAML format.
+//
+// The YAML document mirrors the BloodHound OpenGraph JSON schema: a "graph"
+// mapping holding "nodes" and "edges" sequences, and a "metadata" mapping when
+// the graph has a source kind. Nodes and edges are ordered as by ExportJSON.
+//
+// Arguments:
+//
+//	w io.Writer: The writer to write the YAML document to.
+//
+// Returns:
+//
+//	error: An error if the document could not be encoded or written.
+func (g *OpenGraph) ExportToYAML(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(g.toDocument(g.sortedNodes(), g.sortedEdges(), true)); err != nil {
+		return fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	return encoder.Close()
+}
+
+// ImportFromYAML reads a YAML document from r and appends it to the current graph.
+//
+// The document must follow the same structure as the JSON import and is loaded
+// with the same semantics as FromJSON: nodes are added first, followed by edges,
+// existing nodes are left unchanged and duplicate edges are skipped.
+//
+// Arguments:
+//
+//	r io.Reader: The reader to read the YAML document from.
+//
+// Returns:
+//
+//	error: An error if the document is not valid YAML or does not describe a valid graph.
+func (g *OpenGraph) ImportFromYAML(r io.Reader) error {
+	var document interface{}
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	jsonData, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to convert YAML document: %w", err)
+	}
+	return g.FromJSON(string(jsonData))
+}
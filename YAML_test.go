@@ -0,0 +1,88 @@
+package gopengraph_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph"
+)
+
+func TestYAMLRoundTrip(t *testing.T) {
+	g := gopengraph.NewOpenGraph("Base")
+	g.AddNode(newTestNode(t, "123", []string{"Person"}, map[string]interface{}{
+		"name": "BOB", "enabled": true, "tags": []string{"a", "b"},
+	}))
+	g.AddNode(newTestNode(t, "234", []string{"Person"}, map[string]interface{}{"name": "ALICE"}))
+	knows := newTestEdge(t, "123", "234", "Knows")
+	knows.SetProperty("since", 2020)
+	g.AddEdge(knows)
+
+	var buf bytes.Buffer
+	if err := g.ExportToYAML(&buf); err != nil {
+		t.Fatalf("ExportToYAML failed: %v", err)
+	}
+
+	yamlData := buf.String()
+	for _, fragment := range []string{"graph:", "nodes:", "edges:", "source_kind: Base"} {
+		if !strings.Contains(yamlData, fragment) {
+			t.Errorf("Expected YAML output to contain %q:\n%s", fragment, yamlData)
+		}
+	}
+
+	imported := gopengraph.NewOpenGraph("")
+	if err := imported.ImportFromYAML(strings.NewReader(yamlData)); err != nil {
+		t.Fatalf("ImportFromYAML failed: %v", err)
+	}
+
+	if !imported.Equal(g) {
+		t.Errorf("Expected imported graph %s to equal %s", imported, g)
+	}
+	if got := imported.GetNode("123").GetProperty("name"); got != "BOB" {
+		t.Errorf("Expected name BOB, got %v", got)
+	}
+	if got := imported.GetNode("123").GetProperty("tags"); !reflect.DeepEqual(got, []interface{}{"a", "b"}) {
+		t.Errorf("Expected tags [a b], got %#v", got)
+	}
+}
+
+func TestImportFromYAMLEmptyArrays(t *testing.T) {
+	yamlData := `
+graph:
+  nodes:
+    - id: "1"
+      kinds: [User]
+      properties:
+        groups: []
+  edges: []
+`
+	g := gopengraph.NewOpenGraph("")
+	if err := g.ImportFromYAML(strings.NewReader(yamlData)); err != nil {
+		t.Fatalf("ImportFromYAML failed: %v", err)
+	}
+
+	groups := g.GetNode("1").GetProperty("groups")
+	slice, ok := groups.([]interface{})
+	if !ok || slice == nil || len(slice) != 0 {
+		t.Errorf("Expected an empty non-nil slice, got %#v", groups)
+	}
+
+	jsonData, err := g.ExportJSON(false)
+	if err != nil {
+		t.Fatalf("ExportJSON failed: %v", err)
+	}
+	if !strings.Contains(jsonData, `"edges": []`) {
+		t.Errorf("Expected empty edges to export as an empty array, got %s", jsonData)
+	}
+}
+
+func TestImportFromYAMLInvalid(t *testing.T) {
+	g := gopengraph.NewOpenGraph("")
+	if err := g.ImportFromYAML(strings.NewReader("graph: [unterminated")); err == nil {
+		t.Error("Expected an error for malformed YAML")
+	}
+	if err := g.ImportFromYAML(strings.NewReader("graph:\n  nodes:\n    - id: \"\"\n")); err == nil {
+		t.Error("Expected an error for a node without an ID")
+	}
+}
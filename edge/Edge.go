@@ -1,9 +1,12 @@
 package edge
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
+	"sync/atomic"
 
 	"github.com/TheManticoreProject/gopengraph/properties"
 )
@@ -82,6 +85,55 @@ func NewEndpointByProperty(matchers []PropertyMatcher, kind string) Endpoint {
 	return Endpoint{matchBy: MatchByProperty, kind: kind, propertyMatchers: matchers}
 }
 
+// NewEndpointFromMap creates an endpoint from a map in the format produced by
+// Endpoint.ToDict. The match strategy defaults to MatchByID when "match_by" is
+// absent.
+func NewEndpointFromMap(data map[string]interface{}) (Endpoint, error) {
+	matchBy, _ := data["match_by"].(string)
+	if matchBy == "" {
+		matchBy = MatchByID
+	}
+	value, _ := data["value"].(string)
+	kind, _ := data["kind"].(string)
+
+	var endpoint Endpoint
+	switch matchBy {
+	case MatchByID:
+		endpoint = NewEndpointByID(value)
+	case MatchByName:
+		endpoint = NewEndpointByName(value, kind)
+	case MatchByProperty:
+		var raw []map[string]interface{}
+		switch v := data["property_matchers"].(type) {
+		case []map[string]interface{}:
+			raw = v
+		case []interface{}:
+			for _, m := range v {
+				matcher, ok := m.(map[string]interface{})
+				if !ok {
+					return Endpoint{}, fmt.Errorf("invalid property matcher: %v", m)
+				}
+				raw = append(raw, matcher)
+			}
+		}
+		matchers := make([]PropertyMatcher, 0, len(raw))
+		for _, m := range raw {
+			key, _ := m["key"].(string)
+			operator, _ := m["operator"].(string)
+			matchers = append(matchers, PropertyMatcher{Key: key, Operator: operator, Value: m["value"]})
+		}
+		endpoint = NewEndpointByProperty(matchers, kind)
+	default:
+		return Endpoint{}, fmt.Errorf("unsupported match_by %q", matchBy)
+	}
+	endpoint.kind = kind
+
+	if err := endpoint.Validate(); err != nil {
+		return Endpoint{}, err
+	}
+	return endpoint, nil
+}
+
 // GetMatchBy returns the endpoint match strategy, defaulting to MatchByID when
 // unset.
 func (e Endpoint) GetMatchBy() string {
@@ -178,12 +230,16 @@ func (e Endpoint) Equal(other Endpoint) bool {
 // - https://bloodhound.specterops.io/opengraph/developer/edges
 // - https://bloodhound.specterops.io/opengraph/developer/graph-data
 type Edge struct {
+	id         uint64
 	start      Endpoint
 	end        Endpoint
 	kind       string
 	properties *properties.Properties
 }
 
+// lastEdgeID is the ID of the most recently created edge.
+var lastEdgeID atomic.Uint64
+
 // NewEdge creates a new Edge instance whose endpoints are resolved by node id.
 // This is the common case; use NewEdgeWithEndpoints for name or property
 // matching.
@@ -216,6 +272,7 @@ func NewEdgeWithEndpoints(start Endpoint, end Endpoint, kind string, p *properti
 	}
 
 	return &Edge{
+		id:         lastEdgeID.Add(1),
 		start:      start,
 		end:        end,
 		kind:       kind,
@@ -223,6 +280,42 @@ func NewEdgeWithEndpoints(start Endpoint, end Endpoint, kind string, p *properti
 	}, nil
 }
 
+// NewEdgeFromMap creates a new Edge instance from a map in the format produced
+// by ToDict. "kind", "start" and "end" are required; "properties" is optional
+// and must hold only valid property values.
+func NewEdgeFromMap(data map[string]interface{}) (*Edge, error) {
+	kind, ok := data["kind"].(string)
+	if !ok {
+		return nil, fmt.Errorf("edge map requires a string 'kind'")
+	}
+
+	var endpoints [2]Endpoint
+	for i, name := range []string{"start", "end"} {
+		raw, ok := data[name].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("edge map requires a '%s' endpoint", name)
+		}
+		endpoint, err := NewEndpointFromMap(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s endpoint: %w", name, err)
+		}
+		endpoints[i] = endpoint
+	}
+
+	p := properties.NewProperties()
+	if raw, present := data["properties"]; present && raw != nil {
+		values, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("edge has invalid properties: %v", raw)
+		}
+		if err := p.FromMap(values); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewEdgeWithEndpoints(endpoints[0], endpoints[1], kind, p)
+}
+
 // SetProperty sets a property on the edge
 func (e *Edge) SetProperty(key string, value interface{}) {
 	e.properties.SetProperty(key, value)
@@ -282,7 +375,96 @@ func (e *Edge) GetKind() string {
 	return e.kind
 }
 
-// Equal checks if two edges are equal based on their endpoints and kind
+// SetKind changes the edge kind/type. The kind is validated as in NewEdge, and
+// the edge is left unchanged if it is invalid.
+func (e *Edge) SetKind(kind string) error {
+	if err := validateKind(kind); err != nil {
+		return err
+	}
+	e.kind = kind
+	return nil
+}
+
+// SetStart changes the start endpoint of the edge. The endpoint is validated as
+// in NewEdgeWithEndpoints, and the edge is left unchanged if it is invalid. For
+// an edge already in a graph, use OpenGraph.SetEdgeEndpoints instead, which
+// also updates the edge index of the graph.
+func (e *Edge) SetStart(start Endpoint) error {
+	if err := start.Validate(); err != nil {
+		return fmt.Errorf("invalid start endpoint: %w", err)
+	}
+	e.start = start
+	return nil
+}
+
+// SetEnd changes the end endpoint of the edge. The endpoint is validated as in
+// NewEdgeWithEndpoints, and the edge is left unchanged if it is invalid. For an
+// edge already in a graph, use OpenGraph.SetEdgeEndpoints instead.
+func (e *Edge) SetEnd(end Endpoint) error {
+	if err := end.Validate(); err != nil {
+		return fmt.Errorf("invalid end endpoint: %w", err)
+	}
+	e.end = end
+	return nil
+}
+
+// Reverse returns a new edge with the start and end endpoints swapped, the same
+// kind and a copy of the properties. The edge itself is unchanged.
+func (e *Edge) Reverse() *Edge {
+	return &Edge{
+		id:         lastEdgeID.Add(1),
+		start:      e.end,
+		end:        e.start,
+		kind:       e.kind,
+		properties: e.properties.Clone(),
+	}
+}
+
+// MarshalJSON encodes the edge in the BloodHound OpenGraph edge format, as
+// returned by ToDict.
+func (e *Edge) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.ToDict())
+}
+
+// UnmarshalJSON replaces the edge with one decoded from the BloodHound
+// OpenGraph edge format, validated as in NewEdgeFromMap. The edge gets a new
+// ID.
+func (e *Edge) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var dict map[string]interface{}
+	if err := decoder.Decode(&dict); err != nil {
+		return fmt.Errorf("failed to parse edge: %w", err)
+	}
+
+	decoded, err := NewEdgeFromMap(dict)
+	if err != nil {
+		return err
+	}
+	*e = *decoded
+	return nil
+}
+
+// GetID returns the ID of the edge: a sequence number assigned when the edge
+// is created, unique within the process. Copies made with Reverse get a new ID.
+func (e *Edge) GetID() uint64 {
+	return e.id
+}
+
+// IsSame reports whether other is the same edge as e, by ID. Unlike Equal, it
+// tells apart parallel edges sharing their endpoints and kind, as stored by a
+// multigraph.
+func (e *Edge) IsSame(other *Edge) bool {
+	return other != nil && e.id == other.id
+}
+
+// Equal checks if two edges are equal based on their endpoints and kind. The
+// ID is not compared; see IsSame.
+//
+// The ID is left out on purpose, even in multigraph mode: Equal is how AddEdge
+// detects duplicate edges, how HasEdge finds an edge and how OpenGraph.Equal
+// compares two graphs, and every edge created, copied or decoded gets a new ID.
+// Comparing it would make no two distinct edge values equal.
 func (e *Edge) Equal(other *Edge) bool {
 	if other == nil {
 		return false
@@ -0,0 +1,74 @@
+package edge
+
+import (
+	"fmt"
+
+	"github.com/TheManticoreProject/gopengraph/properties"
+)
+
+// EdgeBuilder constructs an id-matched Edge through method chaining.
+//
+// Validation errors are deferred until Build, so a chain can be written as a
+// single expression:
+//
+//	e, err := edge.NewEdgeBuilder().
+//		FromNode("123").
+//		ToNode("234").
+//		WithKind("Knows").
+//		Build()
+type EdgeBuilder struct {
+	startNodeID string
+	endNodeID   string
+	kind        string
+	properties  *properties.Properties
+	err         error
+}
+
+// NewEdgeBuilder creates a new, empty EdgeBuilder
+func NewEdgeBuilder() *EdgeBuilder {
+	return &EdgeBuilder{
+		properties: properties.NewProperties(),
+	}
+}
+
+// FromNode sets the ID of the start node of the edge
+func (b *EdgeBuilder) FromNode(startNodeID string) *EdgeBuilder {
+	b.startNodeID = startNodeID
+	return b
+}
+
+// ToNode sets the ID of the end node of the edge
+func (b *EdgeBuilder) ToNode(endNodeID string) *EdgeBuilder {
+	b.endNodeID = endNodeID
+	return b
+}
+
+// WithKind sets the kind of the edge
+func (b *EdgeBuilder) WithKind(kind string) *EdgeBuilder {
+	b.kind = kind
+	return b
+}
+
+// WithProperty sets a property on the edge. An invalid property value is
+// reported by Build.
+func (b *EdgeBuilder) WithProperty(key string, value interface{}) *EdgeBuilder {
+	if !b.properties.IsPropertyValueValid(value) {
+		if b.err == nil {
+			b.err = fmt.Errorf("invalid value for property '%s': %T", key, value)
+		}
+		return b
+	}
+	b.properties.SetProperty(key, value)
+	return b
+}
+
+// Build creates the Edge, returning the first error encountered while building
+// or any error returned by NewEdge.
+func (b *EdgeBuilder) Build() (*Edge, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	props := properties.NewPropertiesFromMap(b.properties.GetAllProperties())
+	return NewEdge(b.startNodeID, b.endNodeID, b.kind, props)
+}
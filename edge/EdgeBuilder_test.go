@@ -0,0 +1,55 @@
+package edge_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph/edge"
+	"github.com/TheManticoreProject/gopengraph/properties"
+)
+
+func TestEdgeBuilder(t *testing.T) {
+	built, err := edge.NewEdgeBuilder().
+		FromNode("123").
+		ToNode("234").
+		WithKind("Knows").
+		WithProperty("since", 2020).
+		WithProperty("trusted", true).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	props := properties.NewProperties()
+	props.SetProperty("since", 2020)
+	props.SetProperty("trusted", true)
+	expected, _ := edge.NewEdge("123", "234", "Knows", props)
+
+	if !built.Equal(expected) {
+		t.Errorf("expected built edge to equal %v, got %v", expected, built)
+	}
+	if !reflect.DeepEqual(built.GetProperties().ToDict(), expected.GetProperties().ToDict()) {
+		t.Errorf("expected properties %v, got %v", expected.GetProperties().ToDict(), built.GetProperties().ToDict())
+	}
+}
+
+func TestEdgeBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *edge.EdgeBuilder
+	}{
+		{"missing start", edge.NewEdgeBuilder().ToNode("2").WithKind("Knows")},
+		{"missing end", edge.NewEdgeBuilder().FromNode("1").WithKind("Knows")},
+		{"missing kind", edge.NewEdgeBuilder().FromNode("1").ToNode("2")},
+		{"invalid kind", edge.NewEdgeBuilder().FromNode("1").ToNode("2").WithKind("has-session")},
+		{"invalid property", edge.NewEdgeBuilder().FromNode("1").ToNode("2").WithKind("Knows").WithProperty("p", struct{}{})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.builder.Build(); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
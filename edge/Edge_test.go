@@ -1,6 +1,8 @@
 package edge_test
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/TheManticoreProject/gopengraph/edge"
@@ -115,6 +117,56 @@ func TestNewEdgeKindValidation(t *testing.T) {
 	}
 }
 
+func TestEdgeSetKind(t *testing.T) {
+	e, err := edge.NewEdge("a", "b", "MemberOf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := e.SetKind("AdminTo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.GetKind() != "AdminTo" {
+		t.Errorf("expected kind AdminTo, got %s", e.GetKind())
+	}
+
+	for _, kind := range []string{"", "Has Access", "tag_custom"} {
+		if err := e.SetKind(kind); err == nil {
+			t.Errorf("expected kind %q to be rejected, got nil error", kind)
+		}
+	}
+	if e.GetKind() != "AdminTo" {
+		t.Errorf("expected kind to be unchanged after an error, got %s", e.GetKind())
+	}
+}
+
+func TestEdgeSetStartAndEnd(t *testing.T) {
+	e, err := edge.NewEdge("a", "b", "MemberOf", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := e.SetStart(edge.NewEndpointByID("c")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := e.SetEnd(edge.NewEndpointByName("admins", "Group")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.GetStartNodeID() != "c" || e.GetEnd().GetMatchBy() != edge.MatchByName || e.GetEndNodeID() != "admins" {
+		t.Errorf("expected endpoints c and admins by name, got %v and %v", e.GetStart(), e.GetEnd())
+	}
+
+	if err := e.SetStart(edge.NewEndpointByID("")); err == nil {
+		t.Error("expected error for an empty start endpoint, got nil")
+	}
+	if err := e.SetEnd(edge.NewEndpointByID("")); err == nil {
+		t.Error("expected error for an empty end endpoint, got nil")
+	}
+	if e.GetStartNodeID() != "c" || e.GetEndNodeID() != "admins" {
+		t.Errorf("expected endpoints to be unchanged after an error, got %s and %s", e.GetStartNodeID(), e.GetEndNodeID())
+	}
+}
+
 func TestEdgeProperties(t *testing.T) {
 	e, err := edge.NewEdge("start1", "end1", "CONNECTS_TO", nil)
 	if err != nil {
@@ -157,6 +209,36 @@ func TestEdgeEqual(t *testing.T) {
 	}
 }
 
+func TestEdgeReverse(t *testing.T) {
+	e, err := edge.NewEdge("alice", "admins", "MemberOf", properties.NewPropertiesFromMap(map[string]interface{}{
+		"isacl": false,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reversed := e.Reverse()
+	if reversed.GetStartNodeID() != "admins" || reversed.GetEndNodeID() != "alice" || reversed.GetKind() != "MemberOf" {
+		t.Errorf("expected (admins)-[MemberOf]->(alice), got %v", reversed)
+	}
+	if e.GetStartNodeID() != "alice" || e.GetEndNodeID() != "admins" {
+		t.Errorf("expected the original edge to be unchanged, got %v", e)
+	}
+	if !reversed.Reverse().Equal(e) {
+		t.Error("expected reversing twice to give back an equal edge")
+	}
+
+	// The reversed edge is valid for NewEdge.
+	if _, err := edge.NewEdge(reversed.GetStartNodeID(), reversed.GetEndNodeID(), reversed.GetKind(), nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	reversed.SetProperty("isacl", true)
+	if e.GetProperty("isacl") != false {
+		t.Error("expected modifying the reversed edge's properties not to affect the original")
+	}
+}
+
 func TestEdgeToDict(t *testing.T) {
 	props := properties.NewProperties()
 	props.SetProperty("weight", 10)
@@ -307,3 +389,111 @@ func TestEdgeEqualAcrossMatchStrategies(t *testing.T) {
 func contains(s, substr string) bool {
 	return s != "" && substr != "" && s != substr && len(s) > len(substr) && s[len(s)-1] != substr[0]
 }
+
+func TestNewEdgeFromMap(t *testing.T) {
+	props := properties.NewProperties()
+	props.SetProperty("weight", 2)
+	matchers := []edge.PropertyMatcher{{Key: "username", Operator: "equals", Value: "alice"}}
+	edges := []*edge.Edge{}
+	for _, endpoints := range [][2]edge.Endpoint{
+		{edge.NewEndpointByID("a"), edge.NewEndpointByID("b")},
+		{edge.NewEndpointByName("alice", "User"), edge.NewEndpointByProperty(matchers, "User")},
+	} {
+		e, err := edge.NewEdgeWithEndpoints(endpoints[0], endpoints[1], "MemberOf", props)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		edges = append(edges, e)
+	}
+
+	for _, e := range edges {
+		got, err := edge.NewEdgeFromMap(e.ToDict())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !got.Equal(e) {
+			t.Errorf("expected edge %v, got %v", e, got)
+		}
+		if !reflect.DeepEqual(got.GetProperties().GetAllProperties(), props.GetAllProperties()) {
+			t.Errorf("expected properties %v, got %v", props.GetAllProperties(), got.GetProperties().GetAllProperties())
+		}
+	}
+
+	// Endpoints decoded from JSON have []interface{} property matchers and
+	// may omit match_by.
+	got, err := edge.NewEdgeFromMap(map[string]interface{}{
+		"kind":  "AdminTo",
+		"start": map[string]interface{}{"value": "a"},
+		"end": map[string]interface{}{
+			"match_by":          "property",
+			"property_matchers": []interface{}{map[string]interface{}{"key": "name", "operator": "equals", "value": "b"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.GetStartNodeID() != "a" || got.GetEnd().GetMatchBy() != edge.MatchByProperty {
+		t.Errorf("unexpected edge %v", got)
+	}
+
+	invalid := []map[string]interface{}{
+		{"start": map[string]interface{}{"value": "a"}, "end": map[string]interface{}{"value": "b"}},
+		{"kind": "AdminTo", "end": map[string]interface{}{"value": "b"}},
+		{"kind": "AdminTo", "start": map[string]interface{}{"value": "a"}, "end": map[string]interface{}{"match_by": "other", "value": "b"}},
+		{"kind": "AdminTo", "start": map[string]interface{}{"value": "a"}, "end": map[string]interface{}{"value": "b"}, "properties": map[string]interface{}{"bad": nil}},
+	}
+	for _, data := range invalid {
+		if _, err := edge.NewEdgeFromMap(data); err == nil {
+			t.Errorf("expected error for %v, got nil", data)
+		}
+	}
+}
+
+func TestEdgeIDAndIsSame(t *testing.T) {
+	e1, _ := edge.NewEdge("a", "b", "Knows", nil)
+	e2, _ := edge.NewEdge("a", "b", "Knows", nil)
+
+	if e1.GetID() == 0 || e2.GetID() <= e1.GetID() {
+		t.Errorf("expected increasing non-zero ids, got %d and %d", e1.GetID(), e2.GetID())
+	}
+	if !e1.IsSame(e1) || e1.IsSame(e2) || e1.IsSame(nil) {
+		t.Error("expected an edge to be the same only as itself")
+	}
+	if !e1.Equal(e2) {
+		t.Error("expected edges with the same endpoints and kind to be equal regardless of id")
+	}
+	if r := e1.Reverse(); r.GetID() == e1.GetID() {
+		t.Error("expected the reversed edge to get a new id")
+	}
+}
+
+func TestEdgeJSONCodec(t *testing.T) {
+	props := properties.NewProperties()
+	props.SetProperty("weight", 2)
+	e, err := edge.NewEdge("a", "b", "MemberOf", props)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got edge.Edge
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Equal(e) {
+		t.Errorf("expected edge %v, got %v", e, &got)
+	}
+	if got.IsSame(e) {
+		t.Error("expected the decoded edge to get a new ID")
+	}
+	if !reflect.DeepEqual(got.GetProperties().GetAllProperties(), props.GetAllProperties()) {
+		t.Errorf("expected properties %v, got %v", props.GetAllProperties(), got.GetProperties().GetAllProperties())
+	}
+
+	if err := json.Unmarshal([]byte(`{"start": {"value": "a"}, "end": {"value": "b"}}`), &got); err == nil {
+		t.Error("expected error for an edge without a kind, got nil")
+	}
+}
@@ -1,6 +1,8 @@
 package node
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 
 	"github.com/TheManticoreProject/gopengraph/properties"
@@ -50,6 +52,47 @@ func NewNode(id string, kinds []string, p *properties.Properties) (*Node, error)
 	}, nil
 }
 
+// NewNodeFromMap creates a new Node instance from a map in the format produced
+// by ToDict. The "id" entry is required; "kinds" may be a []string or a
+// []interface{} of strings and "properties" must hold only valid property
+// values.
+func NewNodeFromMap(data map[string]interface{}) (*Node, error) {
+	id, ok := data["id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("node map requires a string 'id'")
+	}
+
+	var kinds []string
+	switch v := data["kinds"].(type) {
+	case nil:
+	case []string:
+		kinds = append(kinds, v...)
+	case []interface{}:
+		for _, k := range v {
+			kind, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("node '%s' has a non-string kind: %v", id, k)
+			}
+			kinds = append(kinds, kind)
+		}
+	default:
+		return nil, fmt.Errorf("node '%s' has invalid kinds: %v", id, v)
+	}
+
+	p := properties.NewProperties()
+	if raw, present := data["properties"]; present && raw != nil {
+		values, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("node '%s' has invalid properties: %v", id, raw)
+		}
+		if err := p.FromMap(values); err != nil {
+			return nil, fmt.Errorf("node '%s': %w", id, err)
+		}
+	}
+
+	return NewNode(id, kinds, p)
+}
+
 // AddKind adds a kind/type to the node if it doesn't already exist.
 //
 // The BloodHound OpenGraph schema limits a node to at most MaxKinds (3) kinds.
@@ -77,6 +120,25 @@ func (n *Node) RemoveKind(kind string) {
 	}
 }
 
+// ReplaceKinds replaces all the kinds of the node with kinds, dropping
+// duplicates. It returns an error, leaving the node unchanged, if kinds holds
+// more than MaxKinds distinct kinds.
+func (n *Node) ReplaceKinds(kinds []string) error {
+	replaced := make([]string, 0, len(kinds))
+	seen := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		if !seen[kind] {
+			seen[kind] = true
+			replaced = append(replaced, kind)
+		}
+	}
+	if len(replaced) > MaxKinds {
+		return fmt.Errorf("node cannot have more than %d kinds, got %d", MaxKinds, len(replaced))
+	}
+	n.kinds = replaced
+	return nil
+}
+
 func (n *Node) GetKinds() []string {
 	return n.kinds
 }
@@ -91,10 +153,44 @@ func (n *Node) HasKind(kind string) bool {
 	return false
 }
 
+// HasAllKinds checks if node has every kind of kinds. It returns true for an
+// empty slice.
+func (n *Node) HasAllKinds(kinds []string) bool {
+	for _, kind := range kinds {
+		if !n.HasKind(kind) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAnyKind checks if node has at least one kind of kinds. It returns false
+// for an empty slice.
+func (n *Node) HasAnyKind(kinds []string) bool {
+	for _, kind := range kinds {
+		if n.HasKind(kind) {
+			return true
+		}
+	}
+	return false
+}
+
 func (n *Node) GetID() string {
 	return n.id
 }
 
+// SetID changes the ID of the node, e.g. to replace a provisional ID before the
+// node is added to a graph. It returns an error and leaves the node unchanged
+// if id is empty. For a node already in a graph, use OpenGraph.ReplaceNodeID
+// instead, which also updates the edges and indexes of the graph.
+func (n *Node) SetID(id string) error {
+	if id == "" {
+		return fmt.Errorf("node ID cannot be empty")
+	}
+	n.id = id
+	return nil
+}
+
 // SetProperty sets a property on the node
 func (n *Node) SetProperty(key string, value interface{}) {
 	n.properties.SetProperty(key, value)
@@ -124,6 +220,30 @@ func (n *Node) ToDict() map[string]interface{} {
 	}
 }
 
+// MarshalJSON encodes the node in the BloodHound OpenGraph node format, as
+// returned by ToDict.
+func (n *Node) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.ToDict())
+}
+
+// UnmarshalJSON replaces the node with one decoded from the BloodHound
+// OpenGraph node format, validated as in NewNodeFromMap.
+func (n *Node) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var dict map[string]interface{}
+	if err := decoder.Decode(&dict); err != nil {
+		return fmt.Errorf("failed to parse node: %w", err)
+	}
+
+	decoded, err := NewNodeFromMap(dict)
+	if err != nil {
+		return err
+	}
+	*n = *decoded
+	return nil
+}
+
 // Equal checks if two nodes are equal based on their ID
 func (n *Node) Equal(other *Node) bool {
 	if other == nil {
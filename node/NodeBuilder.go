@@ -0,0 +1,73 @@
+package node
+
+import (
+	"fmt"
+
+	"github.com/TheManticoreProject/gopengraph/properties"
+)
+
+// NodeBuilder constructs a Node through method chaining.
+//
+// Validation errors are deferred until Build, so a chain can be written as a
+// single expression:
+//
+//	n, err := node.NewNodeBuilder().
+//		WithID("123").
+//		WithKind("User").
+//		WithProperty("name", "BOB").
+//		Build()
+type NodeBuilder struct {
+	id         string
+	kinds      []string
+	properties *properties.Properties
+	err        error
+}
+
+// NewNodeBuilder creates a new, empty NodeBuilder
+func NewNodeBuilder() *NodeBuilder {
+	return &NodeBuilder{
+		kinds:      make([]string, 0),
+		properties: properties.NewProperties(),
+	}
+}
+
+// WithID sets the ID of the node
+func (b *NodeBuilder) WithID(id string) *NodeBuilder {
+	b.id = id
+	return b
+}
+
+// WithKind adds a kind to the node, ignoring kinds that were already added
+func (b *NodeBuilder) WithKind(kind string) *NodeBuilder {
+	for _, k := range b.kinds {
+		if k == kind {
+			return b
+		}
+	}
+	b.kinds = append(b.kinds, kind)
+	return b
+}
+
+// WithProperty sets a property on the node. An invalid property value is
+// reported by Build.
+func (b *NodeBuilder) WithProperty(key string, value interface{}) *NodeBuilder {
+	if !b.properties.IsPropertyValueValid(value) {
+		if b.err == nil {
+			b.err = fmt.Errorf("invalid value for property '%s': %T", key, value)
+		}
+		return b
+	}
+	b.properties.SetProperty(key, value)
+	return b
+}
+
+// Build creates the Node, returning the first error encountered while building
+// or any error returned by NewNode.
+func (b *NodeBuilder) Build() (*Node, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	props := properties.NewPropertiesFromMap(b.properties.GetAllProperties())
+	return NewNode(b.id, append([]string{}, b.kinds...), props)
+}
@@ -0,0 +1,81 @@
+package node_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/TheManticoreProject/gopengraph/node"
+	"github.com/TheManticoreProject/gopengraph/properties"
+)
+
+func TestNodeBuilder(t *testing.T) {
+	built, err := node.NewNodeBuilder().
+		WithID("S-1-5-21-1").
+		WithKind("User").
+		WithKind("Person").
+		WithKind("Base").
+		WithKind("User").
+		WithProperty("name", "ALICE").
+		WithProperty("displayname", "alice").
+		WithProperty("objectid", "S-1-5-21-1").
+		WithProperty("enabled", true).
+		WithProperty("logoncount", 12).
+		WithProperty("spns", []string{"http/web", "cifs/web"}).
+		Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	props := properties.NewProperties()
+	props.SetProperty("name", "ALICE")
+	props.SetProperty("displayname", "alice")
+	props.SetProperty("objectid", "S-1-5-21-1")
+	props.SetProperty("enabled", true)
+	props.SetProperty("logoncount", 12)
+	props.SetProperty("spns", []string{"http/web", "cifs/web"})
+	expected, err := node.NewNode("S-1-5-21-1", []string{"User", "Person", "Base"}, props)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !built.Equal(expected) {
+		t.Errorf("expected built node to equal %v, got %v", expected, built)
+	}
+	if !reflect.DeepEqual(built.GetKinds(), expected.GetKinds()) {
+		t.Errorf("expected kinds %v, got %v", expected.GetKinds(), built.GetKinds())
+	}
+	if !reflect.DeepEqual(built.GetProperties().ToDict(), expected.GetProperties().ToDict()) {
+		t.Errorf("expected properties %v, got %v", expected.GetProperties().ToDict(), built.GetProperties().ToDict())
+	}
+}
+
+func TestNodeBuilderErrors(t *testing.T) {
+	if _, err := node.NewNodeBuilder().WithKind("User").Build(); err == nil {
+		t.Error("expected error for a node without an ID")
+	}
+
+	_, err := node.NewNodeBuilder().WithID("node1").
+		WithKind("A").WithKind("B").WithKind("C").WithKind("D").
+		Build()
+	if err == nil {
+		t.Error("expected error for a node built with four kinds")
+	}
+
+	_, err = node.NewNodeBuilder().WithID("node1").
+		WithProperty("nested", map[string]interface{}{"a": 1}).
+		Build()
+	if err == nil {
+		t.Error("expected error for an invalid property value")
+	}
+}
+
+func TestNodeBuilderBuildsIndependentNodes(t *testing.T) {
+	b := node.NewNodeBuilder().WithID("node1").WithKind("User").WithProperty("name", "a")
+	n1, _ := b.Build()
+	b.WithProperty("name", "b")
+	n2, _ := b.Build()
+
+	if n1.GetProperty("name") != "a" || n2.GetProperty("name") != "b" {
+		t.Errorf("expected nodes built from the same builder to be independent, got %v and %v", n1, n2)
+	}
+}
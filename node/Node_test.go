@@ -1,6 +1,8 @@
 package node_test
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/TheManticoreProject/gopengraph/node"
@@ -114,6 +116,35 @@ func TestNodeKinds(t *testing.T) {
 	}
 }
 
+func TestNodeHasAllAndAnyKinds(t *testing.T) {
+	n, err := node.NewNode("node1", []string{"User", "Base"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		kinds   []string
+		wantAll bool
+		wantAny bool
+	}{
+		{"empty", []string{}, true, false},
+		{"nil", nil, true, false},
+		{"subset", []string{"User"}, true, true},
+		{"same kinds", []string{"Base", "User"}, true, true},
+		{"overlapping", []string{"User", "Group"}, false, true},
+		{"non-overlapping", []string{"Group", "Computer"}, false, false},
+	}
+	for _, tt := range tests {
+		if got := n.HasAllKinds(tt.kinds); got != tt.wantAll {
+			t.Errorf("%s: expected HasAllKinds to return %v, got %v", tt.name, tt.wantAll, got)
+		}
+		if got := n.HasAnyKind(tt.kinds); got != tt.wantAny {
+			t.Errorf("%s: expected HasAnyKind to return %v, got %v", tt.name, tt.wantAny, got)
+		}
+	}
+}
+
 func TestNodeProperties(t *testing.T) {
 	n, err := node.NewNode("node1", nil, nil)
 	if err != nil {
@@ -230,6 +261,126 @@ func TestAddKindRespectsMaxKinds(t *testing.T) {
 	}
 }
 
+func TestReplaceKinds(t *testing.T) {
+	n, err := node.NewNode("node1", []string{"A", "B"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := n.ReplaceKinds([]string{"NewKind", "Other", "NewKind"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(n.GetKinds(), []string{"NewKind", "Other"}) {
+		t.Errorf("expected kinds [NewKind Other], got %v", n.GetKinds())
+	}
+
+	if err := n.ReplaceKinds([]string{"A", "B", "C", "D"}); err == nil {
+		t.Error("expected error for four kinds, got nil")
+	}
+	if !reflect.DeepEqual(n.GetKinds(), []string{"NewKind", "Other"}) {
+		t.Errorf("expected kinds to be unchanged after an error, got %v", n.GetKinds())
+	}
+}
+
+func TestNodeSetID(t *testing.T) {
+	n, err := node.NewNode("provisional", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := n.SetID(""); err == nil {
+		t.Error("expected error for an empty ID, got nil")
+	}
+	if n.GetID() != "provisional" {
+		t.Errorf("expected ID to be unchanged after an error, got %q", n.GetID())
+	}
+
+	if err := n.SetID("node1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n.GetID() != "node1" {
+		t.Errorf("expected ID %q, got %q", "node1", n.GetID())
+	}
+}
+
+func TestNewNodeFromMap(t *testing.T) {
+	props := properties.NewProperties()
+	props.SetProperty("name", "alice")
+	props.SetProperty("logons", 3)
+	props.SetProperty("tags", []string{"a", "b"})
+	n, err := node.NewNode("node1", []string{"User", "Base"}, props)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := node.NewNodeFromMap(n.ToDict())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Equal(n) {
+		t.Errorf("expected node %q, got %q", n.GetID(), got.GetID())
+	}
+	if !reflect.DeepEqual(got.GetKinds(), n.GetKinds()) {
+		t.Errorf("expected kinds %v, got %v", n.GetKinds(), got.GetKinds())
+	}
+	if !reflect.DeepEqual(got.GetProperties().GetAllProperties(), props.GetAllProperties()) {
+		t.Errorf("expected properties %v, got %v", props.GetAllProperties(), got.GetProperties().GetAllProperties())
+	}
+
+	// Kinds decoded from JSON arrive as []interface{}.
+	got, err = node.NewNodeFromMap(map[string]interface{}{"id": "node2", "kinds": []interface{}{"Group"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got.GetKinds(), []string{"Group"}) {
+		t.Errorf("expected kinds [Group], got %v", got.GetKinds())
+	}
+
+	invalid := []map[string]interface{}{
+		{"kinds": []string{"User"}},
+		{"id": "node3", "kinds": []interface{}{1}},
+		{"id": "node3", "properties": map[string]interface{}{"nested": map[string]interface{}{"a": 1}}},
+		{"id": "node3", "kinds": []string{"A", "B", "C", "D"}},
+	}
+	for _, data := range invalid {
+		if _, err := node.NewNodeFromMap(data); err == nil {
+			t.Errorf("expected error for %v, got nil", data)
+		}
+	}
+}
+
+func TestNodeJSONCodec(t *testing.T) {
+	props := properties.NewProperties()
+	props.SetProperty("name", "alice")
+	props.SetProperty("logons", 3)
+	n, err := node.NewNode("node1", []string{"User", "Base"}, props)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(n)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got node.Node
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Equal(n) {
+		t.Errorf("expected node %q, got %q", n.GetID(), got.GetID())
+	}
+	if !reflect.DeepEqual(got.GetKinds(), n.GetKinds()) {
+		t.Errorf("expected kinds %v, got %v", n.GetKinds(), got.GetKinds())
+	}
+	if !reflect.DeepEqual(got.GetProperties().GetAllProperties(), props.GetAllProperties()) {
+		t.Errorf("expected properties %v, got %v", props.GetAllProperties(), got.GetProperties().GetAllProperties())
+	}
+
+	if err := json.Unmarshal([]byte(`{"kinds": ["User"]}`), &got); err == nil {
+		t.Error("expected error for a node without an ID, got nil")
+	}
+}
+
 // Helper function to check if a slice contains a string
 func contains(slice []string, str string) bool {
 	for _, s := range slice {
@@ -1,8 +1,13 @@
 package properties
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"reflect"
+	"sort"
 )
 
 type Properties struct {
@@ -47,10 +52,144 @@ func (p *Properties) GetProperty(key string, defaultVal ...interface{}) interfac
 	return nil
 }
 
+// GetFloat64 returns the property key as a float64 when it holds a number of
+// any Go numeric type or a json.Number. ok is false when the property is
+// missing or holds another type.
+func (p *Properties) GetFloat64(key string) (value float64, ok bool) {
+	if n, isNumber := p.Properties[key].(json.Number); isNumber {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+
+	v := reflect.ValueOf(p.Properties[key])
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// GetInt returns the property key as an int when it holds an integer of any Go
+// integer type or an integer json.Number. ok is false when the property is
+// missing, holds another type or does not fit in an int.
+func (p *Properties) GetInt(key string) (value int, ok bool) {
+	if n, isNumber := p.Properties[key].(json.Number); isNumber {
+		i, err := n.Int64()
+		return int(i), err == nil && int64(int(i)) == i
+	}
+
+	v := reflect.ValueOf(p.Properties[key])
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), int64(int(v.Int())) == v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint()), v.Uint() <= math.MaxInt
+	default:
+		return 0, false
+	}
+}
+
+// GetStringSlice returns a copy of the property key when it holds a slice of
+// strings, either as a []string or as a []interface{} such as produced by JSON
+// decoding. ok is false when the property is missing or holds another type.
+func (p *Properties) GetStringSlice(key string) (values []string, ok bool) {
+	switch v := p.Properties[key].(type) {
+	case []string:
+		return append([]string{}, v...), true
+	case []interface{}:
+		values = make([]string, 0, len(v))
+		for _, element := range v {
+			s, isString := element.(string)
+			if !isString {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
+
+// GetIntSlice returns a copy of the property key when it holds a slice of ints,
+// either as a []int or as a []interface{} such as produced by JSON decoding.
+// The elements of a []interface{} may be ints, or float64 or json.Number values
+// holding an integer that fits in an int. ok is false when the property is
+// missing or holds another type.
+func (p *Properties) GetIntSlice(key string) (values []int, ok bool) {
+	switch v := p.Properties[key].(type) {
+	case []int:
+		return append([]int{}, v...), true
+	case []interface{}:
+		values = make([]int, 0, len(v))
+		for _, element := range v {
+			i, isInt := intElement(element)
+			if !isInt {
+				return nil, false
+			}
+			values = append(values, i)
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
+
+// intElement converts an element of a decoded JSON array to an int, accepting
+// ints and integer-valued float64 and json.Number values within the int range.
+func intElement(element interface{}) (int, bool) {
+	switch e := element.(type) {
+	case int:
+		return e, true
+	case json.Number:
+		if i, err := e.Int64(); err == nil {
+			return int(i), int64(int(i)) == i
+		}
+		f, err := e.Float64()
+		if err != nil {
+			return 0, false
+		}
+		return intElement(f)
+	case float64:
+		if e != math.Trunc(e) || e < math.MinInt || e >= math.MaxInt {
+			return 0, false
+		}
+		return int(e), true
+	default:
+		return 0, false
+	}
+}
+
 func (p *Properties) RemoveProperty(key string) {
 	delete(p.Properties, key)
 }
 
+// RenameKey moves the value of oldKey to newKey. It returns false, leaving the
+// properties unchanged, if oldKey does not exist or newKey already exists.
+func (p *Properties) RenameKey(oldKey, newKey string) bool {
+	if oldKey != newKey && p.HasProperty(newKey) {
+		return false
+	}
+	return p.RenameKeyOverwrite(oldKey, newKey)
+}
+
+// RenameKeyOverwrite moves the value of oldKey to newKey, replacing the value
+// of newKey if it exists. It returns false if oldKey does not exist.
+func (p *Properties) RenameKeyOverwrite(oldKey, newKey string) bool {
+	value, exists := p.Properties[oldKey]
+	if !exists {
+		return false
+	}
+	delete(p.Properties, oldKey)
+	p.Properties[newKey] = value
+	return true
+}
+
 func (p *Properties) HasProperty(key string) bool {
 	_, exists := p.Properties[key]
 	return exists
@@ -65,6 +204,21 @@ func (p *Properties) GetAllProperties() map[string]interface{} {
 	return result
 }
 
+// Clone returns a deep copy of the properties. Slice values are copied, so the
+// clone can be modified without affecting p.
+func (p *Properties) Clone() *Properties {
+	clone := NewProperties()
+	for key, value := range p.Properties {
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Slice && !v.IsNil() {
+			copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			reflect.Copy(copied, v)
+			value = copied.Interface()
+		}
+		clone.Properties[key] = value
+	}
+	return clone
+}
+
 // Clear removes all properties
 func (p *Properties) Clear() {
 	p.Properties = make(map[string]interface{})
@@ -93,11 +247,18 @@ func (p *Properties) IsPropertyValueValid(value interface{}) bool {
 
 // primitiveCategory classifies value into one of the OpenGraph primitive
 // categories ("string", "number", "boolean"). It returns "" when value is nil
-// or not a primitive (e.g. an object, slice, or array).
+// or not a primitive (e.g. an object, slice, or array). A json.Number, as
+// produced by a json.Decoder using UseNumber, is a number if it parses as one.
 func primitiveCategory(value interface{}) string {
 	if value == nil {
 		return ""
 	}
+	if n, ok := value.(json.Number); ok {
+		if _, err := n.Float64(); err != nil {
+			return ""
+		}
+		return "number"
+	}
 
 	switch reflect.TypeOf(value).Kind() {
 	case reflect.String:
@@ -137,6 +298,185 @@ func (p *Properties) ToDict() map[string]interface{} {
 	return p.GetAllProperties()
 }
 
+// ToJSONString encodes the properties as a JSON object
+func (p *Properties) ToJSONString() (string, error) {
+	data, err := json.Marshal(p.Properties)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode properties: %w", err)
+	}
+	return string(data), nil
+}
+
+// FromJSONString replaces the properties with those of the JSON object s.
+//
+// JSON numbers are decoded as int when they are integer-valued and as float64
+// otherwise, inside arrays as well. Values that are not valid property values
+// (null, nested objects, arrays of objects or of mixed types) are rejected, in
+// which case the properties are left unchanged.
+func (p *Properties) FromJSONString(s string) error {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(s)))
+	decoder.UseNumber()
+
+	var values map[string]interface{}
+	if err := decoder.Decode(&values); err != nil {
+		return fmt.Errorf("failed to parse properties: %w", err)
+	}
+	if values == nil {
+		return fmt.Errorf("properties must be a JSON object")
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("failed to parse properties: unexpected data after the JSON object")
+	}
+
+	for key, value := range values {
+		converted, err := fromJSONValue(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for property '%s': %w", key, err)
+		}
+		if !p.IsPropertyValueValid(converted) {
+			return fmt.Errorf("invalid value for property '%s': %v", key, value)
+		}
+		values[key] = converted
+	}
+
+	p.Properties = values
+	return nil
+}
+
+// FromMap replaces the properties with a copy of values, as produced by
+// ToDict or decoded from JSON.
+//
+// json.Number values are converted like FromJSONString converts them. Values
+// that are not valid property values are rejected, in which case the
+// properties are left unchanged.
+func (p *Properties) FromMap(values map[string]interface{}) error {
+	converted := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		c, err := p.convertValue(key, value)
+		if err != nil {
+			return err
+		}
+		converted[key] = c
+	}
+
+	p.Properties = converted
+	return nil
+}
+
+// Patch sets every key of patch to its value, as SetProperty does, skipping
+// the invalid values instead of panicking. json.Number values are converted
+// like FromJSONString converts them, so maps decoded from JSON can be applied
+// directly.
+//
+// It returns one error per invalid value, in key order, or an empty slice if
+// every value was applied.
+func (p *Properties) Patch(patch map[string]interface{}) []error {
+	errs := make([]error, 0)
+	for _, key := range sortedKeys(patch) {
+		converted, err := p.convertValue(key, patch[key])
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		p.Properties[key] = converted
+	}
+	return errs
+}
+
+// PatchStrict is like Patch but applies patch only if every value is valid. It
+// returns the error for the first invalid value in key order, leaving the
+// properties unchanged.
+func (p *Properties) PatchStrict(patch map[string]interface{}) error {
+	converted := make(map[string]interface{}, len(patch))
+	for _, key := range sortedKeys(patch) {
+		c, err := p.convertValue(key, patch[key])
+		if err != nil {
+			return err
+		}
+		converted[key] = c
+	}
+
+	for key, value := range converted {
+		p.Properties[key] = value
+	}
+	return nil
+}
+
+// convertValue converts the json.Number values of value with fromJSONValue and
+// checks that the result is a valid property value for key.
+func (p *Properties) convertValue(key string, value interface{}) (interface{}, error) {
+	converted, err := fromJSONValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for property '%s': %w", key, err)
+	}
+	if !p.IsPropertyValueValid(converted) {
+		return nil, fmt.Errorf("invalid value for property '%s': %v", key, value)
+	}
+	return converted, nil
+}
+
+// sortedKeys returns the keys of values in sorted order.
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// MarshalJSON encodes the properties as a flat JSON object, the same as
+// ToDict, rather than as a struct wrapping the map.
+func (p *Properties) MarshalJSON() ([]byte, error) {
+	if p.Properties == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(p.Properties)
+}
+
+// UnmarshalJSON replaces the properties with those of a flat JSON object, with
+// numbers decoded as in FromJSONString. A JSON null leaves the properties
+// unchanged.
+func (p *Properties) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	return p.FromJSONString(string(data))
+}
+
+// fromJSONValue converts the json.Number values produced by a decoder using
+// UseNumber to int or float64.
+func fromJSONValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case json.Number:
+		// Parse integers exactly first: going through float64 would round
+		// those beyond 2^53.
+		if i, err := v.Int64(); err == nil && int64(int(i)) == i {
+			return int(i), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		if f == math.Trunc(f) && f >= math.MinInt && f < math.MaxInt {
+			return int(f), nil
+		}
+		return f, nil
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, element := range v {
+			c, err := fromJSONValue(element)
+			if err != nil {
+				return nil, err
+			}
+			converted[i] = c
+		}
+		return converted, nil
+	default:
+		return value, nil
+	}
+}
+
 // Len returns the number of properties
 func (p *Properties) Len() int {
 	return len(p.Properties)
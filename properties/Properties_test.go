@@ -1,7 +1,9 @@
 package properties_test
 
 import (
+	"encoding/json"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/TheManticoreProject/gopengraph/properties"
@@ -48,6 +50,8 @@ func TestSetProperty(t *testing.T) {
 		{"bool", true},
 		{"string-slice", []string{"a", "b", "c"}},
 		{"int-slice", []int{1, 2, 3}},
+		{"interface-slice", []interface{}{"a", "b"}},
+		{"empty-interface-slice", []interface{}{}},
 	}
 
 	for _, test := range validTests {
@@ -75,6 +79,9 @@ func TestSetProperty(t *testing.T) {
 		func() {},                             // function
 		[]map[string]string{{"key": "value"}}, // array of objects
 		[]interface{}{1, "a"},                 // array mixing primitive types
+		[]interface{}{map[string]int{"a": 1}}, // array holding an object
+		[]interface{}{"a", struct{}{}},        // array holding a struct
+		[]interface{}{"a", nil},               // array holding null
 	}
 
 	for _, invalidValue := range invalidTests {
@@ -315,3 +322,439 @@ func BenchmarkHasProperty(b *testing.B) {
 		p.HasProperty("key")
 	}
 }
+
+func TestJSONStringRoundTrip(t *testing.T) {
+	p := properties.NewPropertiesFromMap(map[string]interface{}{
+		"name":    "alice",
+		"logons":  42,
+		"enabled": true,
+		"score":   3.5,
+		"groups":  []string{"admins", "users"},
+	})
+
+	data, err := p.ToJSONString()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	decoded := properties.NewProperties()
+	if err := decoded.FromJSONString(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"name":    "alice",
+		"logons":  42,
+		"enabled": true,
+		"score":   3.5,
+		"groups":  []interface{}{"admins", "users"},
+	}
+	if got := decoded.GetAllProperties(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+}
+
+func TestFromJSONStringNumbers(t *testing.T) {
+	p := properties.NewProperties()
+	if err := p.FromJSONString(`{"a": 1, "b": 2.0, "c": 2.5, "d": [1, 2]}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := p.GetProperty("a"); got != 1 {
+		t.Errorf("expected int 1, got %#v", got)
+	}
+	if got := p.GetProperty("b"); got != 2 {
+		t.Errorf("expected integer-valued float to decode as int 2, got %#v", got)
+	}
+	if got := p.GetProperty("c"); got != 2.5 {
+		t.Errorf("expected float64 2.5, got %#v", got)
+	}
+	if got := p.GetProperty("d"); !reflect.DeepEqual(got, []interface{}{1, 2}) {
+		t.Errorf("expected []interface{}{1, 2}, got %#v", got)
+	}
+}
+
+func TestFromJSONStringIntRange(t *testing.T) {
+	p := properties.NewProperties()
+	if err := p.FromJSONString(`{"big": 1099511627776, "huge": 1e300}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 2^40 only fits in a 64-bit int; on 32-bit platforms it stays a float64
+	// rather than wrapping.
+	big := int64(1) << 40
+	var expected interface{} = float64(big)
+	if strconv.IntSize == 64 {
+		expected = int(big)
+	}
+	if got := p.GetProperty("big"); got != expected {
+		t.Errorf("expected %#v, got %#v", expected, got)
+	}
+	if got := p.GetProperty("huge"); got != 1e300 {
+		t.Errorf("expected float64 1e300, got %#v", got)
+	}
+}
+
+func TestJSONRoundTripLargeInt(t *testing.T) {
+	if strconv.IntSize != 64 {
+		t.Skip("integers beyond 2^53 only fit in a 64-bit int")
+	}
+	// 2^53 + 1 is the smallest positive integer that float64 cannot represent.
+	const input = `{"x":9007199254740993}`
+	big := int64(1)<<53 + 1
+	expected := int(big)
+
+	p := properties.NewProperties()
+	if err := p.FromJSONString(input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.GetProperty("x"); got != expected {
+		t.Errorf("expected %d, got %#v", expected, got)
+	}
+
+	var decoded properties.Properties
+	if err := json.Unmarshal([]byte(input), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := decoded.GetProperty("x"); got != expected {
+		t.Errorf("expected %d, got %#v", expected, got)
+	}
+
+	data, err := json.Marshal(&decoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != input {
+		t.Errorf("expected %s, got %s", input, data)
+	}
+}
+
+func TestFromJSONStringInvalid(t *testing.T) {
+	p := properties.NewPropertiesFromMap(map[string]interface{}{"name": "alice"})
+
+	for _, input := range []string{
+		`not json`,
+		`[1, 2]`,
+		`null`,
+		`{"nested": {"a": 1}}`,
+		`{"empty": null}`,
+		`{"mixed": [1, "a"]}`,
+		`{"a": 1} {"b": 2}`,
+		`{"a": 1} trailing`,
+		`{"a": 1}}`,
+	} {
+		if err := p.FromJSONString(input); err == nil {
+			t.Errorf("expected an error for %s", input)
+		}
+	}
+
+	if p.Len() != 1 || p.GetProperty("name") != "alice" {
+		t.Errorf("expected properties to be unchanged, got %v", p)
+	}
+}
+
+func TestGetStringSlice(t *testing.T) {
+	p := properties.NewPropertiesFromMap(map[string]interface{}{
+		"typed":   []string{"a", "b"},
+		"generic": []interface{}{"c", "d"},
+		"ints":    []int{1, 2},
+		"name":    "alice",
+	})
+
+	if got, ok := p.GetStringSlice("typed"); !ok || !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("expected [a b], got %v (ok=%v)", got, ok)
+	}
+	if got, ok := p.GetStringSlice("generic"); !ok || !reflect.DeepEqual(got, []string{"c", "d"}) {
+		t.Errorf("expected [c d], got %v (ok=%v)", got, ok)
+	}
+	for _, key := range []string{"ints", "name", "missing"} {
+		if got, ok := p.GetStringSlice(key); ok {
+			t.Errorf("expected no string slice for %s, got %v", key, got)
+		}
+	}
+
+	// The returned slice is a copy.
+	got, _ := p.GetStringSlice("typed")
+	got[0] = "changed"
+	if again, _ := p.GetStringSlice("typed"); again[0] != "a" {
+		t.Error("expected modifying the returned slice not to affect the property")
+	}
+}
+
+func TestJSONNumberValues(t *testing.T) {
+	p := properties.NewProperties()
+	if !p.IsPropertyValueValid(json.Number("42")) {
+		t.Error("expected json.Number to be a valid value")
+	}
+	if p.IsPropertyValueValid(json.Number("forty-two")) {
+		t.Error("expected a malformed json.Number to be invalid")
+	}
+	if p.IsPropertyValueValid([]interface{}{json.Number("1"), "a"}) {
+		t.Error("expected json.Number and string to be a mixed array")
+	}
+
+	p.SetProperty("logons", json.Number("42"))
+	p.SetProperty("ratio", json.Number("0.5"))
+	p.SetProperty("ports", []interface{}{json.Number("80"), 443})
+
+	if got, ok := p.GetInt("logons"); !ok || got != 42 {
+		t.Errorf("expected 42, got %v (ok=%v)", got, ok)
+	}
+	if got, ok := p.GetFloat64("logons"); !ok || got != 42 {
+		t.Errorf("expected 42.0, got %v (ok=%v)", got, ok)
+	}
+	if got, ok := p.GetFloat64("ratio"); !ok || got != 0.5 {
+		t.Errorf("expected 0.5, got %v (ok=%v)", got, ok)
+	}
+	if got, ok := p.GetInt("ratio"); ok {
+		t.Errorf("expected no int for a fractional json.Number, got %v", got)
+	}
+}
+
+func TestGetFloat64AndGetInt(t *testing.T) {
+	p := properties.NewPropertiesFromMap(map[string]interface{}{
+		"int":    7,
+		"uint8":  uint8(8),
+		"float":  2.5,
+		"string": "7",
+	})
+
+	if got, ok := p.GetInt("int"); !ok || got != 7 {
+		t.Errorf("expected 7, got %v (ok=%v)", got, ok)
+	}
+	if got, ok := p.GetInt("uint8"); !ok || got != 8 {
+		t.Errorf("expected 8, got %v (ok=%v)", got, ok)
+	}
+	if got, ok := p.GetFloat64("int"); !ok || got != 7 {
+		t.Errorf("expected 7.0, got %v (ok=%v)", got, ok)
+	}
+	if got, ok := p.GetFloat64("float"); !ok || got != 2.5 {
+		t.Errorf("expected 2.5, got %v (ok=%v)", got, ok)
+	}
+	for _, key := range []string{"float", "string", "missing"} {
+		if got, ok := p.GetInt(key); ok {
+			t.Errorf("expected no int for %s, got %v", key, got)
+		}
+	}
+	for _, key := range []string{"string", "missing"} {
+		if got, ok := p.GetFloat64(key); ok {
+			t.Errorf("expected no float64 for %s, got %v", key, got)
+		}
+	}
+}
+
+func TestGetIntSlice(t *testing.T) {
+	p := properties.NewPropertiesFromMap(map[string]interface{}{
+		"typed":   []int{1, 2},
+		"generic": []interface{}{3, 4},
+		"decoded": []interface{}{5.0, json.Number("6"), json.Number("7.0")},
+		"floats":  []float64{1.5},
+		"strings": []string{"a"},
+		"partial": []interface{}{1.0, 1.5},
+		"huge":    []interface{}{1e300},
+	})
+
+	if got, ok := p.GetIntSlice("typed"); !ok || !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("expected [1 2], got %v (ok=%v)", got, ok)
+	}
+	if got, ok := p.GetIntSlice("generic"); !ok || !reflect.DeepEqual(got, []int{3, 4}) {
+		t.Errorf("expected [3 4], got %v (ok=%v)", got, ok)
+	}
+	if got, ok := p.GetIntSlice("decoded"); !ok || !reflect.DeepEqual(got, []int{5, 6, 7}) {
+		t.Errorf("expected [5 6 7], got %v (ok=%v)", got, ok)
+	}
+	for _, key := range []string{"floats", "strings", "partial", "huge", "missing"} {
+		if got, ok := p.GetIntSlice(key); ok {
+			t.Errorf("expected no int slice for %s, got %v", key, got)
+		}
+	}
+}
+
+func TestSliceJSONRoundTrip(t *testing.T) {
+	p := properties.NewPropertiesFromMap(map[string]interface{}{
+		"groups": []string{"a", "b"},
+		"ports":  []int{80, 443},
+	})
+
+	data, err := p.ToJSONString()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded := properties.NewProperties()
+	if err := decoded.FromJSONString(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, ok := decoded.GetStringSlice("groups"); !ok || !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("expected [a b], got %v (ok=%v)", got, ok)
+	}
+	if got, ok := decoded.GetIntSlice("ports"); !ok || !reflect.DeepEqual(got, []int{80, 443}) {
+		t.Errorf("expected [80 443], got %v (ok=%v)", got, ok)
+	}
+}
+
+func TestClone(t *testing.T) {
+	p := properties.NewPropertiesFromMap(map[string]interface{}{
+		"name":   "alice",
+		"groups": []string{"a", "b"},
+	})
+
+	clone := p.Clone()
+	if !reflect.DeepEqual(clone.GetAllProperties(), p.GetAllProperties()) {
+		t.Fatalf("expected %v, got %v", p, clone)
+	}
+
+	clone.SetProperty("name", "bob")
+	clone.GetProperty("groups").([]string)[0] = "changed"
+	if p.GetProperty("name") != "alice" {
+		t.Error("expected modifying the clone not to affect the original value")
+	}
+	if got := p.GetProperty("groups").([]string)[0]; got != "a" {
+		t.Errorf("expected slices to be copied, got %s", got)
+	}
+}
+
+func TestRenameKey(t *testing.T) {
+	t.Run("renames an existing key", func(t *testing.T) {
+		p := properties.NewPropertiesFromMap(map[string]interface{}{"samaccountname": "alice", "enabled": true})
+		if !p.RenameKey("samaccountname", "name") {
+			t.Fatal("expected RenameKey to succeed")
+		}
+		if p.HasProperty("samaccountname") {
+			t.Error("expected the old key to be absent")
+		}
+		if p.GetProperty("name") != "alice" {
+			t.Errorf("expected name to be alice, got %v", p.GetProperty("name"))
+		}
+	})
+
+	t.Run("missing key changes nothing", func(t *testing.T) {
+		p := properties.NewPropertiesFromMap(map[string]interface{}{"name": "alice"})
+		if p.RenameKey("missing", "other") {
+			t.Error("expected RenameKey to fail for a missing key")
+		}
+		if p.RenameKeyOverwrite("missing", "name") {
+			t.Error("expected RenameKeyOverwrite to fail for a missing key")
+		}
+		if !reflect.DeepEqual(p.GetAllProperties(), map[string]interface{}{"name": "alice"}) {
+			t.Errorf("expected properties to be unchanged, got %v", p.GetAllProperties())
+		}
+	})
+
+	t.Run("existing new key", func(t *testing.T) {
+		p := properties.NewPropertiesFromMap(map[string]interface{}{"name": "alice", "displayname": "Alice"})
+		if p.RenameKey("displayname", "name") {
+			t.Error("expected RenameKey not to overwrite an existing key")
+		}
+		if p.GetProperty("name") != "alice" || p.GetProperty("displayname") != "Alice" {
+			t.Errorf("expected properties to be unchanged, got %v", p.GetAllProperties())
+		}
+
+		if !p.RenameKeyOverwrite("displayname", "name") {
+			t.Fatal("expected RenameKeyOverwrite to succeed")
+		}
+		if p.HasProperty("displayname") || p.GetProperty("name") != "Alice" {
+			t.Errorf("expected name to be overwritten, got %v", p.GetAllProperties())
+		}
+	})
+}
+
+func TestFromMap(t *testing.T) {
+	p := properties.NewProperties()
+	if err := p.FromMap(map[string]interface{}{"count": json.Number("3"), "ratio": 0.5, "tags": []interface{}{"a", "b"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v := p.GetProperty("count"); v != 3 {
+		t.Errorf("expected count 3 as int, got %v (%T)", v, v)
+	}
+
+	if err := p.FromMap(map[string]interface{}{"nested": map[string]interface{}{"a": 1}}); err == nil {
+		t.Error("expected error for a nested object, got nil")
+	}
+	if p.Len() != 3 {
+		t.Errorf("expected properties to be unchanged after an error, got %v", p.GetAllProperties())
+	}
+}
+
+func TestPatch(t *testing.T) {
+	p := properties.NewProperties()
+	p.SetProperty("name", "alice")
+
+	errs := p.Patch(map[string]interface{}{"name": "bob", "count": json.Number("2"), "tags": []string{"a"}})
+	if errs == nil || len(errs) != 0 {
+		t.Fatalf("expected an empty slice of errors, got %#v", errs)
+	}
+	want := map[string]interface{}{"name": "bob", "count": 2, "tags": []string{"a"}}
+	if got := p.GetAllProperties(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	errs = p.Patch(map[string]interface{}{"enabled": true, "nested": map[string]interface{}{"a": 1}, "owner": nil})
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if !p.HasProperty("enabled") || p.HasProperty("nested") || p.HasProperty("owner") {
+		t.Errorf("expected only the valid value to be applied, got %v", p.GetAllProperties())
+	}
+}
+
+func TestPatchStrict(t *testing.T) {
+	p := properties.NewProperties()
+	p.SetProperty("name", "alice")
+
+	if err := p.PatchStrict(map[string]interface{}{"name": "bob", "enabled": true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.GetProperty("name") != "bob" || p.GetProperty("enabled") != true {
+		t.Errorf("expected the patch to be applied, got %v", p.GetAllProperties())
+	}
+
+	if err := p.PatchStrict(map[string]interface{}{"name": "carol", "nested": []interface{}{1, "a"}}); err == nil {
+		t.Fatal("expected error for a mixed array, got nil")
+	}
+	if p.GetProperty("name") != "bob" {
+		t.Errorf("expected the properties to be unchanged after an error, got %v", p.GetAllProperties())
+	}
+}
+
+func TestPropertiesJSONCodec(t *testing.T) {
+	p := properties.NewProperties()
+	p.SetProperty("name", "alice")
+	p.SetProperty("logons", 3)
+	p.SetProperty("ratio", 0.5)
+	p.SetProperty("tags", []string{"a", "b"})
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The encoding matches that of ToDict, without a "Properties" wrapper.
+	dictData, _ := json.Marshal(p.ToDict())
+	if string(data) != string(dictData) {
+		t.Errorf("expected %s, got %s", dictData, data)
+	}
+
+	var decoded properties.Properties
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]interface{}{"name": "alice", "logons": 3, "ratio": 0.5, "tags": []interface{}{"a", "b"}}
+	if !reflect.DeepEqual(decoded.GetAllProperties(), want) {
+		t.Errorf("expected %v, got %v", want, decoded.GetAllProperties())
+	}
+
+	// Properties embedded in another struct are encoded flat too.
+	wrapped, err := json.Marshal(struct {
+		Props *properties.Properties `json:"props"`
+	}{p})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"props":` + string(dictData) + `}`; string(wrapped) != want {
+		t.Errorf("expected %s, got %s", want, wrapped)
+	}
+
+	if err := json.Unmarshal([]byte(`{"nested":{"a":1}}`), &decoded); err == nil {
+		t.Error("expected error for a nested object, got nil")
+	}
+}